@@ -57,6 +57,15 @@ type Application struct {
 	// be forwarded).
 	inputCapture func(event *tcell.EventKey) *tcell.EventKey
 
+	// The commands of the application, executed by their keyboard shortcuts.
+	commands *CommandRegistry
+
+	// The clipboard text is copied to and pasted from.
+	clipboard Clipboard
+
+	// The keys which stop the application.
+	quitKeys []string
+
 	// Time a resize event was last processed.
 	lastResize time.Time
 
@@ -112,6 +121,11 @@ type Application struct {
 	lastMouseClick          time.Time        // The time when a mouse button was last clicked.
 	lastMouseButtons        tcell.ButtonMask // The last mouse button state.
 
+	// Whether a bracketed paste is in progress, and the keys received since it
+	// started.
+	pasting   bool
+	pasteKeys []*tcell.EventKey
+
 	sync.RWMutex
 }
 
@@ -122,9 +136,52 @@ func NewApplication() *Application {
 		events:               make(chan tcell.Event, queueSize),
 		updates:              make(chan func(), queueSize),
 		screenReplacement:    make(chan tcell.Screen, 1),
+		commands:             NewCommandRegistry(),
+		clipboard:            NewMemoryClipboard(),
+		quitKeys:             []string{"Ctrl+C"},
 	}
 }
 
+// GetCommands returns the command registry of the application. Commands are
+// executed when one of their shortcuts is pressed, after the function set with
+// SetInputCapture and before the primitive which has focus receives the key.
+// Shortcuts should therefore include a modifier, such as Ctrl or Alt, to not
+// interfere with text input.
+func (a *Application) GetCommands() *CommandRegistry {
+	return a.commands
+}
+
+// SetClipboard sets the clipboard the selected text of the primitive which has
+// focus is copied to when the user presses one of the Copy or Cut shortcuts,
+// and pasted from when the user presses one of the Paste shortcuts (see Keys).
+// The default clipboard keeps the text in memory (see MemoryClipboard). Use
+// an OSC52Clipboard to also copy text to the clipboard of the terminal.
+func (a *Application) SetClipboard(clipboard Clipboard) {
+	a.Lock()
+	defer a.Unlock()
+
+	a.clipboard = clipboard
+}
+
+// GetClipboard returns the clipboard of the application.
+func (a *Application) GetClipboard() Clipboard {
+	a.RLock()
+	defer a.RUnlock()
+
+	return a.clipboard
+}
+
+// SetQuitKeys sets the keys which stop the application, e.g. "Ctrl+Q". The
+// default is "Ctrl+C". Provide no keys to not stop the application on a key.
+// If the primitive which has focus has selected text, copying it takes
+// precedence over stopping the application (see SetClipboard).
+func (a *Application) SetQuitKeys(keys ...string) {
+	a.Lock()
+	defer a.Unlock()
+
+	a.quitKeys = keys
+}
+
 // SetInputCapture sets a function which captures all key events before they are
 // forwarded to the key event handler of the primitive which currently has
 // focus. This function can then choose to forward that key event (or a
@@ -342,36 +399,26 @@ EventLoop:
 			}
 
 			a.RLock()
-			p := a.focus
-			inputCapture := a.inputCapture
 			screen := a.screen
 			a.RUnlock()
 
 			switch event := event.(type) {
 			case *tcell.EventKey:
-				// Intercept keys.
-				if inputCapture != nil {
-					event = inputCapture(event)
-					if event == nil {
-						a.draw()
-						continue // Don't forward event.
-					}
-				}
-
-				// Ctrl-C closes the application.
-				if event.Key() == tcell.KeyCtrlC {
-					a.Stop()
+				// Collect the keys of a bracketed paste.
+				if a.pasting {
+					a.pasteKeys = append(a.pasteKeys, event)
+					continue
 				}
 
-				// Pass other key events to the currently focused primitive.
-				if p != nil {
-					if handler := p.InputHandler(); handler != nil {
-						handler(event, func(p Primitive) {
-							a.SetFocus(p)
-						})
-						a.draw()
-					}
+				a.fireKey(event)
+			case *tcell.EventPaste:
+				if event.Start() {
+					a.pasting, a.pasteKeys = true, nil
+					continue
 				}
+				keys := a.pasteKeys
+				a.pasting, a.pasteKeys = false, nil
+				a.firePaste(keys)
 			case *tcell.EventResize:
 				// Throttle resize events.
 				if time.Since(a.lastResize) < resizeEventThrottle {
@@ -417,6 +464,11 @@ EventLoop:
 				if isMouseDownAction {
 					a.mouseDownX, a.mouseDownY = event.Position()
 				}
+			case *tcell.EventInterrupt:
+				// Execute functions queued in order with other events.
+				if f, ok := event.Data().(func()); ok {
+					f()
+				}
 			}
 
 		// If we have updates, now is the time to execute them.
@@ -432,6 +484,129 @@ EventLoop:
 	return nil
 }
 
+// fireKey passes a key event to the input capture function, the commands
+// bound to the key and the primitive which has focus.
+func (a *Application) fireKey(event *tcell.EventKey) {
+	a.RLock()
+	p := a.focus
+	inputCapture := a.inputCapture
+	quitKeys := a.quitKeys
+	a.RUnlock()
+
+	// Intercept keys.
+	if inputCapture != nil {
+		event = inputCapture(event)
+		if event == nil {
+			a.draw()
+			return // Don't forward event.
+		}
+	}
+
+	// Copy, cut and paste text.
+	if a.fireClipboardKey(p, event) {
+		a.draw()
+		return
+	}
+
+	// The quit keys close the application.
+	if HitShortcut(event, quitKeys) {
+		a.Stop()
+	}
+
+	// Execute commands bound to the key.
+	if a.commands.HandleKey(event) {
+		a.draw()
+		return
+	}
+
+	// Pass other key events to the currently focused primitive.
+	if p != nil {
+		if handler := p.InputHandler(); handler != nil {
+			handler(event, func(p Primitive) {
+				a.SetFocus(p)
+			})
+			a.draw()
+		}
+	}
+}
+
+// fireClipboardKey copies or cuts the selected text of the primitive which has
+// focus to the clipboard, or pastes the text of the clipboard into it, if the
+// key is one of the shortcuts to do so. It returns whether the key was
+// handled.
+func (a *Application) fireClipboardKey(p Primitive, event *tcell.EventKey) bool {
+	a.RLock()
+	clipboard := a.clipboard
+	a.RUnlock()
+	if clipboard == nil || isNilPrimitive(p) {
+		return false
+	}
+
+	if cut := HitShortcut(event, Keys.Cut); cut || HitShortcut(event, Keys.Copy) {
+		copyable, ok := p.(Copyable)
+		if !ok {
+			return false
+		}
+		text, ok := copyable.CopyHandler()(cut)
+		if ok {
+			clipboard.SetText(text)
+		}
+		return ok
+	} else if HitShortcut(event, Keys.Paste) {
+		text := clipboard.GetText()
+		return text != "" && a.pasteText(p, text)
+	}
+	return false
+}
+
+// firePaste passes the text of a bracketed paste, given by the keys received
+// between its start and its end, to the primitive which has focus if it
+// implements Pastable. Otherwise, the keys are passed on one by one.
+func (a *Application) firePaste(keys []*tcell.EventKey) {
+	var text []rune
+	for index, event := range keys {
+		switch event.Key() {
+		case tcell.KeyRune:
+			text = append(text, event.Rune())
+		case tcell.KeyEnter:
+			text = append(text, '\n')
+		case tcell.KeyLF:
+			if index == 0 || keys[index-1].Key() != tcell.KeyEnter {
+				text = append(text, '\n') // Not part of "\r\n".
+			}
+		case tcell.KeyTab:
+			text = append(text, '\t')
+		}
+	}
+
+	a.RLock()
+	p := a.focus
+	a.RUnlock()
+	if !a.pasteText(p, string(text)) {
+		for _, event := range keys {
+			a.fireKey(event)
+		}
+	}
+}
+
+// pasteText passes pasted text to a primitive and returns true if it
+// implements Pastable. Otherwise, it returns false.
+func (a *Application) pasteText(p Primitive, text string) bool {
+	pastable, ok := p.(Pastable)
+	if !ok || isNilPrimitive(p) {
+		return false
+	}
+	handler := pastable.PasteHandler()
+	if handler == nil {
+		return false
+	}
+	handler(text, func(p Primitive) {
+		a.SetFocus(p)
+	})
+	a.draw()
+	return true
+}
+
 // fireMouseActions analyzes the provided mouse event, derives mouse actions
 // from it and then forwards them to the corresponding primitives.
 func (a *Application) fireMouseActions(event *tcell.EventMouse) (consumed, isMouseDownAction bool) {
@@ -454,9 +629,15 @@ func (a *Application) fireMouseActions(event *tcell.EventMouse) (consumed, isMou
 			}
 		}
 
+		// While dragging, the primitive under the mouse receives the events.
+		dragging := GetDrag() != nil
+		if dragging && action == MouseMove {
+			setDropTarget(nil)
+		}
+
 		// Determine the target primitive.
 		var primitive, capturingPrimitive Primitive
-		if a.mouseCapturingPrimitive != nil {
+		if a.mouseCapturingPrimitive != nil && !dragging {
 			primitive = a.mouseCapturingPrimitive
 			targetPrimitive = a.mouseCapturingPrimitive
 		} else if targetPrimitive != nil {
@@ -476,6 +657,11 @@ func (a *Application) fireMouseActions(event *tcell.EventMouse) (consumed, isMou
 			}
 		}
 		a.mouseCapturingPrimitive = capturingPrimitive
+
+		// Releasing the button ends dragging.
+		if dragging && action == MouseLeftUp {
+			CancelDrag()
+		}
 	}
 
 	x, y := event.Position()
@@ -699,6 +885,34 @@ func (a *Application) SetRoot(root Primitive, fullscreen bool) {
 	a.SetFocus(root)
 }
 
+// SetTheme replaces Styles (and Borders, if the theme defines them) with the
+// given theme and applies it to all primitives of the root primitive's tree.
+// Colors of existing primitives are only replaced where they match the colors
+// of the previous theme, so colors which were set explicitly are kept. Note
+// that an explicit color which is identical to the previous theme's color is
+// replaced as well.
+//
+// The theme is set in the event loop. Primitives created after that use the
+// new theme. Primitives which are not part of the tree (e.g. hidden windows
+// kept elsewhere) may be themed by calling ApplyTheme.
+func (a *Application) SetTheme(theme *Theme) {
+	// The theme is replaced and applied in the event loop, which draws with
+	// the globals and whose callbacks may hold the primitives' locks.
+	a.QueueUpdateDraw(func() {
+		previous := Styles
+		Styles = *theme
+		if theme.Borders != (BorderSet{}) {
+			Borders = theme.Borders
+		}
+
+		a.RLock()
+		root := a.root
+		a.RUnlock()
+
+		ApplyTheme(root, &previous, theme)
+	})
+}
+
 // ResizeToFullScreen resizes the given primitive such that it fills the entire
 // screen.
 func (a *Application) ResizeToFullScreen(p Primitive) {
@@ -825,6 +1039,9 @@ func (a *Application) QueueUpdateDraw(f func()) {
 
 // QueueEvent sends an event to the Application event loop.
 //
+// A *tcell.EventInterrupt carrying a func() as its data is executed by the
+// event loop once all events queued before it have been processed.
+//
 // It is not recommended for event to be nil.
 func (a *Application) QueueEvent(event tcell.Event) {
 	a.events <- event
@@ -6,9 +6,8 @@ const (
 	BorderHeavy
 )
 
-// Borders defines various borders used when primitives are drawn.
-// These may be changed to accommodate a different look and feel.
-var Borders = struct {
+// BorderSet defines the runes used to draw borders.
+type BorderSet struct {
 	Horizontal  rune
 	Vertical    rune
 	TopLeft     rune
@@ -28,7 +27,11 @@ var Borders = struct {
 	TopRightFocus    rune
 	BottomLeftFocus  rune
 	BottomRightFocus rune
-}{
+}
+
+// Borders defines various borders used when primitives are drawn.
+// These may be changed to accommodate a different look and feel.
+var Borders = BorderSet{
 	Horizontal:  BoxDrawingsLightHorizontal,
 	Vertical:    BoxDrawingsLightVertical,
 	TopLeft:     BoxDrawingsLightDownAndRight,
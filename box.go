@@ -83,6 +83,9 @@ type Box struct {
 	// least one nil if nothing should be forwarded).
 	mouseCapture func(action MouseAction, event *tcell.EventMouse) (MouseAction, *tcell.EventMouse)
 
+	// Keyboard shortcuts used instead of Keys, if set.
+	keys *Key
+
 	l sync.RWMutex
 }
 
@@ -288,6 +291,33 @@ func (b *Box) GetInputCapture() func(event *tcell.EventKey) *tcell.EventKey {
 	return b.inputCapture
 }
 
+// SetKeys overrides the keyboard shortcuts of this primitive. Provide nil to
+// use the global shortcuts in Keys. To disable a single shortcut, copy Keys
+// and clear it:
+//
+//	keys := Keys
+//	keys.MoveUp2, keys.MoveDown2 = nil, nil
+//	table.SetKeys(&keys)
+func (b *Box) SetKeys(keys *Key) *Box {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	b.keys = keys
+	return b
+}
+
+// GetKeys returns the keyboard shortcuts of this primitive: the shortcuts set
+// with SetKeys, or Keys if none were set.
+func (b *Box) GetKeys() *Key {
+	b.l.RLock()
+	defer b.l.RUnlock()
+
+	if b.keys != nil {
+		return b.keys
+	}
+	return &Keys
+}
+
 // WrapMouseHandler wraps a mouse event handler (see MouseHandler()) with the
 // functionality to capture mouse events (see SetMouseCapture()) before passing
 // them on to the provided (default) event handler.
@@ -670,3 +700,21 @@ func (b *Box) GetFocusable() Focusable {
 
 	return b.focus
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme. Colors which were set explicitly are kept.
+func (b *Box) ApplyTheme(previous, theme *Theme) {
+	b.applyTheme(previous.PrimitiveBackgroundColor, theme.PrimitiveBackgroundColor, previous, theme)
+}
+
+// applyTheme applies a theme to the box of a primitive which uses a different
+// default background color.
+func (b *Box) applyTheme(previousBackground, background tcell.Color, previous, theme *Theme) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	themeColor(&b.backgroundColor, previousBackground, background)
+	themeColor(&b.borderColor, previous.BorderColor, theme.BorderColor)
+	themeColor(&b.borderColorFocused, previous.BorderColor, theme.BorderColor)
+	themeColor(&b.titleColor, previous.TitleColor, theme.TitleColor)
+}
@@ -164,12 +164,14 @@ func (b *Button) Draw(screen tcell.Screen) {
 // InputHandler returns the handler for this primitive.
 func (b *Button) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return b.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		keys := b.GetKeys()
+
 		// Process key event.
-		if HitShortcut(event, Keys.Select, Keys.Select2) {
+		if HitShortcut(event, keys.Select, keys.Select2) {
 			if b.selected != nil {
 				b.selected()
 			}
-		} else if HitShortcut(event, Keys.Cancel, Keys.MovePreviousField, Keys.MoveNextField) {
+		} else if HitShortcut(event, keys.Cancel, keys.MovePreviousField, keys.MoveNextField) {
 			if b.blur != nil {
 				b.blur(event.Key())
 			}
@@ -196,3 +198,16 @@ func (b *Button) MouseHandler() func(action MouseAction, event *tcell.EventMouse
 		return
 	})
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme. Colors which were set explicitly are kept.
+func (b *Button) ApplyTheme(previous, theme *Theme) {
+	b.Box.applyTheme(previous.ContrastBackgroundColor, theme.ContrastBackgroundColor, previous, theme)
+
+	b.Lock()
+	defer b.Unlock()
+
+	themeColor(&b.labelColor, previous.PrimaryTextColor, theme.PrimaryTextColor)
+	themeColor(&b.labelColorFocused, previous.InverseTextColor, theme.InverseTextColor)
+	themeColor(&b.backgroundColorFocused, previous.PrimaryTextColor, theme.PrimaryTextColor)
+}
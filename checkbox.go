@@ -305,14 +305,16 @@ func (c *CheckBox) Draw(screen tcell.Screen) {
 // InputHandler returns the handler for this primitive.
 func (c *CheckBox) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return c.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
-		if HitShortcut(event, Keys.Select, Keys.Select2) {
+		keys := c.GetKeys()
+
+		if HitShortcut(event, keys.Select, keys.Select2) {
 			c.Lock()
 			c.checked = !c.checked
 			c.Unlock()
 			if c.changed != nil {
 				c.changed(c.checked)
 			}
-		} else if HitShortcut(event, Keys.Cancel, Keys.MovePreviousField, Keys.MoveNextField) {
+		} else if HitShortcut(event, keys.Cancel, keys.MovePreviousField, keys.MoveNextField) {
 			if c.done != nil {
 				c.done(event.Key())
 			}
@@ -345,3 +347,17 @@ func (c *CheckBox) MouseHandler() func(action MouseAction, event *tcell.EventMou
 		return
 	})
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme. Colors which were set explicitly are kept.
+func (c *CheckBox) ApplyTheme(previous, theme *Theme) {
+	c.Box.ApplyTheme(previous, theme)
+
+	c.Lock()
+	defer c.Unlock()
+
+	themeColor(&c.labelColor, previous.SecondaryTextColor, theme.SecondaryTextColor)
+	themeColor(&c.fieldBackgroundColor, previous.ContrastBackgroundColor, theme.ContrastBackgroundColor)
+	themeColor(&c.fieldTextColor, previous.PrimaryTextColor, theme.PrimaryTextColor)
+	themeRune(&c.checkedRune, previous.CheckBoxCheckedRune, theme.CheckBoxCheckedRune)
+}
@@ -0,0 +1,92 @@
+package crtview
+
+import (
+	"encoding/base64"
+	"io"
+	"os"
+	"sync"
+)
+
+// Clipboard stores the text the user copies or cuts, and provides the text
+// the user pastes. See Application.SetClipboard.
+type Clipboard interface {
+	// SetText stores text copied or cut by the user.
+	SetText(text string)
+
+	// GetText returns the text to paste.
+	GetText() string
+}
+
+// Copyable is implemented by primitives whose selected text may be copied to
+// the clipboard. The application copies the text of the primitive which has
+// focus when the user presses one of the Copy or Cut shortcuts (see Keys).
+// Text is pasted with the primitive's PasteHandler (see Pastable).
+type Copyable interface {
+	// CopyHandler returns a handler which returns the selected text. If cut is
+	// true, the handler also deletes the selected text. It returns false if
+	// no text is selected, or if the text may not be cut, in which case the
+	// key is passed on to the primitive.
+	CopyHandler() func(cut bool) (text string, ok bool)
+}
+
+// MemoryClipboard is a clipboard which keeps its text in memory. It is shared
+// by the primitives of an application but not with other applications. It is
+// the default clipboard of an application.
+type MemoryClipboard struct {
+	text string
+
+	sync.RWMutex
+}
+
+// NewMemoryClipboard returns a new clipboard which keeps its text in memory.
+func NewMemoryClipboard() *MemoryClipboard {
+	return &MemoryClipboard{}
+}
+
+// SetText stores text copied or cut by the user.
+func (c *MemoryClipboard) SetText(text string) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.text = text
+}
+
+// GetText returns the text which was copied or cut last.
+func (c *MemoryClipboard) GetText() string {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.text
+}
+
+// OSC52Clipboard is a clipboard which also sends copied text to the clipboard
+// of the terminal, using the OSC 52 escape sequence. This works over SSH if
+// the terminal supports it. Terminals are rarely allowed to report their
+// clipboard, so the text which was copied last in the application is pasted.
+// Text pasted into the terminal is received through bracketed paste (see
+// Application.EnableBracketedPaste).
+type OSC52Clipboard struct {
+	MemoryClipboard
+
+	// The terminal the escape sequence is written to.
+	terminal io.Writer
+}
+
+// NewOSC52Clipboard returns a new clipboard which writes OSC 52 escape
+// sequences to the given terminal, or to the standard output if it is nil.
+func NewOSC52Clipboard(terminal io.Writer) *OSC52Clipboard {
+	if terminal == nil {
+		terminal = os.Stdout
+	}
+	return &OSC52Clipboard{terminal: terminal}
+}
+
+// SetText stores text copied or cut by the user and sends it to the clipboard
+// of the terminal.
+func (c *OSC52Clipboard) SetText(text string) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.text = text
+	io.WriteString(c.terminal, "\x1b]52;c;"+base64.StdEncoding.EncodeToString([]byte(text))+"\a")
+}
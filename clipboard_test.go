@@ -0,0 +1,107 @@
+package crtview
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// styleReversed returns whether a style is drawn inverted.
+func styleReversed(style tcell.Style) bool {
+	_, _, attributes := style.Decompose()
+	return attributes&tcell.AttrReverse != 0
+}
+
+func TestClipboard(t *testing.T) {
+	t.Parallel()
+
+	i := NewInputField()
+	i.SetText("hello world")
+	app, err := newTestApp(i)
+	if err != nil {
+		t.Fatalf("failed to initialize Application: %s", err)
+	} else if err = app.screen.Init(); err != nil {
+		t.Fatalf("failed to initialize screen: %s", err)
+	}
+	key := func(k tcell.Key, r rune, mod tcell.ModMask) {
+		app.fireKey(tcell.NewEventKey(k, r, mod))
+	}
+
+	// Copy selected text.
+	i.SetCursorPosition(0)
+	for n := 0; n < 5; n++ {
+		key(tcell.KeyRight, 0, tcell.ModShift)
+	}
+	if selected := i.GetSelectedText(); selected != "hello" {
+		t.Fatalf("failed to select text: expected hello, got %s", selected)
+	}
+	key(tcell.KeyCtrlC, 0, tcell.ModCtrl)
+	if text := app.GetClipboard().GetText(); text != "hello" {
+		t.Errorf("failed to copy text: expected hello, got %s", text)
+	} else if app.screen == nil {
+		t.Errorf("failed to copy text: application stopped")
+	}
+
+	// Cut selected text and paste it elsewhere.
+	key(tcell.KeyCtrlX, 0, tcell.ModCtrl)
+	if text := i.GetText(); text != " world" {
+		t.Errorf("failed to cut text: expected \" world\", got %q", text)
+	}
+	key(tcell.KeyEnd, 0, tcell.ModNone)
+	key(tcell.KeyCtrlV, 0, tcell.ModCtrl)
+	if text := i.GetText(); text != " worldhello" {
+		t.Errorf("failed to paste text: expected \" worldhello\", got %q", text)
+	}
+
+	// Copy selected text of a text view.
+	v := NewTextView()
+	v.SetDynamicColors(true)
+	v.SetText("[red]first[-] line\nsecond line")
+	v.SetScrollBarVisibility(ScrollBarNever)
+	app.SetRoot(v, false)
+	v.SetRect(0, 0, 20, 2)
+	v.Draw(app.screen)
+	v.MouseHandler()(MouseLeftDown, tcell.NewEventMouse(6, 0, tcell.Button1, tcell.ModNone), func(p Primitive) {})
+	v.MouseHandler()(MouseMove, tcell.NewEventMouse(6, 1, tcell.Button1, tcell.ModNone), func(p Primitive) {})
+	v.MouseHandler()(MouseLeftUp, tcell.NewEventMouse(6, 1, tcell.ButtonNone, tcell.ModNone), func(p Primitive) {})
+	if selected := v.GetSelectedText(); selected != "line\nsecond" {
+		t.Errorf("failed to select text with the mouse: expected line\\nsecond, got %q", selected)
+	}
+	v.Draw(app.screen)
+	if _, _, style, _ := app.screen.GetContent(6, 0); !styleReversed(style) {
+		t.Errorf("failed to draw selected text")
+	} else if _, _, style, _ = app.screen.GetContent(6, 1); styleReversed(style) {
+		t.Errorf("failed to draw text after selection")
+	}
+	key(tcell.KeyCtrlC, 0, tcell.ModCtrl)
+	if text := app.GetClipboard().GetText(); text != "line\nsecond" {
+		t.Errorf("failed to copy text: expected line\\nsecond, got %q", text)
+	}
+	key(tcell.KeyEscape, 0, tcell.ModNone)
+	key(tcell.KeyRight, 0, tcell.ModShift)
+	key(tcell.KeyDown, 0, tcell.ModShift)
+	if selected := v.GetSelectedText(); selected != "first line\ns" {
+		t.Errorf("failed to select text with keys: expected first line\\ns, got %q", selected)
+	}
+
+	// Quit without a selection.
+	v.ClearSelection()
+	app.SetQuitKeys("Ctrl+Q")
+	key(tcell.KeyCtrlC, 0, tcell.ModCtrl)
+	if app.screen == nil {
+		t.Fatalf("failed to change quit keys: application stopped")
+	}
+	key(tcell.KeyCtrlQ, 0, tcell.ModCtrl)
+	if app.screen != nil {
+		t.Errorf("failed to quit: application running")
+	}
+
+	// Send copied text to the terminal.
+	var terminal bytes.Buffer
+	c := NewOSC52Clipboard(&terminal)
+	c.SetText("hi")
+	if sequence := terminal.String(); sequence != "\x1b]52;c;aGk=\a" || c.GetText() != "hi" {
+		t.Errorf("failed to send text to terminal: got %q", sequence)
+	}
+}
@@ -0,0 +1,258 @@
+package crtview
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+// Command is a named action of an application.
+type Command struct {
+	// The unique name of the command.
+	Name string
+
+	// A short description of what the command does.
+	Description string
+
+	// Keyboard shortcuts which execute the command, in the format of cbind.
+	Shortcuts []string
+
+	// The function executed by the command.
+	Handler func()
+}
+
+// CommandRegistry holds the commands of an application. Commands may be
+// executed by name, by their keyboard shortcuts or from a CommandPalette.
+type CommandRegistry struct {
+	commands []*Command
+
+	sync.RWMutex
+}
+
+// NewCommandRegistry returns a new, empty command registry.
+func NewCommandRegistry() *CommandRegistry {
+	return &CommandRegistry{}
+}
+
+// Register adds a command. The shortcuts are keys in the format of cbind, as
+// used in Keys. An error is returned when the name is already registered, when
+// a shortcut is invalid or when a shortcut is bound to another command.
+func (r *CommandRegistry) Register(name, description string, handler func(), shortcuts ...string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.get(name) != nil {
+		return fmt.Errorf("command %q is already registered", name)
+	}
+
+	encoded := make([]string, 0, len(shortcuts))
+	for _, shortcut := range shortcuts {
+		key, err := normalizeKey(shortcut)
+		if err != nil {
+			return fmt.Errorf("invalid shortcut %q for command %q", shortcut, name)
+		}
+		for _, c := range r.commands {
+			if containsString(c.Shortcuts, key) {
+				return fmt.Errorf("shortcut %s of command %q is bound to command %q", key, name, c.Name)
+			}
+		}
+		encoded = append(encoded, key)
+	}
+
+	r.commands = append(r.commands, &Command{
+		Name:        name,
+		Description: description,
+		Shortcuts:   encoded,
+		Handler:     handler,
+	})
+	return nil
+}
+
+// Unregister removes a command.
+func (r *CommandRegistry) Unregister(name string) {
+	r.Lock()
+	defer r.Unlock()
+
+	for i, c := range r.commands {
+		if c.Name == name {
+			r.commands = append(r.commands[:i], r.commands[i+1:]...)
+			return
+		}
+	}
+}
+
+// Get returns the command with the given name, or nil if there is none.
+func (r *CommandRegistry) Get(name string) *Command {
+	r.RLock()
+	defer r.RUnlock()
+
+	return r.get(name)
+}
+
+func (r *CommandRegistry) get(name string) *Command {
+	for _, c := range r.commands {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// GetCommands returns all commands in the order they were registered.
+func (r *CommandRegistry) GetCommands() []*Command {
+	r.RLock()
+	defer r.RUnlock()
+
+	return append([]*Command(nil), r.commands...)
+}
+
+// Execute executes the command with the given name. It returns false if there
+// is no such command.
+func (r *CommandRegistry) Execute(name string) bool {
+	c := r.Get(name)
+	if c == nil {
+		return false
+	}
+	if c.Handler != nil {
+		c.Handler()
+	}
+	return true
+}
+
+// HandleKey executes the command bound to the key of the event, if any. It
+// returns whether a command was executed.
+func (r *CommandRegistry) HandleKey(event *tcell.EventKey) bool {
+	r.RLock()
+	var command *Command
+	for _, c := range r.commands {
+		if HitShortcut(event, c.Shortcuts) {
+			command = c
+			break
+		}
+	}
+	r.RUnlock()
+
+	if command == nil {
+		return false
+	}
+	if command.Handler != nil {
+		command.Handler()
+	}
+	return true
+}
+
+// Filter returns the commands whose name or description fuzzy-match the query,
+// best matches first. All characters of the query must appear in the name or
+// the description in the same order, ignoring case. All commands are returned
+// if the query is empty.
+func (r *CommandRegistry) Filter(query string) []*Command {
+	commands := r.GetCommands()
+	if query == "" {
+		return commands
+	}
+
+	type match struct {
+		command *Command
+		score   int
+	}
+	var matches []match
+	for _, c := range commands {
+		score, ok := fuzzyScore(c.Name, query)
+		if !ok {
+			// Matches in the description rank below matches in the name.
+			if score, ok = fuzzyScore(c.Description, query); ok {
+				score -= 1000
+			}
+		}
+		if ok {
+			matches = append(matches, match{c, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	filtered := make([]*Command, len(matches))
+	for i := range matches {
+		filtered[i] = matches[i].command
+	}
+	return filtered
+}
+
+// fuzzyScore returns whether all runes of the query appear in the text in the
+// same order, ignoring case, and a score which is higher for consecutive
+// matches and matches at the start of words.
+func fuzzyScore(text, query string) (int, bool) {
+	var (
+		q     = []rune(strings.ToLower(query))
+		score int
+		last  = -2
+		prev  rune
+		i     int
+	)
+	for index, r := range []rune(text) {
+		if i == len(q) {
+			break
+		}
+		if unicode.ToLower(r) == q[i] {
+			score++
+			if index == last+1 {
+				score += 5
+			}
+			if index == 0 || unicode.IsSpace(prev) || prev == '-' || prev == '_' || prev == '.' {
+				score += 10
+			}
+			last = index
+			i++
+		}
+		prev = r
+	}
+	if i < len(q) {
+		return 0, false
+	}
+	// Prefer shorter texts.
+	return score*100 - len(text), true
+}
+
+// Help returns a description of all commands with their shortcuts, one
+// command per line, suitable for a TextView.
+func (r *CommandRegistry) Help() string {
+	commands := r.GetCommands()
+
+	var nameWidth, shortcutsWidth int
+	for _, c := range commands {
+		if w := runewidth.StringWidth(c.Name); w > nameWidth {
+			nameWidth = w
+		}
+		if w := len(strings.Join(c.Shortcuts, ", ")); w > shortcutsWidth {
+			shortcutsWidth = w
+		}
+	}
+
+	var b bytes.Buffer
+	for _, c := range commands {
+		shortcuts := strings.Join(c.Shortcuts, ", ")
+		line := fmt.Sprintf("%s%s  %s%s  %s",
+			c.Name, strings.Repeat(" ", nameWidth-runewidth.StringWidth(c.Name)),
+			shortcuts, strings.Repeat(" ", shortcutsWidth-len(shortcuts)),
+			c.Description)
+		b.WriteString(strings.TrimRight(line, " "))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// NewCommandHelp returns a TextView listing all commands of a registry with
+// their shortcuts and descriptions. The text is generated when this function
+// is called.
+func NewCommandHelp(r *CommandRegistry) *TextView {
+	t := NewTextView()
+	t.SetText(r.Help())
+	return t
+}
@@ -0,0 +1,137 @@
+package crtview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestCommandRegistry(t *testing.T) {
+	t.Parallel()
+
+	var executed []string
+	handler := func(name string) func() {
+		return func() {
+			executed = append(executed, name)
+		}
+	}
+
+	r := NewCommandRegistry()
+	if err := r.Register("save", "Save the file", handler("save"), "ctrl+s"); err != nil {
+		t.Fatalf("failed to register command: %s", err)
+	}
+	if err := r.Register("quit", "Leave the application", handler("quit"), "Alt+Q"); err != nil {
+		t.Fatalf("failed to register command: %s", err)
+	}
+
+	for _, c := range []struct {
+		name      string
+		shortcuts []string
+		expected  string
+	}{
+		{"save", nil, `command "save" is already registered`},
+		{"write", []string{"Ctrl+S"}, `shortcut Ctrl+S of command "write" is bound to command "save"`},
+		{"write", []string{"Ctrl+Nope"}, `invalid shortcut "Ctrl+Nope" for command "write"`},
+	} {
+		err := r.Register(c.name, "", nil, c.shortcuts...)
+		if err == nil || err.Error() != c.expected {
+			t.Errorf("failed to reject command %s: expected %q, got %v", c.name, c.expected, err)
+		}
+	}
+
+	if !r.HandleKey(tcell.NewEventKey(tcell.KeyCtrlS, 0, tcell.ModCtrl)) {
+		t.Errorf("failed to handle shortcut: expected Ctrl+S to execute save")
+	}
+	if r.HandleKey(tcell.NewEventKey(tcell.KeyRune, 'q', tcell.ModNone)) {
+		t.Errorf("failed to ignore key: expected q to execute no command")
+	}
+	if !r.Execute("quit") || r.Execute("missing") {
+		t.Errorf("failed to execute commands by name")
+	}
+	if strings.Join(executed, ",") != "save,quit" {
+		t.Errorf("failed to execute commands: expected save,quit, got %s", strings.Join(executed, ","))
+	}
+
+	r.Unregister("quit")
+	if r.Get("quit") != nil || len(r.GetCommands()) != 1 {
+		t.Errorf("failed to unregister command")
+	}
+}
+
+func TestCommandRegistryFilter(t *testing.T) {
+	t.Parallel()
+
+	r := NewCommandRegistry()
+	for _, name := range []string{"file-open", "find", "format-document", "quit"} {
+		r.Register(name, "", nil)
+	}
+	r.Register("exit", "Quit the application", nil)
+
+	var names []string
+	for _, c := range r.Filter("fo") {
+		names = append(names, c.Name)
+	}
+	if strings.Join(names, ",") != "file-open,format-document" {
+		t.Errorf("failed to filter commands: expected file-open,format-document, got %s", strings.Join(names, ","))
+	}
+
+	names = nil
+	for _, c := range r.Filter("quit") {
+		names = append(names, c.Name)
+	}
+	if strings.Join(names, ",") != "quit,exit" {
+		t.Errorf("failed to filter by description: expected quit,exit, got %s", strings.Join(names, ","))
+	}
+
+	if len(r.Filter("")) != 5 {
+		t.Errorf("failed to list all commands: expected 5, got %d", len(r.Filter("")))
+	}
+}
+
+func TestCommandRegistryHelp(t *testing.T) {
+	t.Parallel()
+
+	r := NewCommandRegistry()
+	r.Register("save", "Save the file", nil, "Ctrl+S")
+	r.Register("palette", "Show all commands", nil, "Ctrl+P", "F1")
+	r.Register("about", "", nil)
+
+	expected := "save     Ctrl+S      Save the file\n" +
+		"palette  Ctrl+P, F1  Show all commands\n" +
+		"about\n"
+	if help := r.Help(); help != expected {
+		t.Errorf("failed to generate help: expected\n%s\ngot\n%s", expected, help)
+	}
+}
+
+func TestCommandPalette(t *testing.T) {
+	t.Parallel()
+
+	var executed string
+	r := NewCommandRegistry()
+	r.Register("open", "Open a file", func() { executed = "open" })
+	r.Register("quit", "Leave the application", func() { executed = "quit" })
+
+	var closed bool
+	p := NewCommandPalette(r)
+	p.SetDoneFunc(func() {
+		closed = true
+	})
+
+	handler := p.input.InputHandler()
+	handler(tcell.NewEventKey(tcell.KeyRune, 'q', tcell.ModNone), nil)
+	if commands := p.GetCommands(); len(commands) != 1 || commands[0].Name != "quit" {
+		t.Fatalf("failed to filter commands: expected quit, got %d commands", len(commands))
+	}
+
+	handler(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), nil)
+	if !closed || executed != "quit" {
+		t.Errorf("failed to execute command: expected quit, got %q", executed)
+	}
+
+	p.Reset()
+	if p.GetQuery() != "" || len(p.GetCommands()) != 2 {
+		t.Errorf("failed to reset palette: expected 2 commands, got %d", len(p.GetCommands()))
+	}
+}
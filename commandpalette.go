@@ -0,0 +1,173 @@
+package crtview
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// CommandPalette is a popup Window listing the commands of a CommandRegistry.
+// The list is filtered as the user types, best matches first. Enter executes
+// the selected command, Escape closes the palette without executing one.
+//
+// Add the palette to a WindowManager and show it with a command of its own:
+//
+//	palette := crtview.NewCommandPalette(app.GetCommands())
+//	palette.SetDoneFunc(func() {
+//		palette.Hide()
+//		app.SetFocus(root)
+//	})
+//	wm.Add(palette.Window)
+//	app.GetCommands().Register("palette", "Show all commands", func() {
+//		palette.Reset()
+//		palette.Show()
+//		app.SetFocus(palette)
+//	}, "Ctrl+P")
+type CommandPalette struct {
+	*Window
+
+	registry *CommandRegistry
+
+	// The query input and the filtered commands.
+	input *InputField
+	list  *List
+
+	// The commands currently listed.
+	commands []*Command
+
+	// An optional function which is called when the palette is closed, before
+	// the selected command (if any) is executed.
+	done func()
+
+	sync.RWMutex
+}
+
+// NewCommandPalette returns a new command palette listing the commands of the
+// given registry. The palette is hidden and centered initially.
+func NewCommandPalette(registry *CommandRegistry) *CommandPalette {
+	p := &CommandPalette{
+		registry: registry,
+		input:    NewInputField(),
+		list:     NewList(),
+	}
+
+	p.input.SetLabel("> ")
+	p.input.SetChangedFunc(func(text string) {
+		p.filter(text)
+	})
+	p.input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			p.execute()
+		case tcell.KeyEscape:
+			p.close(nil)
+		}
+	})
+	p.input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// Move through the list while typing. The focus stays on the input
+		// field.
+		switch event.Key() {
+		case tcell.KeyUp, tcell.KeyDown, tcell.KeyPgUp, tcell.KeyPgDn:
+			p.list.InputHandler()(event, func(Primitive) {})
+			return nil
+		}
+		return event
+	})
+
+	p.list.SetHighlightFullLine(true)
+	p.list.SetSelectedFunc(func(int, *ListItem) {
+		p.execute()
+	})
+
+	flex := NewFlex()
+	flex.SetDirection(FlexRow)
+	flex.AddItem(p.input, 1, 0, true)
+	flex.AddItem(p.list, 0, 1, false)
+
+	p.Window = NewWindow(flex)
+	p.Window.SetTitle("Commands")
+	p.Window.SetSize(60, 16)
+	p.Window.SetPositionCenter()
+	p.Window.Hide()
+
+	p.filter("")
+	return p
+}
+
+// SetDoneFunc sets a handler which is called when the palette is closed,
+// either because a command was executed or because it was cancelled. The
+// handler is typically used to hide the palette and restore the focus.
+func (p *CommandPalette) SetDoneFunc(handler func()) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.done = handler
+}
+
+// Reset clears the query and lists all commands of the registry again. Call
+// it before showing the palette to pick up newly registered commands.
+func (p *CommandPalette) Reset() {
+	p.input.SetText("")
+	p.filter("")
+}
+
+// GetQuery returns the text the commands are filtered by.
+func (p *CommandPalette) GetQuery() string {
+	return p.input.GetText()
+}
+
+// GetCommands returns the commands currently listed, in the order shown.
+func (p *CommandPalette) GetCommands() []*Command {
+	p.RLock()
+	defer p.RUnlock()
+
+	return append([]*Command(nil), p.commands...)
+}
+
+// filter lists the commands matching the query.
+func (p *CommandPalette) filter(query string) {
+	commands := p.registry.Filter(query)
+
+	p.Lock()
+	p.commands = commands
+	p.Unlock()
+
+	p.list.Clear()
+	for _, c := range commands {
+		text := c.Name
+		if len(c.Shortcuts) > 0 {
+			text += " (" + strings.Join(c.Shortcuts, ", ") + ")"
+		}
+		item := NewListItem(Escape(text))
+		item.SetSecondaryText(Escape(c.Description))
+		item.SetReference(c)
+		p.list.AddItem(item)
+	}
+	if len(commands) > 0 {
+		p.list.SetCurrentItem(0)
+	}
+}
+
+// execute closes the palette and executes the selected command.
+func (p *CommandPalette) execute() {
+	item := p.list.GetCurrentItem()
+	if item == nil {
+		return
+	}
+	p.close(item.GetReference().(*Command))
+}
+
+// close calls the done handler, then executes the command, if any.
+func (p *CommandPalette) close(c *Command) {
+	p.RLock()
+	done := p.done
+	p.RUnlock()
+
+	if done != nil {
+		done()
+	}
+	if c != nil && c.Handler != nil {
+		c.Handler()
+	}
+}
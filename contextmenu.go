@@ -1,6 +1,32 @@
 package crtview
 
-import "sync"
+import (
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// contextItemKind is the kind of a context menu item.
+type contextItemKind int
+
+// Context menu item kinds.
+const (
+	contextItemPlain contextItemKind = iota
+	contextItemCheck
+	contextItemRadio
+	contextItemSubmenu
+)
+
+// contextItem holds the state of a context menu item which is not kept by the
+// ListItem showing it.
+type contextItem struct {
+	text    string
+	kind    contextItemKind
+	group   string
+	checked bool
+	submenu *ContextMenu
+}
 
 // ContextMenu is a menu that appears upon user interaction, such as right
 // clicking or pressing Alt+Enter.
@@ -13,6 +39,12 @@ type ContextMenu struct {
 	x, y     int
 	selected func(int, string, rune)
 
+	// The state of the items, in the order of the items of the list.
+	items []*contextItem
+
+	// The menu this menu is a submenu of, if any.
+	parentMenu *ContextMenu
+
 	l sync.RWMutex
 }
 
@@ -39,9 +71,11 @@ func (c *ContextMenu) initializeList() {
 		Styles.ContextMenuPaddingBottom,
 		Styles.ContextMenuPaddingLeft,
 		Styles.ContextMenuPaddingRight)
+	c.list.menu = c
 }
 
-// ContextMenuList returns the underlying List of the context menu.
+// ContextMenuList returns the underlying List of the context menu. Items may
+// be disabled with its SetItemEnabled method.
 func (c *ContextMenu) ContextMenuList() *List {
 	c.l.Lock()
 	defer c.l.Unlock()
@@ -57,24 +91,182 @@ func (c *ContextMenu) AddContextItem(text string, shortcut rune, selected func(i
 	c.l.Lock()
 	defer c.l.Unlock()
 
+	c.addItem(&contextItem{text: text}, shortcut, selected)
+}
+
+// AddContextSeparator adds a separator line to the context menu. Separators
+// may not be selected.
+func (c *ContextMenu) AddContextSeparator() {
+	c.AddContextItem("", 0, nil)
+}
+
+// AddContextCheckItem adds an item which shows a check box. Selecting the item
+// toggles its checked state before the function is called.
+func (c *ContextMenu) AddContextCheckItem(text string, shortcut rune, checked bool, selected func(index int)) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	c.addItem(&contextItem{text: text, kind: contextItemCheck, checked: checked}, shortcut, selected)
+}
+
+// AddContextRadioItem adds an item which shows a radio button. Of all radio
+// items of the menu with the same group, only one is checked. Selecting the
+// item checks it before the function is called.
+func (c *ContextMenu) AddContextRadioItem(text string, shortcut rune, group string, checked bool, selected func(index int)) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	c.addItem(&contextItem{text: text, kind: contextItemRadio, group: group}, shortcut, selected)
+	if checked {
+		c.setChecked(len(c.items)-1, true)
+	}
+}
+
+// AddContextSubmenu adds an item which opens another context menu. The
+// submenu is shown right of the menu, or left of it when there is not enough
+// space at the right edge of the screen. It is opened by selecting the item,
+// by pressing the right arrow key or by hovering over the item with the mouse.
+// The left arrow key closes it again.
+//
+// Selecting an item of the submenu closes all menus. The functions of the
+// submenu's items receive the same index as the functions of this menu's
+// items.
+func (c *ContextMenu) AddContextSubmenu(text string, shortcut rune, submenu *ContextMenu) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	c.addItem(&contextItem{text: text, kind: contextItemSubmenu, submenu: submenu}, shortcut, nil)
+}
+
+func (c *ContextMenu) addItem(item *contextItem, shortcut rune, selected func(index int)) {
 	c.initializeList()
 
-	item := NewListItem(text)
-	item.SetShortcut(shortcut)
-	item.SetSelectedFunc(c.wrap(selected))
+	index := len(c.items)
+	c.items = append(c.items, item)
+
+	listItem := NewListItem(item.text)
+	listItem.SetShortcut(shortcut)
+	if item.kind != contextItemSubmenu {
+		listItem.SetSelectedFunc(c.wrap(index, selected))
+	}
 
-	c.list.AddItem(item)
-	if text == "" && shortcut == 0 {
+	c.list.AddItem(listItem)
+	if item.text == "" && shortcut == 0 {
 		c.list.Lock()
-		index := len(c.list.items) - 1
-		c.list.items[index].disabled = true
+		c.list.items[len(c.list.items)-1].disabled = true
 		c.list.Unlock()
 	}
+
+	c.updateText()
 }
 
-func (c *ContextMenu) wrap(f func(index int)) func() {
+func (c *ContextMenu) wrap(index int, f func(index int)) func() {
 	return func() {
-		f(c.item)
+		c.l.Lock()
+		if index < len(c.items) {
+			switch c.items[index].kind {
+			case contextItemCheck:
+				c.setChecked(index, !c.items[index].checked)
+			case contextItemRadio:
+				c.setChecked(index, true)
+			}
+		}
+		item := c.item
+		c.l.Unlock()
+
+		if f != nil {
+			f(item)
+		}
+	}
+}
+
+// SetContextItemChecked sets the checked state of a check box or radio item.
+// Checking a radio item unchecks the other items of its group.
+func (c *ContextMenu) SetContextItemChecked(index int, checked bool) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	c.setChecked(index, checked)
+}
+
+// IsContextItemChecked returns whether a check box or radio item is checked.
+func (c *ContextMenu) IsContextItemChecked(index int) bool {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	if index < 0 || index >= len(c.items) {
+		return false
+	}
+	return c.items[index].checked
+}
+
+func (c *ContextMenu) setChecked(index int, checked bool) {
+	if index < 0 || index >= len(c.items) {
+		return
+	}
+	item := c.items[index]
+	if item.kind != contextItemCheck && item.kind != contextItemRadio {
+		return
+	}
+
+	if item.kind == contextItemRadio && checked {
+		for _, other := range c.items {
+			if other.kind == contextItemRadio && other.group == item.group {
+				other.checked = false
+			}
+		}
+	}
+	item.checked = checked
+
+	c.updateText()
+}
+
+// updateText sets the texts of the list items from the state of the items.
+// Check boxes and radio buttons are shown in front of the text, submenu
+// indicators are aligned at the right.
+func (c *ContextMenu) updateText() {
+	var (
+		checkable bool
+		textWidth int
+	)
+	for _, item := range c.items {
+		checkable = checkable || item.kind == contextItemCheck || item.kind == contextItemRadio
+		if w := TaggedStringWidth(item.text); w > textWidth {
+			textWidth = w
+		}
+	}
+
+	c.list.Lock()
+	defer c.list.Unlock()
+
+	for index, item := range c.items {
+		if index >= len(c.list.items) {
+			break
+		} else if item.text == "" {
+			continue
+		}
+
+		var b strings.Builder
+		if checkable {
+			switch {
+			case item.kind == contextItemCheck && item.checked:
+				b.WriteString(Escape("[x] "))
+			case item.kind == contextItemCheck:
+				b.WriteString("[ ] ")
+			case item.kind == contextItemRadio && item.checked:
+				b.WriteString("(•) ")
+			case item.kind == contextItemRadio:
+				b.WriteString("( ) ")
+			default:
+				b.WriteString("    ")
+			}
+		}
+		b.WriteString(item.text)
+		if item.kind == contextItemSubmenu {
+			b.WriteString(strings.Repeat(" ", textWidth-TaggedStringWidth(item.text)+1))
+			b.WriteString("►")
+		}
+		c.list.items[index].mainText = []byte(b.String())
 	}
 }
 
@@ -85,6 +277,7 @@ func (c *ContextMenu) ClearContextMenu() {
 
 	c.initializeList()
 
+	c.items = nil
 	c.list.Clear()
 }
 
@@ -147,14 +340,28 @@ func (c *ContextMenu) show(item int, x int, y int, setFocus func(Primitive)) {
 	c.list.SetSelectedFunc(func(index int, item *ListItem) {
 		c.l.Lock()
 
-		// A context item was selected. Close the menu.
-		c.hide(setFocus)
-
-		if c.selected != nil {
-			c.l.Unlock()
-			c.selected(index, string(item.mainText), item.shortcut)
-		} else {
+		// Submenu items open their submenu.
+		if index < len(c.items) && c.items[index].kind == contextItemSubmenu {
+			c.showSubmenu(index, setFocus)
 			c.l.Unlock()
+			return
+		}
+
+		text := string(item.mainText)
+		if index < len(c.items) {
+			text = c.items[index].text
+		}
+		selected := c.selected
+		c.l.Unlock()
+
+		// A context item was selected. Close the menu and its parent menus.
+		root := c.root()
+		root.l.Lock()
+		root.hide(setFocus)
+		root.l.Unlock()
+
+		if selected != nil {
+			selected(index, text, item.shortcut)
 		}
 	})
 	c.list.SetDoneFunc(func() {
@@ -167,12 +374,287 @@ func (c *ContextMenu) show(item int, x int, y int, setFocus func(Primitive)) {
 	setFocus(c.list)
 }
 
+// showSubmenu shows the submenu of an item, closing any other submenu.
+func (c *ContextMenu) showSubmenu(index int, setFocus func(Primitive)) {
+	submenu := c.items[index].submenu
+	if open := c.openSubmenu(); open != nil && open != submenu {
+		open.l.Lock()
+		open.hide(func(Primitive) {})
+		open.l.Unlock()
+	}
+	if submenu == nil {
+		return
+	}
+
+	submenu.l.Lock()
+	defer submenu.l.Unlock()
+
+	if submenu.open {
+		setFocus(submenu.list)
+		return
+	}
+	submenu.parent = c.list
+	submenu.parentMenu = c
+	submenu.show(c.item, -1, -1, setFocus)
+}
+
+// openSubmenu returns the open submenu of the menu, if any, and the index of
+// the item which opened it.
+func (c *ContextMenu) openSubmenu() *ContextMenu {
+	for _, item := range c.items {
+		if item.submenu == nil {
+			continue
+		}
+		item.submenu.l.RLock()
+		open := item.submenu.open
+		item.submenu.l.RUnlock()
+		if open {
+			return item.submenu
+		}
+	}
+	return nil
+}
+
+// openSubmenuIndex returns the index of the item whose submenu is open, or -1.
+func (c *ContextMenu) openSubmenuIndex() int {
+	open := c.openSubmenu()
+	for index, item := range c.items {
+		if open != nil && item.submenu == open {
+			return index
+		}
+	}
+	return -1
+}
+
+// root returns the menu at the top of the chain of open submenus.
+func (c *ContextMenu) root() *ContextMenu {
+	menu := c
+	for {
+		menu.l.RLock()
+		parent := menu.parentMenu
+		menu.l.RUnlock()
+		if parent == nil {
+			return menu
+		}
+		menu = parent
+	}
+}
+
 func (c *ContextMenu) hide(setFocus func(Primitive)) {
 	c.initializeList()
 
+	focused := c.menuHasFocus()
+
+	// Close the submenus without moving the focus to their parents.
+	if submenu := c.openSubmenu(); submenu != nil {
+		submenu.l.Lock()
+		submenu.hide(func(Primitive) {})
+		submenu.l.Unlock()
+	}
+
 	c.open = false
 
-	if c.list.HasFocus() {
+	if focused {
 		setFocus(c.parent)
 	}
 }
+
+// menuHasFocus returns whether the list of the menu or of an open submenu has
+// focus.
+func (c *ContextMenu) menuHasFocus() bool {
+	if c.list.HasFocus() {
+		return true
+	}
+	if submenu := c.openSubmenu(); submenu != nil {
+		submenu.l.RLock()
+		defer submenu.l.RUnlock()
+
+		return submenu.menuHasFocus()
+	}
+	return false
+}
+
+// focusList returns the list of the deepest open submenu, or the list of the
+// menu if no submenu is open.
+func (c *ContextMenu) focusList() *List {
+	if submenu := c.openSubmenu(); submenu != nil {
+		submenu.l.RLock()
+		defer submenu.l.RUnlock()
+
+		return submenu.focusList()
+	}
+	return c.list
+}
+
+// listAtPoint returns the list of the deepest open menu containing the given
+// point, or nil if the point is outside of all open menus.
+func (c *ContextMenu) listAtPoint(x, y int) *List {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	if submenu := c.openSubmenu(); submenu != nil {
+		if list := submenu.listAtPoint(x, y); list != nil {
+			return list
+		}
+	}
+	if c.list != nil && c.list.InRect(x, y) {
+		return c.list
+	}
+	return nil
+}
+
+// handleKey handles the keys which open and close submenus. It is called by
+// the list of the menu and returns whether the key was handled.
+func (c *ContextMenu) handleKey(event *tcell.EventKey, setFocus func(Primitive)) bool {
+	keys := c.list.GetKeys()
+
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	switch {
+	case HitShortcut(event, keys.MoveRight):
+		index := c.list.GetCurrentItemIndex()
+		if index < 0 || index >= len(c.items) || c.items[index].kind != contextItemSubmenu {
+			return false
+		}
+		c.showSubmenu(index, setFocus)
+		return true
+	case HitShortcut(event, keys.MoveLeft):
+		if c.parentMenu == nil {
+			return false
+		}
+		c.hide(setFocus)
+		return true
+	}
+	return false
+}
+
+// hover opens the submenu of an item the mouse is moved over and closes the
+// submenu of any other item.
+func (c *ContextMenu) hover(index int, setFocus func(Primitive)) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	if index < 0 || index >= len(c.items) || c.openSubmenuIndex() == index {
+		return
+	}
+	if c.items[index].kind == contextItemSubmenu && c.list.items[index].disabled {
+		return
+	}
+	if c.items[index].kind == contextItemSubmenu {
+		c.showSubmenu(index, setFocus)
+	} else if submenu := c.openSubmenu(); submenu != nil {
+		submenu.l.Lock()
+		submenu.hide(setFocus)
+		submenu.l.Unlock()
+	}
+}
+
+// draw draws the menu at the given position, followed by its open submenu. A
+// menu which does not fit right of x is moved left of flipX, or as far left
+// as necessary if flipX is negative.
+func (c *ContextMenu) draw(screen tcell.Screen, x, y, flipX int) {
+	c.l.RLock()
+	list := c.list
+	c.l.RUnlock()
+
+	list.RLock()
+	// What's the longest option text? Shortcuts are shown in a column of
+	// their own.
+	maxWidth := 0
+	var showShortcuts bool
+	for _, option := range list.items {
+		showShortcuts = showShortcuts || option.shortcut != 0
+		if strWidth := TaggedTextWidth(option.mainText); strWidth > maxWidth {
+			maxWidth = strWidth
+		}
+	}
+	if showShortcuts {
+		maxWidth += 4
+	}
+
+	lheight := len(list.items)
+	lwidth := maxWidth
+
+	// Add space for borders
+	lwidth += 2
+	lheight += 2
+
+	lwidth += list.paddingLeft + list.paddingRight
+	lheight += list.paddingTop + list.paddingBottom
+
+	if list.scrollBarVisibility == ScrollBarAlways || (list.scrollBarVisibility == ScrollBarAuto && len(list.items) > lheight) {
+		lwidth++ // Add space for scroll bar
+	}
+	list.RUnlock()
+
+	swidth, sheight := screen.Size()
+	if x+lwidth > swidth {
+		if flipX >= 0 {
+			x = flipX - lwidth
+		} else {
+			x = swidth - lwidth
+		}
+		if x < 0 {
+			x = 0
+		}
+	}
+
+	if y+lheight >= sheight && y-2 > lheight-y {
+		for i := (y + lheight) - sheight; i > 0; i-- {
+			y--
+			if y+lheight < sheight {
+				break
+			}
+		}
+		if y < 0 {
+			y = 0
+		}
+	}
+	if y+lheight >= sheight {
+		lheight = sheight - y
+	}
+
+	list.SetRect(x, y, lwidth, lheight)
+	list.Draw(screen)
+
+	// Align the first item of the open submenu with the item which opened it.
+	c.l.RLock()
+	index := c.openSubmenuIndex()
+	var submenu *ContextMenu
+	if index >= 0 {
+		submenu = c.items[index].submenu
+	}
+	c.l.RUnlock()
+	if submenu == nil {
+		return
+	}
+
+	_, iy, _, _ := list.GetInnerRect()
+	offset, _ := list.GetOffset()
+	submenuList := submenu.ContextMenuList()
+	submenuList.RLock()
+	paddingTop := submenuList.paddingTop
+	submenuList.RUnlock()
+	submenu.draw(screen, x+lwidth, iy+index-offset-1-paddingTop, x)
+}
+
+// applyTheme applies a theme to the list of the menu and its submenus.
+func (c *ContextMenu) applyTheme(previous, theme *Theme) {
+	c.l.RLock()
+	list := c.list
+	var submenus []*ContextMenu
+	for _, item := range c.items {
+		if item.submenu != nil && item.submenu != c {
+			submenus = append(submenus, item.submenu)
+		}
+	}
+	c.l.RUnlock()
+
+	if list != nil {
+		list.ApplyTheme(previous, theme)
+	}
+	for _, submenu := range submenus {
+		submenu.applyTheme(previous, theme)
+	}
+}
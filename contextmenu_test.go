@@ -0,0 +1,124 @@
+package crtview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestContextMenu(t *testing.T) {
+	t.Parallel()
+
+	var selected []string
+	handler := func(name string) func(int) {
+		return func(int) {
+			selected = append(selected, name)
+		}
+	}
+
+	l := NewList()
+	l.AddItem(NewListItem("file.txt"))
+
+	archive := NewContextMenu(nil)
+	archive.AddContextItem("Zip", 0, handler("zip"))
+	archive.AddContextItem("Tar", 0, handler("tar"))
+
+	l.AddContextItem("Open", 'o', handler("open"))
+	l.AddContextSeparator()
+	l.AddContextCheckItem("Hidden files", 0, false, handler("hidden"))
+	l.AddContextRadioItem("By name", 0, "sort", true, handler("name"))
+	l.AddContextRadioItem("By size", 0, "sort", false, handler("size"))
+	l.AddContextItem("Delete", 0, handler("delete"))
+	l.AddContextSubmenu("Compress", 0, archive)
+	l.ContextMenuList().SetItemEnabled(5, false)
+
+	app, err := newTestApp(l)
+	if err != nil {
+		t.Fatalf("failed to initialize Application: %s", err)
+	}
+	l.SetRect(0, 0, 40, 10)
+
+	var (
+		focused  Primitive = l
+		setFocus func(p Primitive)
+	)
+	setFocus = func(p Primitive) {
+		focused.Blur()
+		focused = p
+		p.Focus(setFocus)
+	}
+	l.Focus(setFocus)
+	key := func(k tcell.Key, mod tcell.ModMask) {
+		focused.InputHandler()(tcell.NewEventKey(k, 0, mod), setFocus)
+	}
+
+	// Separators are skipped, check items are toggled.
+	key(tcell.KeyEnter, tcell.ModAlt)
+	key(tcell.KeyDown, tcell.ModNone)
+	key(tcell.KeyEnter, tcell.ModNone)
+	if !l.IsContextItemChecked(2) || l.ContextMenuVisible() || focused != l {
+		t.Errorf("failed to toggle check item: expected checked item and closed menu")
+	}
+
+	// Checking a radio item unchecks the others of its group.
+	key(tcell.KeyEnter, tcell.ModAlt)
+	for i := 0; i < 3; i++ {
+		key(tcell.KeyDown, tcell.ModNone)
+	}
+	key(tcell.KeyEnter, tcell.ModNone)
+	if l.IsContextItemChecked(3) || !l.IsContextItemChecked(4) {
+		t.Errorf("failed to check radio item: expected By size to replace By name")
+	}
+
+	// Disabled items are skipped, submenus are opened with Right and closed
+	// with Left.
+	key(tcell.KeyEnter, tcell.ModAlt)
+	key(tcell.KeyUp, tcell.ModNone)
+	key(tcell.KeyRight, tcell.ModNone)
+	if focused != archive.ContextMenuList() {
+		t.Fatalf("failed to open submenu: expected submenu to have focus")
+	}
+
+	l.Draw(app.screen)
+	expected := []string{
+		"file.tx┌────────────────────────┐",
+		"       │ (o)     Open           │",
+		"       │    ────────────────────│",
+		"       │     [x] Hidden files   │",
+		"       │     ( ) By name        │",
+		"       │     (•) By size        │",
+		"       │         Delete         │┌─────┐",
+		"       │         Compress     ► ││ Zip │",
+		"       └────────────────────────┘│ Tar │",
+		"                                 └─────┘",
+	}
+	for y, line := range expected {
+		if got := screenLine(app.screen, y); got != line {
+			t.Errorf("failed to draw context menu line %d: expected %q, got %q", y, line, got)
+		}
+	}
+
+	// Submenus flip to the left at the right edge of the screen.
+	app.screen.Clear()
+	l.SetRect(50, 0, 30, 10)
+	l.Draw(app.screen)
+	if line := screenLine(app.screen, 7); !strings.HasSuffix(line, "│ Zip ││         Compress     ► │") {
+		t.Errorf("failed to flip submenu: got %q", line)
+	}
+
+	key(tcell.KeyLeft, tcell.ModNone)
+	if archive.ContextMenuVisible() || focused != l.ContextMenuList() {
+		t.Errorf("failed to close submenu: expected menu to have focus")
+	}
+	key(tcell.KeyRight, tcell.ModNone)
+	key(tcell.KeyDown, tcell.ModNone)
+	key(tcell.KeyEnter, tcell.ModNone)
+	if l.ContextMenuVisible() || archive.ContextMenuVisible() || focused != l {
+		t.Errorf("failed to close menus: expected list to have focus")
+	}
+
+	if strings.Join(selected, ",") != "hidden,size,tar" {
+		t.Errorf("failed to select items: expected hidden,size,tar, got %s", strings.Join(selected, ","))
+	}
+}
@@ -0,0 +1,214 @@
+package crtviewtest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/isbm/crtview"
+)
+
+// GoldenDir is the directory golden files are read from and written to,
+// relative to the directory of the test being run.
+var GoldenDir = "testdata"
+
+// Update determines whether golden files are rewritten instead of compared
+// against. Golden files are also rewritten if the environment variable
+// CRTVIEW_UPDATE_GOLDEN is set to a true value, or if the test package defines
+// an "update" flag (e.g. with flag.Bool) and it is set. This package defines
+// no flags itself.
+var Update bool
+
+// updateGolden returns whether golden files are rewritten.
+func updateGolden() bool {
+	if Update {
+		return true
+	}
+	if update, err := strconv.ParseBool(os.Getenv("CRTVIEW_UPDATE_GOLDEN")); err == nil && update {
+		return true
+	}
+	if f := flag.Lookup("update"); f != nil {
+		update, _ := strconv.ParseBool(f.Value.String())
+		return update
+	}
+	return false
+}
+
+// styleKeys are the characters used to reference styles in a golden file, in
+// order of first appearance.
+const styleKeys = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// styleKey returns the key of the style with the given index in order of
+// first appearance, consisting of the given number of characters.
+func styleKey(index, length int) string {
+	key := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		key[i] = styleKeys[index%len(styleKeys)]
+		index /= len(styleKeys)
+	}
+	return string(key)
+}
+
+// Render draws a primitive onto a simulation screen of the given size and
+// returns the result. The primitive is positioned at the top left corner of
+// the screen and resized to fill it.
+func Render(p crtview.Primitive, width, height int) *Snapshot {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		panic(err)
+	}
+	defer screen.Fini()
+	screen.SetSize(width, height)
+
+	p.SetRect(0, 0, width, height)
+	p.Draw(screen)
+	screen.Show()
+
+	return NewSnapshot(screen)
+}
+
+// Golden returns the snapshot in the golden file format. The text grid is
+// followed by a style map referencing one style per cell by a single
+// character, and a legend describing each referenced style. If the snapshot
+// has more styles than there are characters (62), each style is referenced
+// by as many characters as needed instead.
+func (s *Snapshot) Golden() []byte {
+	var (
+		b      bytes.Buffer
+		keys   = make(map[tcell.Style]string)
+		legend []tcell.Style
+	)
+
+	// Determine the styles and the length of their keys.
+	for _, cell := range s.Cells {
+		if _, ok := keys[cell.Style]; !ok {
+			keys[cell.Style] = ""
+			legend = append(legend, cell.Style)
+		}
+	}
+	length := 1
+	for count := len(styleKeys); count < len(legend); count *= len(styleKeys) {
+		length++
+	}
+	for index, style := range legend {
+		keys[style] = styleKey(index, length)
+	}
+
+	b.WriteString("-- text --\n")
+	for y := 0; y < s.Height; y++ {
+		b.WriteString(s.Line(y))
+		b.WriteString("|\n")
+	}
+
+	b.WriteString("-- styles --\n")
+	for y := 0; y < s.Height; y++ {
+		for x := 0; x < s.Width; x++ {
+			b.WriteString(keys[s.Style(x, y)])
+		}
+		b.WriteByte('\n')
+	}
+
+	b.WriteString("-- legend --\n")
+	for _, style := range legend {
+		fmt.Fprintf(&b, "%s %s\n", keys[style], formatStyle(style))
+	}
+	return b.Bytes()
+}
+
+// formatStyle returns a compact description of a style.
+func formatStyle(style tcell.Style) string {
+	fg, bg, attrs := style.Decompose()
+	return fmt.Sprintf("fg=%s bg=%s attrs=%s", formatColor(fg), formatColor(bg), formatAttributes(attrs))
+}
+
+// formatColor returns a color as #rrggbb, or "default" if it is not set.
+func formatColor(color tcell.Color) string {
+	if color == tcell.ColorDefault {
+		return "default"
+	}
+	return fmt.Sprintf("#%06x", color.Hex())
+}
+
+// formatAttributes returns the attributes as a string of flag characters.
+func formatAttributes(attrs tcell.AttrMask) string {
+	var b strings.Builder
+	for _, attr := range []struct {
+		mask tcell.AttrMask
+		flag byte
+	}{
+		{tcell.AttrBold, 'b'},
+		{tcell.AttrBlink, 'l'},
+		{tcell.AttrReverse, 'r'},
+		{tcell.AttrUnderline, 'u'},
+		{tcell.AttrDim, 'd'},
+		{tcell.AttrItalic, 'i'},
+		{tcell.AttrStrikeThrough, 's'},
+	} {
+		if attrs&attr.mask != 0 {
+			b.WriteByte(attr.flag)
+		}
+	}
+	if b.Len() == 0 {
+		return "-"
+	}
+	return b.String()
+}
+
+// AssertGolden renders a primitive at the given size and compares the result
+// against the golden file GoldenDir/name.golden. The golden file is rewritten
+// instead if Update is set.
+func AssertGolden(t testing.TB, name string, p crtview.Primitive, width, height int) {
+	t.Helper()
+
+	AssertSnapshot(t, name, Render(p, width, height))
+}
+
+// AssertSnapshot compares a snapshot against the golden file
+// GoldenDir/name.golden. The golden file is rewritten instead if Update is set.
+func AssertSnapshot(t testing.TB, name string, s *Snapshot) {
+	t.Helper()
+
+	path := filepath.Join(GoldenDir, name+".golden")
+	actual := s.Golden()
+
+	if updateGolden() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create golden file directory: %s", err)
+		}
+		if err := ioutil.WriteFile(path, actual, 0644); err != nil {
+			t.Fatalf("failed to update golden file: %s", err)
+		}
+		return
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (set CRTVIEW_UPDATE_GOLDEN=1 to create it): %s", err)
+	}
+	if bytes.Equal(expected, actual) {
+		return
+	}
+
+	expectedLines := strings.Split(string(expected), "\n")
+	actualLines := strings.Split(string(actual), "\n")
+	for i := 0; i < len(expectedLines) || i < len(actualLines); i++ {
+		var e, a string
+		if i < len(expectedLines) {
+			e = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			a = actualLines[i]
+		}
+		if e != a {
+			t.Errorf("snapshot does not match %s at line %d:\nexpected: %s\n     got: %s\n\ngot:\n%s", path, i+1, e, a, actual)
+			return
+		}
+	}
+}
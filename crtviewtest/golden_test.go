@@ -0,0 +1,90 @@
+package crtviewtest
+
+import (
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/isbm/crtview"
+	"github.com/isbm/crtview/crtwin"
+)
+
+// update rewrites the golden files when the tests are run with -update. The
+// flag is defined here, and looked up by the package (see Update).
+var update = flag.Bool("update", false, "rewrite golden files instead of comparing against them")
+
+func TestGoldenBox(t *testing.T) {
+	b := crtview.NewBox()
+	b.SetBorder(true)
+	b.SetTitle("Box")
+
+	AssertGolden(t, "box", b, 20, 5)
+}
+
+func TestGoldenTable(t *testing.T) {
+	table := crtview.NewTable()
+	table.SetBorders(true)
+	for row, cells := range [][]string{
+		{"Name", "Size"},
+		{"alpha", "1"},
+		{"beta", "22"},
+	} {
+		for column, text := range cells {
+			table.SetCellSimple(row, column, text)
+		}
+	}
+	table.SetFixed(1, 0)
+
+	AssertGolden(t, "table", table, 20, 8)
+}
+
+func TestGoldenTreeView(t *testing.T) {
+	root := crtview.NewTreeNode("root")
+	child := crtview.NewTreeNode("child")
+	child.AddChild(crtview.NewTreeNode("leaf"))
+	root.AddChild(child)
+	root.AddChild(crtview.NewTreeNode("sibling"))
+
+	tree := crtview.NewTreeView()
+	tree.SetRoot(root)
+	tree.SetCurrentNode(root)
+
+	AssertGolden(t, "treeview", tree, 20, 5)
+}
+
+func TestGoldenModalDialog(t *testing.T) {
+	dialog := crtwin.NewModalDialog(crtwin.DIALOG_OK | crtwin.DIALOG_TYPE_WARNING)
+	dialog.SetTitle("Warning")
+	dialog.SetMessage("Disk is full")
+
+	AssertGolden(t, "modaldialog", dialog, 40, 16)
+}
+
+func TestSnapshotGolden(t *testing.T) {
+	t.Parallel()
+
+	text := crtview.NewTextView()
+	text.SetDynamicColors(true)
+	text.SetText("[red::b]A[-::-]B")
+
+	expected := "-- text --\nAB|\n-- styles --\nabc\n-- legend --\n" +
+		"a fg=#ff0000 bg=#000000 attrs=b\n" +
+		"b fg=#ffffff bg=#000000 attrs=-\n" +
+		"c fg=default bg=#000000 attrs=-\n"
+	if golden := string(Render(text, 3, 1).Golden()); golden != expected {
+		t.Errorf("unexpected golden output: expected\n%s\ngot\n%s", expected, golden)
+	}
+
+	// Styles are referenced by two characters if there are more than 62.
+	s := &Snapshot{Width: 63, Height: 1}
+	for i := 0; i < s.Width; i++ {
+		s.Cells = append(s.Cells, Cell{Runes: []rune{' '}, Style: tcell.StyleDefault.Foreground(tcell.NewRGBColor(int32(i), 0, 0))})
+	}
+	lines := strings.Split(string(s.Golden()), "\n")
+	if styles := lines[3]; len(styles) != 126 || styles[:4] != "aaab" || styles[122:] != "a9ba" {
+		t.Errorf("failed to reference styles by two characters: got %q", styles)
+	} else if legend := lines[len(lines)-2]; legend != "ba fg=#3e0000 bg=default attrs=-" {
+		t.Errorf("failed to describe style: got %q", legend)
+	}
+}
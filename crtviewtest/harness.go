@@ -0,0 +1,305 @@
+/*
+Package crtviewtest provides utilities for testing crtview applications.
+
+A Harness runs an Application on a tcell simulation screen. Tests inject key,
+mouse, paste and resize events, which are delivered through
+Application.QueueEvent exactly as a terminal would deliver them, and then
+inspect the rendered screen:
+
+	h := crtviewtest.New(t, form, 80, 24)
+	h.Type("admin")
+	h.Key(tcell.KeyTab, 0, tcell.ModNone)
+	if line := h.Snapshot().Line(2); !strings.Contains(line, "admin") {
+	    t.Errorf("unexpected line: %q", line)
+	}
+
+The application is stopped automatically when the test finishes. Call
+Harness.Close to stop it earlier.
+*/
+package crtviewtest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/isbm/crtview"
+	"github.com/mattn/go-runewidth"
+)
+
+// DefaultTimeout is the maximum time a Harness waits for the application to
+// process queued events before failing the test.
+var DefaultTimeout = 5 * time.Second
+
+// Harness drives an Application running on a simulation screen.
+type Harness struct {
+	// The application under test.
+	App *crtview.Application
+
+	// The simulation screen the application draws onto.
+	Screen tcell.SimulationScreen
+
+	// The maximum time to wait for the application to process events.
+	Timeout time.Duration
+
+	t    testing.TB
+	done chan error
+}
+
+// New starts an Application with the given root primitive on a simulation
+// screen of the given size. The root primitive is resized to fill the screen
+// and receives the focus. The application is stopped when the test finishes.
+func New(t testing.TB, root crtview.Primitive, width, height int) *Harness {
+	t.Helper()
+
+	app := crtview.NewApplication()
+	app.SetRoot(root, true)
+	return NewWithApplication(t, app, width, height)
+}
+
+// NewWithApplication starts the provided Application on a simulation screen
+// of the given size. The application must not have been started yet. It is
+// stopped when the test finishes.
+func NewWithApplication(t testing.TB, app *crtview.Application, width, height int) *Harness {
+	t.Helper()
+
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("failed to initialize simulation screen: %s", err)
+	}
+	screen.SetSize(width, height)
+	app.SetScreen(screen)
+
+	h := &Harness{
+		App:     app,
+		Screen:  screen,
+		Timeout: DefaultTimeout,
+		t:       t,
+		done:    make(chan error, 1),
+	}
+
+	done := h.done
+	go func() {
+		done <- app.Run()
+	}()
+	t.Cleanup(h.Close)
+
+	h.App.QueueEvent(tcell.NewEventResize(width, height))
+	h.Sync()
+	return h
+}
+
+// Close stops the application and waits for it to exit. It is safe to call
+// Close more than once.
+func (h *Harness) Close() {
+	h.t.Helper()
+
+	if h.done == nil {
+		return
+	}
+	done := h.done
+	h.done = nil
+
+	h.App.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			h.t.Errorf("application exited with error: %s", err)
+		}
+	case <-time.After(h.Timeout):
+		h.t.Errorf("timed out waiting for application to exit")
+	}
+}
+
+// Sync waits until all events queued so far have been processed and a frame
+// has been drawn afterwards.
+func (h *Harness) Sync() {
+	h.t.Helper()
+
+	processed := make(chan struct{})
+	h.App.QueueEvent(tcell.NewEventInterrupt(func() {
+		close(processed)
+	}))
+	h.wait(processed)
+
+	drawn := make(chan struct{})
+	h.App.QueueUpdateDraw(func() {})
+	h.App.QueueUpdate(func() {
+		close(drawn)
+	})
+	h.wait(drawn)
+}
+
+func (h *Harness) wait(c chan struct{}) {
+	h.t.Helper()
+
+	select {
+	case <-c:
+	case <-time.After(h.Timeout):
+		h.t.Fatalf("timed out waiting for application to process events")
+	}
+}
+
+// QueueEvent queues an arbitrary event.
+func (h *Harness) QueueEvent(event tcell.Event) {
+	h.App.QueueEvent(event)
+}
+
+// Key queues a key event.
+func (h *Harness) Key(key tcell.Key, r rune, mod tcell.ModMask) {
+	h.App.QueueEvent(tcell.NewEventKey(key, r, mod))
+}
+
+// Type queues a key event for each rune of the provided text.
+func (h *Harness) Type(text string) {
+	for _, r := range text {
+		h.Key(tcell.KeyRune, r, tcell.ModNone)
+	}
+}
+
+// Mouse queues a mouse event with the given position and button state.
+func (h *Harness) Mouse(x, y int, buttons tcell.ButtonMask, mod tcell.ModMask) {
+	h.App.QueueEvent(tcell.NewEventMouse(x, y, buttons, mod))
+}
+
+// Click queues a press and a release of the primary mouse button at the given
+// position.
+func (h *Harness) Click(x, y int) {
+	h.Mouse(x, y, tcell.ButtonPrimary, tcell.ModNone)
+	h.Mouse(x, y, tcell.ButtonNone, tcell.ModNone)
+}
+
+// Paste queues a bracketed paste of the provided text: a paste start event,
+// a key event for each rune and a paste end event.
+func (h *Harness) Paste(text string) {
+	h.App.QueueEvent(tcell.NewEventPaste(true))
+	for _, r := range text {
+		switch r {
+		case '\n':
+			h.Key(tcell.KeyEnter, r, tcell.ModNone)
+		case '\t':
+			h.Key(tcell.KeyTab, r, tcell.ModNone)
+		default:
+			h.Key(tcell.KeyRune, r, tcell.ModNone)
+		}
+	}
+	h.App.QueueEvent(tcell.NewEventPaste(false))
+}
+
+// Resize changes the size of the simulation screen and waits until the
+// application has processed the new size.
+func (h *Harness) Resize(width, height int) {
+	h.t.Helper()
+
+	h.Screen.SetSize(width, height)
+
+	// Resize events are throttled by the application, so retry until the new
+	// size has been applied.
+	deadline := time.Now().Add(h.Timeout)
+	for {
+		h.App.QueueEvent(tcell.NewEventResize(width, height))
+		h.Sync()
+
+		w, hh := h.App.GetScreenSize()
+		if w == width && hh == height {
+			return
+		} else if time.Now().After(deadline) {
+			h.t.Fatalf("timed out waiting for application to resize to %dx%d", width, height)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Snapshot waits for all queued events to be processed and returns the
+// contents of the screen.
+func (h *Harness) Snapshot() *Snapshot {
+	h.t.Helper()
+
+	h.Sync()
+	return NewSnapshot(h.Screen)
+}
+
+// Cell is a single screen cell.
+type Cell struct {
+	// The runes displayed in the cell, including combining characters.
+	Runes []rune
+
+	// The style of the cell.
+	Style tcell.Style
+}
+
+// Snapshot holds the contents of a screen at a point in time.
+type Snapshot struct {
+	Width, Height int
+
+	// The cells of the screen, row by row.
+	Cells []Cell
+}
+
+// NewSnapshot returns the current contents of a simulation screen.
+func NewSnapshot(screen tcell.SimulationScreen) *Snapshot {
+	cells, width, height := screen.GetContents()
+
+	s := &Snapshot{
+		Width:  width,
+		Height: height,
+		Cells:  make([]Cell, len(cells)),
+	}
+	for i, cell := range cells {
+		s.Cells[i] = Cell{
+			Runes: append([]rune(nil), cell.Runes...),
+			Style: cell.Style,
+		}
+	}
+	return s
+}
+
+// Cell returns the cell at the given position.
+func (s *Snapshot) Cell(x, y int) Cell {
+	if x < 0 || y < 0 || x >= s.Width || y >= s.Height {
+		return Cell{}
+	}
+	return s.Cells[y*s.Width+x]
+}
+
+// Style returns the style of the cell at the given position.
+func (s *Snapshot) Style(x, y int) tcell.Style {
+	return s.Cell(x, y).Style
+}
+
+// Line returns the text of the given row. Trailing whitespace is removed.
+func (s *Snapshot) Line(y int) string {
+	var b strings.Builder
+	for x := 0; x < s.Width; {
+		runes := s.Cell(x, y).Runes
+		if len(runes) == 0 {
+			b.WriteRune(' ')
+			x++
+			continue
+		}
+		b.WriteString(string(runes))
+
+		// Skip the cells covered by wide characters.
+		width := runewidth.RuneWidth(runes[0])
+		if width < 1 {
+			width = 1
+		}
+		x += width
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// Lines returns the text of all rows.
+func (s *Snapshot) Lines() []string {
+	lines := make([]string, s.Height)
+	for y := range lines {
+		lines[y] = s.Line(y)
+	}
+	return lines
+}
+
+// Text returns the text of all rows separated by newlines.
+func (s *Snapshot) Text() string {
+	return strings.Join(s.Lines(), "\n")
+}
@@ -0,0 +1,118 @@
+package crtviewtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/isbm/crtview"
+)
+
+func TestHarnessForm(t *testing.T) {
+	t.Parallel()
+
+	var submitted string
+
+	form := crtview.NewForm()
+	form.AddInputField("Name", "", 20, nil, nil)
+	form.AddButton("Save", func() {
+		submitted = form.GetFormItem(0).(*crtview.InputField).GetText()
+	})
+
+	h := New(t, form, 40, 10)
+	defer h.Close()
+	h.Type("admin")
+
+	s := h.Snapshot()
+	if !strings.Contains(s.Text(), "admin") {
+		t.Errorf("failed to render typed text: got\n%s", s.Text())
+	}
+
+	// Move to the button and press it.
+	h.Key(tcell.KeyTab, 0, tcell.ModNone)
+	h.Key(tcell.KeyEnter, 0, tcell.ModNone)
+	h.Sync()
+
+	if submitted != "admin" {
+		t.Errorf("failed to submit form: expected admin, got %q", submitted)
+	}
+}
+
+func TestHarnessPaste(t *testing.T) {
+	t.Parallel()
+
+	input := crtview.NewInputField()
+
+	h := New(t, input, 40, 1)
+	defer h.Close()
+	h.Paste("pasted")
+	h.Sync()
+
+	if input.GetText() != "pasted" {
+		t.Errorf("failed to paste text: expected pasted, got %q", input.GetText())
+	}
+}
+
+func TestHarnessResize(t *testing.T) {
+	t.Parallel()
+
+	box := crtview.NewBox()
+	box.SetBorder(true)
+	box.ShowFocus(false)
+
+	h := New(t, box, 10, 5)
+	defer h.Close()
+	h.Resize(20, 8)
+
+	s := h.Snapshot()
+	if s.Width != 20 || s.Height != 8 {
+		t.Fatalf("failed to resize screen: expected 20x8, got %dx%d", s.Width, s.Height)
+	}
+	if r := s.Cell(19, 7).Runes; len(r) == 0 || r[0] != crtview.Borders.BottomRight {
+		t.Errorf("failed to redraw resized box: expected bottom right corner, got %q", string(r))
+	}
+}
+
+func TestHarnessStyles(t *testing.T) {
+	t.Parallel()
+
+	text := crtview.NewTextView()
+	text.SetDynamicColors(true)
+	text.SetText("[red]A[-]B")
+
+	h := New(t, text, 10, 1)
+	defer h.Close()
+
+	s := h.Snapshot()
+	if s.Line(0) != "AB" {
+		t.Fatalf("failed to render text: expected AB, got %q", s.Line(0))
+	}
+	if fg, _, _ := s.Style(0, 0).Decompose(); fg.Hex() != tcell.ColorRed.Hex() {
+		t.Errorf("failed to render style: expected red foreground, got %v", fg)
+	}
+}
+
+func TestHarnessCommands(t *testing.T) {
+	t.Parallel()
+
+	input := crtview.NewInputField()
+
+	app := crtview.NewApplication()
+	app.SetRoot(input, true)
+
+	var saved bool
+	app.GetCommands().Register("save", "Save", func() { saved = true }, "Ctrl+S")
+
+	h := NewWithApplication(t, app, 40, 1)
+	defer h.Close()
+	h.Type("a")
+	h.Key(tcell.KeyCtrlS, 0, tcell.ModCtrl)
+	h.Sync()
+
+	if !saved {
+		t.Errorf("failed to execute command bound to Ctrl+S")
+	}
+	if input.GetText() != "a" {
+		t.Errorf("failed to consume command key: expected a, got %q", input.GetText())
+	}
+}
@@ -36,25 +36,7 @@ func NewModalDialog(flags int) *ModalDialog {
 }
 
 func (tmd *ModalDialog) init() *ModalDialog {
-	var bgc, brc, txc tcell.Color
-	if tmd.flags&DIALOG_TYPE_ALT_INFO != 0 {
-		bgc = crtview.Styles.AltInfoDialogBackgroundColor
-		brc = crtview.Styles.AltInfoDialogBorderColor
-		txc = crtview.Styles.AltInfoDialogTextColor
-	} else if tmd.flags&DIALOG_TYPE_WARNING != 0 {
-		bgc = crtview.Styles.WarningDialogBackgroundColor
-		brc = crtview.Styles.WarningDialogBorderColor
-		txc = crtview.Styles.WarningDialogTextColor
-	} else if tmd.flags&DIALOG_TYPE_ALERT != 0 {
-		bgc = crtview.Styles.AlertDialogBackgroundColor
-		brc = crtview.Styles.AlertDialogBorderColor
-		txc = crtview.Styles.AlertDialogTextColor
-	} else {
-		// DIALOG_TYPE_INFO
-		bgc = crtview.Styles.InfoDialogBackgroundColor
-		brc = crtview.Styles.InfoDialogBorderColor
-		txc = crtview.Styles.InfoDialogTextColor
-	}
+	bgc, brc, txc := dialogColors(tmd.flags, &crtview.Styles)
 
 	tmd.SetBorder(true)
 	tmd.SetBackgroundColor(bgc)
@@ -91,6 +73,36 @@ func (tmd *ModalDialog) init() *ModalDialog {
 	return tmd
 }
 
+// dialogColors returns the background, border and text colors of a dialog type
+// in the given theme.
+func dialogColors(flags int, theme *crtview.Theme) (background, border, text tcell.Color) {
+	if flags&DIALOG_TYPE_ALT_INFO != 0 {
+		return theme.AltInfoDialogBackgroundColor, theme.AltInfoDialogBorderColor, theme.AltInfoDialogTextColor
+	} else if flags&DIALOG_TYPE_WARNING != 0 {
+		return theme.WarningDialogBackgroundColor, theme.WarningDialogBorderColor, theme.WarningDialogTextColor
+	} else if flags&DIALOG_TYPE_ALERT != 0 {
+		return theme.AlertDialogBackgroundColor, theme.AlertDialogBorderColor, theme.AlertDialogTextColor
+	}
+	// DIALOG_TYPE_INFO
+	return theme.InfoDialogBackgroundColor, theme.InfoDialogBorderColor, theme.InfoDialogTextColor
+}
+
+// dialogTheme returns a copy of the theme with the primitive colors replaced by
+// the colors of a dialog type.
+func dialogTheme(flags int, theme *crtview.Theme) *crtview.Theme {
+	t := *theme
+	t.PrimitiveBackgroundColor, t.BorderColor, t.PrimaryTextColor = dialogColors(flags, theme)
+	t.TitleColor = t.BorderColor
+	return &t
+}
+
+// ApplyTheme replaces the dialog colors of the previous theme with the dialog
+// colors of the new theme, including the colors of the message and the
+// buttons. Colors which were set explicitly are kept.
+func (tmd *ModalDialog) ApplyTheme(previous, theme *crtview.Theme) {
+	tmd.DialogWindow.ApplyTheme(dialogTheme(tmd.flags, previous), dialogTheme(tmd.flags, theme))
+}
+
 func (tmd *ModalDialog) SetMessage(msg string) {
 	tmd.msg.SetText(msg)
 }
@@ -31,7 +31,7 @@ func (tmd *DialogWindow) init() *DialogWindow {
 	tmd.SetBorder(true)
 	tmd.SetShadow(true)
 	tmd.SetTitle("")
-	tmd.SetShadowColor(tcell.NewRGBColor(0x66, 0x66, 0x66), tcell.ColorBlack)
+	tmd.SetShadowColor(crtview.Styles.ShadowTextColor, crtview.Styles.ShadowColor)
 
 	return tmd
 }
@@ -106,3 +106,17 @@ func (tmw *DialogWindow) Draw(screen tcell.Screen) {
 
 	tmw.SetRect(x, y, w, h)
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme, including the shadow colors. Colors which were set explicitly are
+// kept.
+func (tmd *DialogWindow) ApplyTheme(previous, theme *crtview.Theme) {
+	tmd.Form.ApplyTheme(previous, theme)
+
+	if tmd.shFgColor == previous.ShadowTextColor {
+		tmd.shFgColor = theme.ShadowTextColor
+	}
+	if tmd.shBgColor == previous.ShadowColor {
+		tmd.shBgColor = theme.ShadowColor
+	}
+}
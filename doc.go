@@ -32,13 +32,16 @@ The following widgets are available:
   Grid - A grid based layout manager.
   InputField - Single-line text entry field.
   List - A navigable text list with optional keyboard shortcuts.
+  MenuBar - A bar of drop-down menus with submenus and accelerators.
   Modal - A centered window with a text message and one or more buttons.
   Panels - A panel based layout manager.
   ProgressBar - Indicates the progress of an operation.
   Table - A scrollable display of tabular data. Table cells, rows, or columns
     may also be highlighted.
+  TextArea - Multi-line text entry field.
   TextView - A scrollable window that displays multi-colored text. Text may
     also be highlighted.
+  TreeTable - A tree of nodes with additional columns and column headers.
   TreeView - A scrollable display for hierarchical data. Tree nodes can be
     highlighted, collapsed, expanded, and more.
   Window - A draggable and resizable container.
@@ -64,6 +67,11 @@ H/J/K/L by default. You may replace these defaults by modifying the shortcuts
 listed in Keys. You may also override keyboard shortcuts globally by setting a
 handler with Application.SetInputCapture.
 
+Keymaps may be loaded from a file with LoadKeymap, based on one of the presets
+DefaultKeys, EmacsKeys or ViKeys. Conflicting bindings are reported when the
+keymap is loaded. The shortcuts of a single primitive may be overridden with
+SetKeys.
+
 cbind is a library which simplifies the process of adding support for custom
 keyboard shortcuts to your application. It allows setting handlers for
 EventKeys. It also translates between EventKeys and human-readable strings such
@@ -75,11 +83,24 @@ cbind: https://gitlab.com/tslocum/cbind
 Bracketed Paste Mode
 
 Bracketed paste mode is enabled by default. It may be disabled by calling
-Application.EnableBracketedPaste before Application.Run. The following demo
-shows how to handle paste events and process pasted text.
+Application.EnableBracketedPaste before Application.Run. Pasted text is
+delivered at once to the focused primitive if it implements Pastable, such as
+InputField, TextArea and Table while a cell is edited. Other primitives receive
+the pasted keys one by one. The following demo shows how to handle paste events
+and process pasted text.
 
 tcell bracketed paste demo: https://github.com/gdamore/tcell/blob/master/_demos/mouse.go
 
+Clipboard
+
+Text selected in an InputField, TextArea or TextView, with the mouse or the
+selection shortcuts, is copied to the clipboard of the application with the
+Copy and Cut shortcuts (see Keys) and pasted with the Paste shortcut. The
+clipboard is kept in memory by default. Call Application.SetClipboard with an
+OSC52Clipboard to also send copied text to the terminal. As Ctrl+C copies
+text, it only closes the application if no text is selected. The keys which
+close the application may be changed with Application.SetQuitKeys.
+
 Mouse Support
 
 Mouse support may be enabled by calling Application.EnableMouse before
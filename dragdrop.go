@@ -0,0 +1,106 @@
+package crtview
+
+import (
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// DragPayload is the data the user drags with the mouse, within a primitive
+// or from one primitive to another.
+type DragPayload struct {
+	// The primitive the data is dragged from.
+	Source Primitive
+
+	// The dragged data. A List drags its *ListItem, a TreeView its *TreeNode.
+	Data interface{}
+}
+
+// DropPosition is the position relative to a target at which dragged data is
+// dropped.
+type DropPosition int
+
+// Drop positions.
+const (
+	// Before the target, e.g. above a list item or a tree node.
+	DropBefore DropPosition = iota
+
+	// After the target, e.g. below a list item or a tree node.
+	DropAfter
+
+	// Onto the target, e.g. into a tree node as its last child.
+	DropOnto
+)
+
+// drag is the drag-and-drop operation in progress. There is only one mouse,
+// so there is at most one operation at a time.
+var drag struct {
+	// The dragged data, or nil if nothing is dragged.
+	payload *DragPayload
+
+	// The primitive which draws a drop indicator, if any.
+	target Primitive
+
+	sync.RWMutex
+}
+
+// StartDrag starts dragging data from a primitive with the mouse, replacing
+// the drag-and-drop operation in progress. Until the left mouse button is
+// released, the application sends mouse events to the primitive under the
+// mouse cursor, even if another primitive captured the mouse. Primitives which
+// accept the data retrieve it with GetDrag() when they receive MouseLeftUp.
+// The operation ends after the button was released.
+//
+// List and TreeView start dragging their items and nodes if they are
+// draggable. Other primitives call StartDrag() from their mouse handler, e.g.
+// when the mouse is moved with the left button pressed.
+func StartDrag(source Primitive, data interface{}) {
+	drag.Lock()
+	defer drag.Unlock()
+
+	drag.payload = &DragPayload{Source: source, Data: data}
+	drag.target = nil
+}
+
+// GetDrag returns the data which is being dragged, or nil if no drag-and-drop
+// operation is in progress.
+func GetDrag() *DragPayload {
+	drag.RLock()
+	defer drag.RUnlock()
+
+	return drag.payload
+}
+
+// CancelDrag ends the drag-and-drop operation in progress without dropping the
+// data.
+func CancelDrag() {
+	drag.Lock()
+	defer drag.Unlock()
+
+	drag.payload, drag.target = nil, nil
+}
+
+// setDropTarget sets the primitive which draws a drop indicator, or nil if
+// there is none.
+func setDropTarget(target Primitive) {
+	drag.Lock()
+	defer drag.Unlock()
+
+	drag.target = target
+}
+
+// isDropTarget returns whether the primitive draws a drop indicator.
+func isDropTarget(target Primitive) bool {
+	drag.RLock()
+	defer drag.RUnlock()
+
+	return drag.payload != nil && drag.target == target
+}
+
+// drawDropIndicator draws a drop indicator line under the given row.
+func drawDropIndicator(screen tcell.Screen, x, y, width int, color tcell.Color) {
+	for column := x; column < x+width; column++ {
+		mainc, combc, style, _ := screen.GetContent(column, y)
+		screen.SetContent(column, y, mainc, combc, style.Foreground(color).Underline(true))
+	}
+}
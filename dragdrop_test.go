@@ -0,0 +1,140 @@
+package crtview
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestDragAndDrop(t *testing.T) {
+	t.Parallel()
+
+	l := NewList()
+	l.ShowSecondaryText(false)
+	l.SetDraggable(true)
+	for _, text := range []string{"a", "b", "c", "d"} {
+		l.AddItem(NewListItem(text))
+	}
+
+	root := NewTreeNode("root")
+	x, y := NewTreeNode("x"), NewTreeNode("y")
+	x1 := NewTreeNode("x1")
+	x.AddChild(x1)
+	root.SetChildren([]*TreeNode{x, y})
+	tr := NewTreeView()
+	tr.SetRoot(root)
+	tr.SetCurrentNode(root)
+	tr.SetDraggable(true)
+
+	flex := NewFlex()
+	flex.AddItem(l, 0, 1, false)
+	flex.AddItem(tr, 0, 1, false)
+	flex.SetRect(0, 0, 40, 10)
+
+	app, err := newTestApp(flex)
+	if err != nil {
+		t.Fatalf("failed to initialize Application: %s", err)
+	}
+	flex.Draw(app.screen)
+
+	mouse := func(x, y int, buttons tcell.ButtonMask) {
+		event := tcell.NewEventMouse(x, y, buttons, tcell.ModNone)
+		_, isMouseDownAction := app.fireMouseActions(event)
+		app.lastMouseButtons = buttons
+		if isMouseDownAction {
+			app.mouseDownX, app.mouseDownY = x, y
+		}
+	}
+	listTexts := func() (texts string) {
+		for _, item := range l.GetItems() {
+			texts += item.GetMainText()
+		}
+		return texts
+	}
+
+	// Reorder the list.
+	var from, to int
+	l.SetMovedFunc(func(f, t int) {
+		from, to = f, t
+	})
+	mouse(0, 0, tcell.Button1)
+	mouse(0, 1, tcell.Button1)
+	if payload := GetDrag(); payload == nil || payload.Source != l || payload.Data != l.GetItem(0) {
+		t.Fatalf("failed to start dragging list item: got %v", payload)
+	}
+	mouse(0, 2, tcell.Button1)
+	flex.Draw(app.screen)
+	_, _, style, _ := app.screen.GetContent(0, 2)
+	if _, _, attributes := style.Decompose(); attributes&tcell.AttrUnderline == 0 {
+		t.Errorf("failed to draw drop indicator below item c")
+	}
+	mouse(0, 2, 0)
+	if GetDrag() != nil {
+		t.Errorf("failed to end dragging: expected no payload")
+	} else if texts := listTexts(); texts != "bcad" || from != 0 || to != 2 {
+		t.Errorf("failed to move list item: expected bcad from 0 to 2, got %s from %d to %d", texts, from, to)
+	}
+
+	// Veto a move.
+	l.SetAcceptDropFunc(func(payload *DragPayload, index int, position DropPosition) bool {
+		return index != 0
+	})
+	mouse(0, 3, tcell.Button1)
+	mouse(0, 0, tcell.Button1)
+	mouse(0, 0, 0)
+	if texts := listTexts(); texts != "bcad" {
+		t.Errorf("failed to veto move: expected bcad, got %s", texts)
+	}
+
+	// Move a tree node into another node.
+	var moved, parent *TreeNode
+	var index int
+	tr.SetMovedFunc(func(node, p *TreeNode, i int) {
+		moved, parent, index = node, p, i
+	})
+	mouse(24, 3, tcell.Button1) // y
+	mouse(26, 1, tcell.Button1) // Onto x
+	mouse(26, 1, 0)
+	if moved != y || parent != x || index != 1 {
+		t.Fatalf("failed to move tree node: expected y as child 1 of x, got %v, %v, %d", moved, parent, index)
+	} else if children := x.GetChildren(); len(children) != 2 || children[1] != y || len(root.GetChildren()) != 1 {
+		t.Errorf("failed to move tree node: incorrect children")
+	}
+
+	// Never move a node into its own subtree.
+	flex.Draw(app.screen)
+	mouse(24, 1, tcell.Button1) // x
+	mouse(28, 2, tcell.Button1) // Onto x1
+	mouse(28, 2, 0)
+	if len(root.GetChildren()) != 1 || root.GetChildren()[0] != x {
+		t.Errorf("failed to refuse moving node into its own subtree")
+	}
+
+	// Never move a node into a node whose children were not loaded.
+	x1.SetLoadFunc(func(node *TreeNode) {
+		node.SetChildren([]*TreeNode{NewTreeNode("loaded")})
+	})
+	flex.Draw(app.screen)
+	mouse(28, 3, tcell.Button1) // y
+	mouse(30, 2, tcell.Button1) // Onto x1
+	mouse(30, 2, 0)
+	if children := x.GetChildren(); len(children) != 2 || children[1] != y || x1.IsLoaded() {
+		t.Errorf("failed to refuse moving node into unloaded node")
+	}
+
+	// Drag a list item onto a tree node.
+	var payload *DragPayload
+	var target *TreeNode
+	var position DropPosition
+	tr.SetDroppedFunc(func(p *DragPayload, n *TreeNode, pos DropPosition) {
+		payload, target, position = p, n, pos
+	})
+	mouse(0, 0, tcell.Button1) // b
+	mouse(26, 1, tcell.Button1)
+	mouse(26, 1, 0)
+	if payload == nil || payload.Source != l || payload.Data != l.GetItem(0) || target != x || position != DropOnto {
+		t.Errorf("failed to drop list item on tree node: got %v on %v at %d", payload, target, position)
+	} else if texts := listTexts(); texts != "bcad" {
+		t.Errorf("failed to keep dragged list item: expected bcad, got %s", texts)
+	}
+}
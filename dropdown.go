@@ -791,3 +791,30 @@ func (d *DropDown) MouseHandler() func(action MouseAction, event *tcell.EventMou
 		return
 	})
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme. Colors which were set explicitly are kept.
+func (d *DropDown) ApplyTheme(previous, theme *Theme) {
+	d.Box.ApplyTheme(previous, theme)
+
+	d.Lock()
+	defer d.Unlock()
+
+	themeColor(&d.labelColor, previous.SecondaryTextColor, theme.SecondaryTextColor)
+	themeColor(&d.fieldBackgroundColor, previous.ContrastBackgroundColor, theme.ContrastBackgroundColor)
+	themeColor(&d.fieldTextColor, previous.PrimaryTextColor, theme.PrimaryTextColor)
+	themeColor(&d.prefixTextColor, previous.ContrastSecondaryTextColor, theme.ContrastSecondaryTextColor)
+	themeRune(&d.dropDownSymbol, previous.DropDownSymbol, theme.DropDownSymbol)
+	if d.abbreviationChars == previous.DropDownAbbreviationChars {
+		d.abbreviationChars = theme.DropDownAbbreviationChars
+	}
+
+	l := d.list
+	l.Box.applyTheme(previous.MoreContrastBackgroundColor, theme.MoreContrastBackgroundColor, previous, theme)
+	l.Lock()
+	themeColor(&l.mainTextColor, previous.PrimitiveBackgroundColor, theme.PrimitiveBackgroundColor)
+	themeColor(&l.selectedTextColor, previous.PrimitiveBackgroundColor, theme.PrimitiveBackgroundColor)
+	themeColor(&l.selectedBackgroundColor, previous.PrimaryTextColor, theme.PrimaryTextColor)
+	themeColor(&l.scrollBarColor, previous.ScrollBarColor, theme.ScrollBarColor)
+	l.Unlock()
+}
@@ -290,3 +290,18 @@ func (f *Flex) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 		return
 	})
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme, for the flex and all of its items. Colors which were set
+// explicitly are kept.
+func (f *Flex) ApplyTheme(previous, theme *Theme) {
+	f.Box.ApplyTheme(previous, theme)
+
+	f.RLock()
+	items := append([]*flexItem(nil), f.items...)
+	f.RUnlock()
+
+	for _, item := range items {
+		ApplyTheme(item.Item, previous, theme)
+	}
+}
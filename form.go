@@ -380,6 +380,24 @@ func (f *Form) AddPasswordField(label, value string, fieldWidth int, mask rune,
 	f.items = append(f.items, passwordField)
 }
 
+// AddTextArea adds a text area to the form. It has a label, an optional
+// initial value, a field width (a value of 0 extends it as far as possible), a
+// field height (the number of rows of the input area) and an (optional)
+// callback function which is invoked when the text area's text has changed.
+func (f *Form) AddTextArea(label, value string, fieldWidth, fieldHeight int, changed func(text string)) {
+	f.Lock()
+	defer f.Unlock()
+
+	textArea := NewTextArea()
+	textArea.SetLabel(label)
+	textArea.SetText(value)
+	textArea.SetFieldWidth(fieldWidth)
+	textArea.SetFieldHeight(fieldHeight)
+	textArea.SetChangedFunc(changed)
+
+	f.items = append(f.items, textArea)
+}
+
 // AddDropDownSimple adds a drop-down element to the form. It has a label, options,
 // and an (optional) callback function which is invoked when an option was
 // selected. The initial option may be a negative value to indicate that no
@@ -1108,3 +1126,29 @@ func setFormItemAttributes(item FormItem, attrs *FormItemAttributes) {
 		item.SetFinishedFunc(attrs.FinishedFunc)
 	}
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme, for the form and all of its items and buttons. Colors which were
+// set explicitly are kept.
+func (f *Form) ApplyTheme(previous, theme *Theme) {
+	f.Box.ApplyTheme(previous, theme)
+
+	f.Lock()
+	themeColor(&f.labelColor, previous.SecondaryTextColor, theme.SecondaryTextColor)
+	themeColor(&f.fieldBackgroundColor, previous.ContrastBackgroundColor, theme.ContrastBackgroundColor)
+	themeColor(&f.fieldTextColor, previous.PrimaryTextColor, theme.PrimaryTextColor)
+	themeColor(&f.buttonBackgroundColor, previous.ButtonBackgroundColor, theme.ButtonBackgroundColor)
+	themeColor(&f.buttonTextColor, previous.ButtonTextColor, theme.ButtonTextColor)
+	themeColor(&f.buttonBackgroundColorFocused, previous.ButtonFocusedBackgroundColor, theme.ButtonFocusedBackgroundColor)
+	themeColor(&f.buttonTextColorFocused, previous.ButtonTextFocusedColor, theme.ButtonTextFocusedColor)
+	items := append([]FormItem(nil), f.items...)
+	buttons := append([]*Button(nil), f.buttons...)
+	f.Unlock()
+
+	for _, item := range items {
+		ApplyTheme(item, previous, theme)
+	}
+	for _, button := range buttons {
+		button.ApplyTheme(previous, theme)
+	}
+}
@@ -189,3 +189,16 @@ func (f *Frame) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 		return f.primitive.MouseHandler()(action, event, setFocus)
 	})
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme, for the frame and its primitive. Colors which were set explicitly
+// are kept.
+func (f *Frame) ApplyTheme(previous, theme *Theme) {
+	f.Box.ApplyTheme(previous, theme)
+
+	f.RLock()
+	primitive := f.primitive
+	f.RUnlock()
+
+	ApplyTheme(primitive, previous, theme)
+}
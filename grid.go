@@ -310,20 +310,22 @@ func (g *Grid) HasFocus() bool {
 // InputHandler returns the handler for this primitive.
 func (g *Grid) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return g.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		keys := g.GetKeys()
+
 		g.Lock()
 		defer g.Unlock()
 
-		if HitShortcut(event, Keys.MoveFirst, Keys.MoveFirst2) {
+		if HitShortcut(event, keys.MoveFirst, keys.MoveFirst2) {
 			g.rowOffset, g.columnOffset = 0, 0
-		} else if HitShortcut(event, Keys.MoveLast, Keys.MoveLast2) {
+		} else if HitShortcut(event, keys.MoveLast, keys.MoveLast2) {
 			g.rowOffset = math.MaxInt32
-		} else if HitShortcut(event, Keys.MoveUp, Keys.MoveUp2, Keys.MovePreviousField) {
+		} else if HitShortcut(event, keys.MoveUp, keys.MoveUp2, keys.MovePreviousField) {
 			g.rowOffset--
-		} else if HitShortcut(event, Keys.MoveDown, Keys.MoveDown2, Keys.MoveNextField) {
+		} else if HitShortcut(event, keys.MoveDown, keys.MoveDown2, keys.MoveNextField) {
 			g.rowOffset++
-		} else if HitShortcut(event, Keys.MoveLeft, Keys.MoveLeft2) {
+		} else if HitShortcut(event, keys.MoveLeft, keys.MoveLeft2) {
 			g.columnOffset--
-		} else if HitShortcut(event, Keys.MoveRight, Keys.MoveRight2) {
+		} else if HitShortcut(event, keys.MoveRight, keys.MoveRight2) {
 			g.columnOffset++
 		}
 	})
@@ -713,3 +715,19 @@ func (g *Grid) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 		return
 	})
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme, for the grid and all of its items. Colors which were set
+// explicitly are kept.
+func (g *Grid) ApplyTheme(previous, theme *Theme) {
+	g.Box.ApplyTheme(previous, theme)
+
+	g.Lock()
+	themeColor(&g.bordersColor, previous.GraphicsColor, theme.GraphicsColor)
+	items := append([]*gridItem(nil), g.items...)
+	g.Unlock()
+
+	for _, item := range items {
+		ApplyTheme(item.Item, previous, theme)
+	}
+}
@@ -198,3 +198,9 @@ func (nt *InfoText) SetMaximised(maximised bool) {}
 func (nt *InfoText) GetWidgetType() string {
 	return ""
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme. Colors which were set explicitly are kept.
+func (nt *InfoText) ApplyTheme(previous, theme *Theme) {
+	nt.TextView.ApplyTheme(previous, theme)
+}
@@ -3,8 +3,8 @@ package crtview
 import (
 	"bytes"
 	"math"
-	"regexp"
 	"sync"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/gdamore/tcell/v2"
@@ -22,13 +22,40 @@ import (
 //   - Right arrow: Move right by one character.
 //   - Home, Ctrl-A, Alt-a: Move to the beginning of the line.
 //   - End, Ctrl-E, Alt-e: Move to the end of the line.
-//   - Alt-left, Alt-b: Move left by one word.
-//   - Alt-right, Alt-f: Move right by one word.
+//   - Alt-left, Ctrl-left, Alt-b: Move to the beginning of the word before
+//     the cursor.
+//   - Alt-right, Ctrl-right, Alt-f: Move to the end of the word after the
+//     cursor.
 //   - Backspace: Delete the character before the cursor.
 //   - Delete: Delete the character after the cursor.
-//   - Ctrl-K: Delete from the cursor to the end of the line.
-//   - Ctrl-W: Delete the last word before the cursor.
-//   - Ctrl-U: Delete the entire line.
+//   - Ctrl-K: Kill the text from the cursor to the end of the line.
+//   - Ctrl-W: Kill the text before the cursor up to the previous space.
+//   - Alt-Backspace: Kill the word before the cursor.
+//   - Alt-d: Kill the word after the cursor.
+//   - Ctrl-U: Kill the entire line.
+//   - Ctrl-Y: Yank the text which was killed last.
+//   - Alt-y: Replace the text which was just yanked with the text killed
+//     before it.
+//   - Ctrl-Z, Ctrl-_: Undo the last edit.
+//   - Alt-/: Redo the last undone edit.
+//   - Shift and any of the movement keys above: Select text.
+//   - Up arrow, Down arrow: Recall the previous or the next entry of the
+//     history (see SetHistory()).
+//   - Ctrl-R: Search the history backwards for the text typed next. Ctrl-R
+//     finds the next older entry, Enter accepts the entry, Escape and Ctrl-G
+//     restore the text before the search, and other keys end the search and
+//     edit the entry.
+//
+// As in readline, words consist of letters and digits. Killed text is deleted
+// and kept in the kill ring of the input field, from which it may be yanked.
+// Text killed by consecutive keys is kept as one entry. Characters typed in a
+// row are undone together.
+//
+// Text may also be selected by dragging the mouse over it. Typed text replaces
+// the selected text. The selected text of fields which are not masked may be
+// copied to the clipboard of the application (see Application.SetClipboard).
+// Pasted text is inserted at once if bracketed paste mode is enabled (see
+// Application.EnableBracketedPaste).
 type InputField struct {
 	*Box
 
@@ -83,6 +110,12 @@ type InputField struct {
 	// The text color of the note below the input field.
 	fieldNoteTextColor tcell.Color
 
+	// The text color of selected text.
+	selectedTextColor tcell.Color
+
+	// The background color of selected text.
+	selectedBackgroundColor tcell.Color
+
 	// The note to show below the input field.
 	fieldNote []byte
 
@@ -101,6 +134,43 @@ type InputField struct {
 	// The cursor position as a byte index into the text string.
 	cursorPos int
 
+	// The byte index at which the selection starts, the cursor being at its
+	// other end, or -1 if no text is selected.
+	selectionStart int
+
+	// Whether text is being selected with the mouse.
+	dragging bool
+
+	// The edits which may be undone and redone.
+	edits editHistory
+
+	// The text which was killed and may be yanked.
+	kills killRing
+
+	// The history of the text entered into the input field, or nil.
+	history *InputHistory
+
+	// The index of the history entry which is shown, or -1 if the user is not
+	// walking through the history.
+	historyIndex int
+
+	// The text which was entered before the user walked through or searched
+	// the history.
+	historyText []byte
+
+	// Whether the user is searching the history.
+	searching bool
+
+	// The text searched for in the history.
+	searchQuery string
+
+	// The index of the history entry which contains the search query, or the
+	// number of entries if none does.
+	searchIndex int
+
+	// Whether no entry contains the search query.
+	searchFailed bool
+
 	// An optional autocomplete function which receives the current text of the
 	// input field and returns a slice of ListItems to be displayed in a drop-down
 	// selection. Items' main text is displayed in the autocomplete list. When
@@ -155,6 +225,11 @@ func NewInputField() *InputField {
 		autocompleteListSelectedBackgroundColor: Styles.PrimaryTextColor,
 		autocompleteSuggestionTextColor:         Styles.ContrastPrimaryTextColor,
 		fieldNoteTextColor:                      Styles.SecondaryTextColor,
+		selectedTextColor:                       Styles.PrimitiveBackgroundColor,
+		selectedBackgroundColor:                 Styles.PrimaryTextColor,
+		selectionStart:                          -1,
+		kills:                                   newKillRing(),
+		historyIndex:                            -1,
 		labelColorFocused:                       ColorUnset,
 		fieldBackgroundColorFocused:             ColorUnset,
 		fieldTextColorFocused:                   ColorUnset,
@@ -162,12 +237,18 @@ func NewInputField() *InputField {
 	}
 }
 
-// SetText sets the current text of the input field.
+// SetText sets the current text of the input field and moves the cursor to the
+// end of the text. Edits made before cannot be undone anymore. Use
+// ReplaceText() to keep them.
 func (i *InputField) SetText(text string) {
 	i.Lock()
 
 	i.text = []byte(text)
 	i.cursorPos = len(text)
+	i.selectionStart = -1
+	i.edits.clear()
+	i.historyIndex = -1
+	i.searching = false
 	if i.changed != nil {
 		i.Unlock()
 		i.changed(text)
@@ -176,6 +257,32 @@ func (i *InputField) SetText(text string) {
 	}
 }
 
+// ReplaceText replaces the text between the byte indices start and end with
+// the given text and moves the cursor to the end of the new text. Unlike
+// SetText(), the edit may be undone by the user. Indices beyond the text are
+// moved to its end, so ReplaceText(0, math.MaxInt32, text) replaces the entire
+// text.
+func (i *InputField) ReplaceText(start, end int, text string) {
+	i.Lock()
+
+	if end > len(i.text) {
+		end = len(i.text)
+	}
+	if start < 0 {
+		start = 0
+	} else if start > end {
+		start = end
+	}
+	i.replace(start, end, []byte(text), false)
+	newText := string(i.text)
+	if i.changed != nil {
+		i.Unlock()
+		i.changed(newText)
+	} else {
+		i.Unlock()
+	}
+}
+
 // GetText returns the current text of the input field.
 func (i *InputField) GetText() string {
 	i.RLock()
@@ -327,6 +434,82 @@ func (i *InputField) SetAutocompleteSuggestionTextColor(color tcell.Color) {
 	i.autocompleteSuggestionTextColor = color
 }
 
+// SetSelectedTextColor sets the text color of selected text.
+func (i *InputField) SetSelectedTextColor(color tcell.Color) {
+	i.Lock()
+	defer i.Unlock()
+
+	i.selectedTextColor = color
+}
+
+// SetSelectedBackgroundColor sets the background color of selected text.
+func (i *InputField) SetSelectedBackgroundColor(color tcell.Color) {
+	i.Lock()
+	defer i.Unlock()
+
+	i.selectedBackgroundColor = color
+}
+
+// GetSelection returns the byte indices of the beginning and the end of the
+// selected text. Both are equal to the cursor position if no text is selected.
+func (i *InputField) GetSelection() (start, end int) {
+	i.RLock()
+	defer i.RUnlock()
+
+	return i.selection()
+}
+
+// GetSelectedText returns the selected text.
+func (i *InputField) GetSelectedText() string {
+	i.RLock()
+	defer i.RUnlock()
+
+	start, end := i.selection()
+	return string(i.text[start:end])
+}
+
+// selection returns the byte indices of the beginning and the end of the
+// selected text, or the cursor position twice if no text is selected.
+func (i *InputField) selection() (start, end int) {
+	if i.selectionStart < 0 || i.selectionStart > len(i.text) {
+		return i.cursorPos, i.cursorPos
+	} else if i.selectionStart < i.cursorPos {
+		return i.selectionStart, i.cursorPos
+	}
+	return i.cursorPos, i.selectionStart
+}
+
+// replace replaces the text between the given byte indices, moves the cursor
+// to the end of the new text and records the edit so it may be undone. Edits
+// which type characters are undone together with the characters typed right
+// before them.
+func (i *InputField) replace(start, end int, text []byte, typing bool) {
+	i.edits.record(textEdit{text: i.text, cursorPos: i.cursorPos}, typing)
+
+	newText := make([]byte, 0, len(i.text)-(end-start)+len(text))
+	newText = append(newText, i.text[:start]...)
+	newText = append(newText, text...)
+	i.text = append(newText, i.text[end:]...)
+	i.cursorPos = start + len(text)
+	i.selectionStart = -1
+}
+
+// undo restores the text before the last edit.
+func (i *InputField) undo() {
+	if edit, ok := i.edits.undo(textEdit{text: i.text, cursorPos: i.cursorPos}); ok {
+		i.text, i.cursorPos = edit.text, edit.cursorPos
+		i.selectionStart = -1
+	}
+}
+
+// redo restores the text before the last undo.
+func (i *InputField) redo() {
+	if edit, ok := i.edits.redo(textEdit{text: i.text, cursorPos: i.cursorPos}); ok {
+		i.text, i.cursorPos = edit.text, edit.cursorPos
+		i.selectionStart = -1
+	}
+}
+
 // SetFieldNoteTextColor sets the text color of the note.
 func (i *InputField) SetFieldNoteTextColor(color tcell.Color) {
 	i.Lock()
@@ -393,6 +576,7 @@ func (i *InputField) SetCursorPosition(cursorPos int) {
 	defer i.Unlock()
 
 	i.cursorPos = cursorPos
+	i.selectionStart = -1
 }
 
 // SetMaskCharacter sets a character that masks user input on a screen. A value
@@ -571,16 +755,19 @@ func (i *InputField) Draw(screen tcell.Screen) {
 		return
 	}
 
-	// Draw label.
-	if i.labelWidth > 0 {
-		labelWidth := i.labelWidth
+	// Draw label. The prompt of a history search replaces it.
+	label, labelWidth := i.label, i.labelWidth
+	if i.searching {
+		label, labelWidth = EscapeBytes([]byte(i.searchPrompt())), 0
+	}
+	if labelWidth > 0 {
 		if labelWidth > rightLimit-x {
 			labelWidth = rightLimit - x
 		}
-		Print(screen, i.label, x, y, labelWidth, AlignLeft, labelColor)
+		Print(screen, label, x, y, labelWidth, AlignLeft, labelColor)
 		x += labelWidth
 	} else {
-		_, drawnWidth := Print(screen, i.label, x, y, rightLimit-x, AlignLeft, labelColor)
+		_, drawnWidth := Print(screen, label, x, y, rightLimit-x, AlignLeft, labelColor)
 		x += drawnWidth
 	}
 
@@ -659,6 +846,20 @@ func (i *InputField) Draw(screen tcell.Screen) {
 			drawnText = EscapeBytes(text[i.offset:])
 			Print(screen, drawnText, x, y, fieldWidth, AlignLeft, fieldTextColor)
 		}
+		// Draw selection.
+		if start, end := i.selection(); start < end && i.maskCharacter == 0 {
+			selectedStyle := tcell.StyleDefault.Background(i.selectedBackgroundColor).Foreground(i.selectedTextColor)
+			iterateString(string(text[i.offset:]), func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth int) bool {
+				if screenPos+screenWidth > fieldWidth {
+					return true
+				}
+				if pos := i.offset + textPos; pos >= start && pos < end {
+					screen.SetContent(x+screenPos, y, main, comb, selectedStyle)
+				}
+				return false
+			})
+		}
+
 		// Draw suggestion
 		if i.maskCharacter == 0 && len(i.autocompleteListSuggestion) > 0 {
 			Print(screen, i.autocompleteListSuggestion, x+runewidth.StringWidth(string(drawnText)), y, fieldWidth-runewidth.StringWidth(string(drawnText)), AlignLeft, i.autocompleteSuggestionTextColor)
@@ -719,17 +920,44 @@ func (i *InputField) InputHandler() func(event *tcell.EventKey, setFocus func(p
 		defer func() {
 			i.Lock()
 			newText := i.text
+			searching := i.searching
 			i.Unlock()
 
 			if !bytes.Equal(newText, currentText) {
-				i.Autocomplete()
+				if !searching {
+					i.Autocomplete()
+				}
 				if i.changed != nil {
 					i.changed(string(i.text))
 				}
 			}
 		}()
 
-		// Movement functions.
+		// Text killed by consecutive keys is collected, and only the text
+		// yanked by the last key may be replaced.
+		killing, yankStart := i.kills.startKey()
+
+		// Search the history.
+		if i.searching && i.handleSearchKey(event) {
+			i.Unlock()
+			return
+		}
+
+		// Movement functions. Movements with the Shift key select text, unless
+		// the text is masked.
+		selecting := event.Modifiers()&tcell.ModShift > 0 && i.maskCharacter == 0
+		move := func(movement func()) {
+			if !selecting {
+				i.selectionStart = -1
+			} else if i.selectionStart < 0 {
+				i.selectionStart = i.cursorPos
+			}
+			movement()
+			if i.selectionStart == i.cursorPos {
+				i.selectionStart = -1
+			}
+			i.edits.typing = false
+		}
 		home := func() { i.cursorPos = 0 }
 		end := func() { i.cursorPos = len(i.text) }
 		moveLeft := func() {
@@ -745,24 +973,64 @@ func (i *InputField) InputHandler() func(event *tcell.EventKey, setFocus func(p
 			})
 		}
 		moveWordLeft := func() {
-			i.cursorPos = len(regexRightWord.ReplaceAll(i.text[:i.cursorPos], nil))
+			i.cursorPos = wordLeft(i.text, i.cursorPos)
 		}
 		moveWordRight := func() {
-			i.cursorPos = len(i.text) - len(regexLeftWord.ReplaceAll(i.text[i.cursorPos:], nil))
+			i.cursorPos = wordRight(i.text, i.cursorPos)
+		}
+
+		// Insert text function. The text replaces the selected text, if any.
+		// Returns whether or not the text is accepted.
+		insert := func(text []byte, typing bool) bool {
+			start, end := i.selection()
+			newText := make([]byte, 0, len(i.text)-(end-start)+len(text))
+			newText = append(append(append(newText, i.text[:start]...), text...), i.text[end:]...)
+			lastChar, _ := utf8.DecodeLastRune(text)
+			if i.accept != nil && !i.accept(string(newText), lastChar) {
+				return false
+			}
+			i.replace(start, end, text, typing)
+			return true
 		}
 
 		// Add character function. Returns whether or not the rune character is
 		// accepted.
 		add := func(r rune) bool {
-			newText := append(append(i.text[:i.cursorPos], []byte(string(r))...), i.text[i.cursorPos:]...)
-			if i.accept != nil && !i.accept(string(newText), r) {
+			return insert([]byte(string(r)), !unicode.IsSpace(r))
+		}
+
+		// Delete the selected text. Returns whether or not text was selected.
+		deleteSelection := func() bool {
+			start, end := i.selection()
+			i.selectionStart = -1
+			if start == end {
 				return false
 			}
-			i.text = newText
-			i.cursorPos += len(string(r))
+			i.replace(start, end, nil, false)
 			return true
 		}
 
+		// Kill function. Deletes the text between the given byte indices and
+		// adds it to the kill ring, or to the text killed by the last key.
+		kill := func(start, end int) {
+			i.selectionStart = -1
+			i.kills.killing = killing
+			if start >= end {
+				return
+			}
+			i.kills.add(i.text[start:end], killing, start < i.cursorPos)
+			i.replace(start, end, nil, false)
+		}
+
+		// Yank function. Inserts the text of the kill ring at the given index.
+		yank := func(index int) {
+			start, _ := i.selection()
+			if insert(i.kills.entries[index], false) {
+				i.kills.yankIndex, i.kills.yankStart = index, start
+			}
+			i.selectionStart = -1
+		}
+
 		// Finish up.
 		finish := func(key tcell.Key) {
 			if i.done != nil {
@@ -780,13 +1048,22 @@ func (i *InputField) InputHandler() func(event *tcell.EventKey, setFocus func(p
 				// We accept some Alt- key combinations.
 				switch event.Rune() {
 				case 'a': // Home.
-					home()
+					move(home)
 				case 'e': // End.
-					end()
+					move(end)
 				case 'b': // Move word left.
-					moveWordLeft()
+					move(moveWordLeft)
 				case 'f': // Move word right.
-					moveWordRight()
+					move(moveWordRight)
+				case 'd': // Kill word right.
+					kill(i.cursorPos, wordRight(i.text, i.cursorPos))
+				case 'y': // Replace yanked text.
+					if yankStart >= 0 && len(i.kills.entries) > 0 {
+						i.selectionStart = yankStart
+						yank(i.kills.previous())
+					}
+				case '/': // Redo.
+					i.redo()
 				default:
 					if !add(event.Rune()) {
 						i.Unlock()
@@ -800,45 +1077,57 @@ func (i *InputField) InputHandler() func(event *tcell.EventKey, setFocus func(p
 					return
 				}
 			}
-		case tcell.KeyCtrlU: // Delete all.
-			i.text = nil
-			i.cursorPos = 0
-		case tcell.KeyCtrlK: // Delete until the end of the line.
-			i.text = i.text[:i.cursorPos]
-		case tcell.KeyCtrlW: // Delete last word.
-			newText := append(regexRightWord.ReplaceAll(i.text[:i.cursorPos], nil), i.text[i.cursorPos:]...)
-			i.cursorPos -= len(i.text) - len(newText)
-			i.text = newText
+		case tcell.KeyCtrlU: // Kill all.
+			kill(0, len(i.text))
+		case tcell.KeyCtrlK: // Kill until the end of the line.
+			kill(i.cursorPos, len(i.text))
+		case tcell.KeyCtrlW: // Kill until the previous space.
+			kill(spaceWordLeft(i.text, i.cursorPos), i.cursorPos)
+		case tcell.KeyCtrlY: // Yank.
+			if len(i.kills.entries) > 0 {
+				yank(len(i.kills.entries) - 1)
+			}
+		case tcell.KeyCtrlZ, tcell.KeyCtrlUnderscore: // Undo.
+			i.undo()
+		case tcell.KeyCtrlR: // Search the history.
+			if i.usesHistory() {
+				i.startSearch()
+			}
 		case tcell.KeyBackspace, tcell.KeyBackspace2: // Delete character before the cursor.
-			iterateStringReverse(string(i.text[:i.cursorPos]), func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth int) bool {
-				i.text = append(i.text[:textPos], i.text[textPos+textWidth:]...)
-				i.cursorPos -= textWidth
-				return true
-			})
+			if event.Modifiers()&tcell.ModAlt > 0 {
+				kill(wordLeft(i.text, i.cursorPos), i.cursorPos)
+			} else if !deleteSelection() {
+				iterateStringReverse(string(i.text[:i.cursorPos]), func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth int) bool {
+					i.replace(textPos, textPos+textWidth, nil, false)
+					return true
+				})
+			}
 			if i.offset >= i.cursorPos {
 				i.offset = 0
 			}
 		case tcell.KeyDelete: // Delete character after the cursor.
-			iterateString(string(i.text[i.cursorPos:]), func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth int) bool {
-				i.text = append(i.text[:i.cursorPos], i.text[i.cursorPos+textWidth:]...)
-				return true
-			})
+			if !deleteSelection() {
+				iterateString(string(i.text[i.cursorPos:]), func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth int) bool {
+					i.replace(i.cursorPos, i.cursorPos+textWidth, nil, false)
+					return true
+				})
+			}
 		case tcell.KeyLeft:
-			if event.Modifiers()&tcell.ModAlt > 0 {
-				moveWordLeft()
+			if event.Modifiers()&(tcell.ModAlt|tcell.ModCtrl) > 0 {
+				move(moveWordLeft)
 			} else {
-				moveLeft()
+				move(moveLeft)
 			}
 		case tcell.KeyRight:
-			if event.Modifiers()&tcell.ModAlt > 0 {
-				moveWordRight()
+			if event.Modifiers()&(tcell.ModAlt|tcell.ModCtrl) > 0 {
+				move(moveWordRight)
 			} else {
-				moveRight()
+				move(moveRight)
 			}
 		case tcell.KeyHome, tcell.KeyCtrlA:
-			home()
+			move(home)
 		case tcell.KeyEnd, tcell.KeyCtrlE:
-			end()
+			move(end)
 		case tcell.KeyEnter: // We might be done.
 			if i.autocompleteList != nil {
 				currentItem := i.autocompleteList.GetCurrentItem()
@@ -846,13 +1135,15 @@ func (i *InputField) InputHandler() func(event *tcell.EventKey, setFocus func(p
 				if currentItem.GetSecondaryText() != "" {
 					selectionText = currentItem.GetSecondaryText()
 				}
-				i.Unlock()
-				i.SetText(selectionText)
-				i.Lock()
+				i.replace(0, len(i.text), []byte(selectionText), false)
 				i.autocompleteList = nil
 				i.autocompleteListSuggestion = nil
 				i.Unlock()
 			} else {
+				if i.usesHistory() {
+					i.history.Add(string(i.text))
+					i.historyIndex = -1
+				}
 				i.Unlock()
 				finish(key)
 			}
@@ -876,6 +1167,9 @@ func (i *InputField) InputHandler() func(event *tcell.EventKey, setFocus func(p
 				}
 				i.autocompleteList.SetCurrentItem(newEntry)
 				i.Unlock()
+			} else if key == tcell.KeyDown && i.usesHistory() {
+				i.walkHistory(1)
+				i.Unlock()
 			} else {
 				i.Unlock()
 				finish(key)
@@ -889,6 +1183,9 @@ func (i *InputField) InputHandler() func(event *tcell.EventKey, setFocus func(p
 				}
 				i.autocompleteList.SetCurrentItem(newEntry)
 				i.Unlock()
+			} else if key == tcell.KeyUp && i.usesHistory() {
+				i.walkHistory(-1)
+				i.Unlock()
 			} else {
 				i.Unlock()
 				finish(key)
@@ -905,33 +1202,143 @@ func (i *InputField) MouseHandler() func(action MouseAction, event *tcell.EventM
 	return i.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 		x, y := event.Position()
 		_, rectY, _, _ := i.GetInnerRect()
-		if !i.InRect(x, y) {
-			return false, nil
+
+		// Determine where to place the cursor.
+		position := func() int {
+			pos, offset := len(i.text), i.offset
+			if i.maskCharacter != 0 || offset > len(i.text) {
+				offset = 0 // The offset is an index into the masked text.
+			}
+			iterateString(string(i.text[offset:]), func(main rune, comb []rune, textPos int, textWidth int, screenPos int, screenWidth int) bool {
+				if x-i.fieldX < screenPos+screenWidth {
+					pos = offset + textPos
+					return true
+				}
+				return false
+			})
+			return pos
 		}
 
 		// Process mouse event.
-		if action == MouseLeftClick && y == rectY {
-			// Determine where to place the cursor.
-			if x >= i.fieldX {
-				if !iterateString(string(i.text), func(main rune, comb []rune, textPos int, textWidth int, screenPos int, screenWidth int) bool {
-					if x-i.fieldX < screenPos+screenWidth {
-						i.cursorPos = textPos
-						return true
-					}
-					return false
-				}) {
-					i.cursorPos = len(i.text)
-				}
+		switch action {
+		case MouseLeftDown:
+			if !i.InRect(x, y) || y != rectY || x < i.fieldX {
+				return false, nil
 			}
+			// Place the cursor and start selecting text.
+			i.Lock()
+			i.cursorPos = position()
+			i.selectionStart = i.cursorPos
+			i.dragging = i.maskCharacter == 0
+			i.edits.typing = false
+			i.Unlock()
 			setFocus(i)
-			consumed = true
+			return true, i
+		case MouseMove:
+			i.Lock()
+			defer i.Unlock()
+			if !i.dragging || event.Buttons()&tcell.ButtonPrimary == 0 {
+				return false, nil
+			}
+			i.cursorPos = position()
+			return true, i
+		case MouseLeftUp:
+			i.Lock()
+			defer i.Unlock()
+			if !i.dragging {
+				return false, nil
+			}
+			i.dragging = false
+			if i.selectionStart == i.cursorPos {
+				i.selectionStart = -1
+			}
+			return true, nil
+		case MouseLeftClick:
+			if i.InRect(x, y) {
+				setFocus(i)
+				return true, nil
+			}
 		}
 
-		return
+		return false, nil
 	})
 }
 
-var (
-	regexRightWord = regexp.MustCompile(`(\w*|\W)$`)
-	regexLeftWord  = regexp.MustCompile(`^(\W|\w*)`)
-)
+// CopyHandler returns the handler which copies or cuts the selected text. The
+// text of masked fields is never copied.
+func (i *InputField) CopyHandler() func(cut bool) (text string, ok bool) {
+	return func(cut bool) (text string, ok bool) {
+		i.Lock()
+		start, end := i.selection()
+		if start == end || i.maskCharacter != 0 {
+			i.Unlock()
+			return "", false
+		}
+		text = string(i.text[start:end])
+		if !cut {
+			i.Unlock()
+			return text, true
+		}
+		i.replace(start, end, nil, false)
+		if i.offset > start {
+			i.offset = start
+		}
+		newText, changed := string(i.text), i.changed
+		i.Unlock()
+
+		i.Autocomplete()
+		if changed != nil {
+			changed(newText)
+		}
+		return text, true
+	}
+}
+
+// PasteHandler returns the handler for pasted text. The text replaces the
+// selected text, or is inserted at the cursor, as a single line: line breaks
+// are replaced with spaces. The acceptance function is called once for the
+// resulting text, with the last pasted character.
+func (i *InputField) PasteHandler() func(text string, setFocus func(p Primitive)) {
+	return func(text string, setFocus func(p Primitive)) {
+		i.Lock()
+		paste := pasteLine(text)
+		start, end := i.selection()
+		newText := make([]byte, 0, len(i.text)+len(paste))
+		newText = append(append(append(newText, i.text[:start]...), paste...), i.text[end:]...)
+		lastChar, _ := utf8.DecodeLastRune(paste)
+		if len(paste) == 0 || i.accept != nil && !i.accept(string(newText), lastChar) {
+			i.Unlock()
+			return
+		}
+		i.replace(start, end, paste, false)
+		changed := i.changed
+		i.Unlock()
+
+		i.Autocomplete()
+		if changed != nil {
+			changed(string(newText))
+		}
+	}
+}
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme. Colors which were set explicitly are kept.
+func (i *InputField) ApplyTheme(previous, theme *Theme) {
+	i.Box.ApplyTheme(previous, theme)
+
+	i.Lock()
+	defer i.Unlock()
+
+	themeColor(&i.labelColor, previous.SecondaryTextColor, theme.SecondaryTextColor)
+	themeColor(&i.fieldBackgroundColor, previous.ContrastBackgroundColor, theme.ContrastBackgroundColor)
+	themeColor(&i.fieldTextColor, previous.PrimaryTextColor, theme.PrimaryTextColor)
+	themeColor(&i.placeholderTextColor, previous.ContrastSecondaryTextColor, theme.ContrastSecondaryTextColor)
+	themeColor(&i.autocompleteListTextColor, previous.PrimitiveBackgroundColor, theme.PrimitiveBackgroundColor)
+	themeColor(&i.autocompleteListBackgroundColor, previous.MoreContrastBackgroundColor, theme.MoreContrastBackgroundColor)
+	themeColor(&i.autocompleteListSelectedTextColor, previous.PrimitiveBackgroundColor, theme.PrimitiveBackgroundColor)
+	themeColor(&i.autocompleteListSelectedBackgroundColor, previous.PrimaryTextColor, theme.PrimaryTextColor)
+	themeColor(&i.autocompleteSuggestionTextColor, previous.ContrastPrimaryTextColor, theme.ContrastPrimaryTextColor)
+	themeColor(&i.fieldNoteTextColor, previous.SecondaryTextColor, theme.SecondaryTextColor)
+	themeColor(&i.selectedTextColor, previous.PrimitiveBackgroundColor, theme.PrimitiveBackgroundColor)
+	themeColor(&i.selectedBackgroundColor, previous.PrimaryTextColor, theme.PrimaryTextColor)
+}
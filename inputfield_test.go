@@ -0,0 +1,87 @@
+package crtview
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestInputField(t *testing.T) {
+	t.Parallel()
+
+	i := NewInputField()
+	key := func(k tcell.Key, r rune, mod tcell.ModMask) {
+		i.InputHandler()(tcell.NewEventKey(k, r, mod), nil)
+	}
+	typeText := func(text string) {
+		for _, r := range text {
+			key(tcell.KeyRune, r, tcell.ModNone)
+		}
+	}
+	expect := func(action, text string, pos int) {
+		t.Helper()
+		if got := i.GetText(); got != text {
+			t.Errorf("failed to %s: expected text %q, got %q", action, text, got)
+		} else if got := i.GetCursorPosition(); got != pos {
+			t.Errorf("failed to %s: expected cursor position %d, got %d", action, pos, got)
+		}
+	}
+
+	// Undo typed words together.
+	typeText("one two-3")
+	key(tcell.KeyCtrlZ, 0, tcell.ModCtrl)
+	expect("undo typed word", "one ", 4)
+	key(tcell.KeyCtrlZ, 0, tcell.ModCtrl)
+	expect("undo typed space", "one", 3)
+	key(tcell.KeyRune, '/', tcell.ModAlt)
+	key(tcell.KeyRune, '/', tcell.ModAlt)
+	expect("redo", "one two-3", 9)
+
+	// Move by words.
+	key(tcell.KeyLeft, 0, tcell.ModCtrl)
+	expect("move word left", "one two-3", 8)
+	key(tcell.KeyRune, 'b', tcell.ModAlt)
+	expect("move word left", "one two-3", 4)
+	key(tcell.KeyRune, 'b', tcell.ModAlt)
+	expect("move word left", "one two-3", 0)
+	key(tcell.KeyRight, 0, tcell.ModCtrl)
+	expect("move word right", "one two-3", 3)
+	key(tcell.KeyRune, 'f', tcell.ModAlt)
+	expect("move word right", "one two-3", 7)
+
+	// Kill and yank.
+	key(tcell.KeyRune, 'd', tcell.ModAlt)
+	expect("kill word right", "one two", 7)
+	key(tcell.KeyBackspace2, 0, tcell.ModAlt)
+	key(tcell.KeyCtrlW, 0, tcell.ModCtrl)
+	expect("kill words left", "", 0)
+	key(tcell.KeyCtrlY, 0, tcell.ModCtrl)
+	expect("yank consecutive kills", "one two-3", 9)
+	typeText(" x")
+	key(tcell.KeyCtrlK, 0, tcell.ModCtrl)
+	key(tcell.KeyCtrlU, 0, tcell.ModCtrl)
+	typeText("a")
+	key(tcell.KeyCtrlW, 0, tcell.ModCtrl)
+	key(tcell.KeyCtrlY, 0, tcell.ModCtrl)
+	expect("yank last kill", "a", 1)
+	key(tcell.KeyRune, 'y', tcell.ModAlt)
+	expect("replace yanked text", "one two-3 x", 11)
+	key(tcell.KeyCtrlUnderscore, 0, tcell.ModCtrl)
+	expect("undo yank", "a", 1)
+
+	// Replace text without losing the history.
+	var changed string
+	i.SetChangedFunc(func(text string) {
+		changed = text
+	})
+	i.ReplaceText(0, 100, "new")
+	expect("replace text", "new", 3)
+	if changed != "new" {
+		t.Errorf("failed to report replaced text: expected new, got %q", changed)
+	}
+	key(tcell.KeyCtrlZ, 0, tcell.ModCtrl)
+	expect("undo replaced text", "a", 1)
+	i.SetText("set")
+	key(tcell.KeyCtrlZ, 0, tcell.ModCtrl)
+	expect("keep set text", "set", 3)
+}
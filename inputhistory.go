@@ -0,0 +1,291 @@
+package crtview
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// InputHistory stores the text entered into input fields. The user walks
+// through the entries with the Up and Down keys and searches them with Ctrl-R
+// (see InputField.SetHistory). A history may be shared by several input
+// fields.
+//
+// Blank entries are not added. An entry which is added again is moved to the
+// end of the history, so each text is recalled only once.
+type InputHistory struct {
+	// The entries, the oldest first.
+	entries []string
+
+	// The maximum number of entries. A value of 0 means no limit.
+	maxEntries int
+
+	// The file the entries are written to, or an empty string if the history
+	// is only kept in memory.
+	path string
+
+	sync.RWMutex
+}
+
+// NewInputHistory returns a new history which is kept in memory.
+func NewInputHistory() *InputHistory {
+	return &InputHistory{}
+}
+
+// OpenInputHistory returns a new history which is read from the given file,
+// one entry per line, and written back to it whenever an entry is added. The
+// file is created when the first entry is added if it does not exist.
+func OpenInputHistory(path string) (*InputHistory, error) {
+	h := &InputHistory{path: path}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return h, nil
+		}
+		return nil, err
+	}
+	for _, entry := range strings.Split(string(data), "\n") {
+		h.add(entry)
+	}
+	return h, nil
+}
+
+// SetMaxEntries sets the maximum number of entries. The oldest entries are
+// removed when more entries are added. A value of 0 means no limit.
+func (h *InputHistory) SetMaxEntries(maxEntries int) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.maxEntries = maxEntries
+	h.clip()
+}
+
+// Add adds an entry to the end of the history, unless it is blank. If the
+// history was opened from a file, the file is rewritten and an error is
+// returned if that fails. The entry is kept in memory in any case.
+func (h *InputHistory) Add(entry string) error {
+	h.Lock()
+	defer h.Unlock()
+
+	if !h.add(entry) || h.path == "" {
+		return nil
+	}
+	return ioutil.WriteFile(h.path, []byte(strings.Join(h.entries, "\n")+"\n"), 0600)
+}
+
+// GetEntries returns the entries of the history, the oldest first.
+func (h *InputHistory) GetEntries() []string {
+	h.RLock()
+	defer h.RUnlock()
+
+	return append([]string(nil), h.entries...)
+}
+
+// Clear removes all entries from the history. The history file is not
+// changed until the next entry is added.
+func (h *InputHistory) Clear() {
+	h.Lock()
+	defer h.Unlock()
+
+	h.entries = nil
+}
+
+// add adds an entry to the end of the history, removing an equal entry. It
+// returns whether the entry was added.
+func (h *InputHistory) add(entry string) bool {
+	entry = strings.TrimRight(entry, "\r\n")
+	if strings.TrimSpace(entry) == "" || strings.ContainsAny(entry, "\r\n") {
+		return false
+	}
+	for index, existing := range h.entries {
+		if existing == entry {
+			h.entries = append(h.entries[:index:index], h.entries[index+1:]...)
+			break
+		}
+	}
+	h.entries = append(h.entries, entry)
+	h.clip()
+	return true
+}
+
+// clip removes the oldest entries if there are more than the maximum.
+func (h *InputHistory) clip() {
+	if h.maxEntries > 0 && len(h.entries) > h.maxEntries {
+		h.entries = append([]string(nil), h.entries[len(h.entries)-h.maxEntries:]...)
+	}
+}
+
+// search returns the index of the newest entry at or before the given index
+// which contains the query, the entry, and the byte index of the last
+// occurrence of the query in it. It returns -1 if no entry contains the query.
+func (h *InputHistory) search(query string, from int) (index int, entry string, pos int) {
+	h.RLock()
+	defer h.RUnlock()
+
+	if from >= len(h.entries) {
+		from = len(h.entries) - 1
+	}
+	for index = from; index >= 0; index-- {
+		if pos = strings.LastIndex(h.entries[index], query); pos >= 0 {
+			return index, h.entries[index], pos
+		}
+	}
+	return -1, "", -1
+}
+
+// length returns the number of entries.
+func (h *InputHistory) length() int {
+	h.RLock()
+	defer h.RUnlock()
+
+	return len(h.entries)
+}
+
+// SetHistory sets the history of the text entered into the input field. The
+// text is added to the history when the user presses Enter, and the user may
+// recall entries with the Up and Down keys and search them with Ctrl-R. While
+// the autocomplete list is shown, the Up and Down keys select its entries
+// instead. Without a history, the Up and Down keys finish editing, like Tab
+// and Backtab. Provide nil to remove the history.
+//
+// Input fields with a mask character (see SetMaskCharacter()) do not use the
+// history, so that passwords are neither stored nor recalled.
+//
+// Errors writing the entries to the file of the history (see
+// OpenInputHistory()) are ignored. Call InputHistory.Add() yourself to handle
+// them.
+func (i *InputField) SetHistory(history *InputHistory) {
+	i.Lock()
+	defer i.Unlock()
+
+	i.history = history
+	i.historyIndex = -1
+	i.searching = false
+}
+
+// GetHistory returns the history of the input field, or nil if it has none.
+func (i *InputField) GetHistory() *InputHistory {
+	i.RLock()
+	defer i.RUnlock()
+
+	return i.history
+}
+
+// usesHistory returns whether text is added to and recalled from the history.
+func (i *InputField) usesHistory() bool {
+	return i.history != nil && i.maskCharacter == 0
+}
+
+// walkHistory replaces the text with the previous (step -1) or the next (step
+// 1) entry of the history. The text entered before is restored after the
+// newest entry.
+func (i *InputField) walkHistory(step int) {
+	entries := i.history.GetEntries()
+	index := i.historyIndex
+	if index < 0 || index > len(entries) {
+		if step > 0 {
+			return
+		}
+		i.historyText = i.text
+		index = len(entries)
+	}
+	index += step
+	if index < 0 {
+		return // Already at the oldest entry.
+	} else if index >= len(entries) {
+		i.historyIndex = -1
+		i.setHistoryText(i.historyText)
+		return
+	}
+	i.historyIndex = index
+	i.setHistoryText([]byte(entries[index]))
+}
+
+// setHistoryText replaces the text with text recalled from the history, which
+// may be undone, and moves the cursor to the end.
+func (i *InputField) setHistoryText(text []byte) {
+	if !bytes.Equal(i.text, text) {
+		i.replace(0, len(i.text), text, false)
+	}
+	i.cursorPos = len(i.text)
+	i.selectionStart = -1
+}
+
+// startSearch starts searching the history.
+func (i *InputField) startSearch() {
+	i.searching = true
+	i.searchQuery = ""
+	i.searchIndex = i.history.length()
+	i.searchFailed = false
+	i.historyText = i.text
+	i.autocompleteList = nil
+	i.autocompleteListSuggestion = nil
+}
+
+// searchHistory shows the newest entry at or before the given index which
+// contains the search query, selecting the query in it. The text entered
+// before the search is shown while the query is empty.
+func (i *InputField) searchHistory(from int) {
+	i.searchFailed = false
+	if i.searchQuery == "" {
+		i.searchIndex = i.history.length()
+		i.setHistoryText(i.historyText)
+		return
+	}
+	index, entry, pos := i.history.search(i.searchQuery, from)
+	if index < 0 {
+		i.searchFailed = true
+		return
+	}
+	i.searchIndex = index
+	i.setHistoryText([]byte(entry))
+	i.cursorPos = pos
+	i.selectionStart = pos + len(i.searchQuery)
+}
+
+// searchPrompt returns the prompt shown while the history is searched.
+func (i *InputField) searchPrompt() string {
+	if i.searchFailed {
+		return "(failed reverse-i-search)`" + i.searchQuery + "': "
+	}
+	return "(reverse-i-search)`" + i.searchQuery + "': "
+}
+
+// handleSearchKey handles a key while the history is searched. It returns
+// false if the key ended the search and must be handled as usual.
+func (i *InputField) handleSearchKey(event *tcell.EventKey) bool {
+	switch event.Key() {
+	case tcell.KeyCtrlR:
+		if i.searchQuery != "" && !i.searchFailed {
+			i.searchHistory(i.searchIndex - 1)
+		}
+	case tcell.KeyEscape, tcell.KeyCtrlG:
+		i.searching = false
+		i.setHistoryText(i.historyText)
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if i.searchQuery != "" {
+			_, size := utf8.DecodeLastRuneInString(i.searchQuery)
+			i.searchQuery = i.searchQuery[:len(i.searchQuery)-size]
+			i.searchHistory(i.history.length())
+		}
+	case tcell.KeyRune:
+		if event.Modifiers()&tcell.ModAlt > 0 {
+			i.searching = false
+			i.selectionStart = -1
+			return false
+		}
+		i.searchQuery += string(event.Rune())
+		i.searchHistory(i.searchIndex)
+	default:
+		// Other keys end the search and are handled as usual.
+		i.searching = false
+		i.selectionStart = -1
+		return false
+	}
+	return true
+}
@@ -0,0 +1,155 @@
+package crtview
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestInputHistory(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "crtview")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "history")
+
+	// Skip blank and duplicate entries.
+	h, err := OpenInputHistory(path)
+	if err != nil {
+		t.Fatalf("failed to open history: %s", err)
+	}
+	for _, entry := range []string{"ls -l", " ", "cd /", "ls -l", "", "cat x"} {
+		if err := h.Add(entry); err != nil {
+			t.Fatalf("failed to add entry: %s", err)
+		}
+	}
+	expected := []string{"cd /", "ls -l", "cat x"}
+	if entries := h.GetEntries(); !reflect.DeepEqual(entries, expected) {
+		t.Errorf("failed to add entries: expected %q, got %q", expected, entries)
+	}
+
+	// Read the entries from the file.
+	h, err = OpenInputHistory(path)
+	if err != nil {
+		t.Fatalf("failed to open history: %s", err)
+	} else if entries := h.GetEntries(); !reflect.DeepEqual(entries, expected) {
+		t.Errorf("failed to read entries: expected %q, got %q", expected, entries)
+	}
+	h.SetMaxEntries(2)
+	if entries := h.GetEntries(); !reflect.DeepEqual(entries, expected[1:]) {
+		t.Errorf("failed to limit entries: expected %q, got %q", expected[1:], entries)
+	}
+
+	i := NewInputField()
+	i.SetHistory(NewInputHistory())
+	app, err := newTestApp(i)
+	if err != nil {
+		t.Fatalf("failed to initialize Application: %s", err)
+	}
+	var done tcell.Key
+	i.SetDoneFunc(func(key tcell.Key) {
+		done = key
+	})
+	key := func(k tcell.Key, r rune, mod tcell.ModMask) {
+		i.InputHandler()(tcell.NewEventKey(k, r, mod), nil)
+	}
+	typeText := func(text string) {
+		for _, r := range text {
+			key(tcell.KeyRune, r, tcell.ModNone)
+		}
+	}
+	expect := func(action, text string) {
+		t.Helper()
+		if got := i.GetText(); got != text {
+			t.Errorf("failed to %s: expected %q, got %q", action, text, got)
+		}
+	}
+
+	// Add entered text and walk through the history.
+	for _, entry := range []string{"ls -l", "cd /", "cat x"} {
+		typeText(entry)
+		key(tcell.KeyEnter, 0, tcell.ModNone)
+		i.SetText("")
+	}
+	typeText("e")
+	key(tcell.KeyUp, 0, tcell.ModNone)
+	expect("recall previous entry", "cat x")
+	key(tcell.KeyUp, 0, tcell.ModNone)
+	key(tcell.KeyUp, 0, tcell.ModNone)
+	key(tcell.KeyUp, 0, tcell.ModNone)
+	expect("stop at oldest entry", "ls -l")
+	key(tcell.KeyDown, 0, tcell.ModNone)
+	expect("recall next entry", "cd /")
+	key(tcell.KeyDown, 0, tcell.ModNone)
+	key(tcell.KeyDown, 0, tcell.ModNone)
+	expect("restore entered text", "e")
+	if done != tcell.KeyEnter {
+		t.Errorf("failed to finish with history: expected Enter, got %d", done)
+	}
+
+	// Search the history.
+	key(tcell.KeyCtrlR, 0, tcell.ModCtrl)
+	typeText("c")
+	expect("search history", "cat x")
+	if start, end := i.GetSelection(); start != 0 || end != 1 {
+		t.Errorf("failed to select search query: expected 0 and 1, got %d and %d", start, end)
+	}
+	key(tcell.KeyCtrlR, 0, tcell.ModCtrl)
+	expect("search older entries", "cd /")
+	i.SetRect(0, 0, 40, 1)
+	i.Draw(app.screen)
+	if line := screenLine(app.screen, 0); line != "(reverse-i-search)`c': cd /" {
+		t.Errorf("failed to draw search prompt: got %q", line)
+	}
+	key(tcell.KeyCtrlR, 0, tcell.ModCtrl)
+	typeText("x")
+	expect("keep entry when search fails", "cd /")
+	i.Draw(app.screen)
+	if line := screenLine(app.screen, 0); line != "(failed reverse-i-search)`cx': cd /" {
+		t.Errorf("failed to draw failed search prompt: got %q", line)
+	}
+	key(tcell.KeyEscape, 0, tcell.ModNone)
+	expect("cancel search", "e")
+	key(tcell.KeyCtrlR, 0, tcell.ModCtrl)
+	typeText("l")
+	key(tcell.KeyEnd, 0, tcell.ModNone)
+	typeText("a")
+	expect("edit found entry", "ls -la")
+
+	// The autocomplete list takes precedence.
+	i.SetText("")
+	i.SetAutocompleteFunc(func(text string) []*ListItem {
+		if text == "" {
+			return nil
+		}
+		return []*ListItem{NewListItem("one"), NewListItem("two")}
+	})
+	typeText("o")
+	key(tcell.KeyDown, 0, tcell.ModNone)
+	key(tcell.KeyEnter, 0, tcell.ModNone)
+	expect("select autocomplete entry", "two")
+	if entries := i.GetHistory().GetEntries(); len(entries) != 3 {
+		t.Errorf("failed to skip autocomplete selection: expected 3 entries, got %q", entries)
+	}
+
+	// Masked text is not added or recalled.
+	key(tcell.KeyEscape, 0, tcell.ModNone)
+	i.SetAutocompleteFunc(nil)
+	i.SetMaskCharacter('*')
+	i.SetText("secret")
+	key(tcell.KeyEnter, 0, tcell.ModNone)
+	if entries := i.GetHistory().GetEntries(); len(entries) != 3 {
+		t.Errorf("failed to skip masked text: expected 3 entries, got %q", entries)
+	}
+	key(tcell.KeyUp, 0, tcell.ModNone)
+	key(tcell.KeyCtrlR, 0, tcell.ModCtrl)
+	typeText("c")
+	expect("skip history of masked text", "secretc")
+}
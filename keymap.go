@@ -0,0 +1,420 @@
+package crtview
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gitlab.com/tslocum/cbind"
+)
+
+// DefaultKeys is the default keymap. It is a copy of the initial Keys.
+var DefaultKeys = Keys
+
+// EmacsKeys is a keymap with Emacs-style movement shortcuts.
+var EmacsKeys = Key{
+	Cancel: []string{"Escape", "Ctrl+G"},
+
+	Select:  []string{"Enter"},
+	Select2: []string{"Space"},
+
+	MoveUp:    []string{"Up", "Ctrl+P"},
+	MoveDown:  []string{"Down", "Ctrl+N"},
+	MoveLeft:  []string{"Left", "Ctrl+B"},
+	MoveRight: []string{"Right", "Ctrl+F"},
+
+	MoveFirst: []string{"Home", "Alt+<"},
+	MoveLast:  []string{"End", "Alt+>"},
+
+	MovePreviousField: []string{"Backtab"},
+	MoveNextField:     []string{"Tab"},
+	MovePreviousPage:  []string{"PageUp", "Alt+v"},
+	MoveNextPage:      []string{"PageDown", "Ctrl+V"},
+
+	ShowContextMenu: []string{"Alt+Enter"},
+
+	Search:         []string{"/", "Ctrl+S"},
+	SearchNext:     []string{"Alt+n"},
+	SearchPrevious: []string{"Alt+p"},
+	Filter:         []string{"Alt+f"},
+
+	GrowColumn:      []string{">"},
+	ShrinkColumn:    []string{"<"},
+	MoveColumnLeft:  []string{"Alt+Left"},
+	MoveColumnRight: []string{"Alt+Right"},
+
+	SelectUp:           []string{"Shift+Up"},
+	SelectDown:         []string{"Shift+Down"},
+	SelectLeft:         []string{"Shift+Left"},
+	SelectRight:        []string{"Shift+Right"},
+	SelectFirst:        []string{"Shift+Home"},
+	SelectLast:         []string{"Shift+End"},
+	SelectPreviousPage: []string{"Shift+PageUp"},
+	SelectNextPage:     []string{"Shift+PageDown"},
+	SelectAll:          []string{"Ctrl+A"},
+
+	Edit: []string{"F2"},
+
+	Copy:  []string{"Alt+w"},
+	Cut:   []string{"Ctrl+W"},
+	Paste: []string{"Ctrl+Y"},
+}
+
+// ViKeys is a keymap with vi-style movement shortcuts.
+var ViKeys = Key{
+	Cancel: []string{"Escape"},
+
+	Select:  []string{"Enter"},
+	Select2: []string{"Space"},
+
+	MoveUp:     []string{"Up"},
+	MoveUp2:    []string{"k"},
+	MoveDown:   []string{"Down"},
+	MoveDown2:  []string{"j"},
+	MoveLeft:   []string{"Left"},
+	MoveLeft2:  []string{"h"},
+	MoveRight:  []string{"Right"},
+	MoveRight2: []string{"l"},
+
+	MoveFirst:  []string{"Home"},
+	MoveFirst2: []string{"g"},
+	MoveLast:   []string{"End"},
+	MoveLast2:  []string{"G"},
+
+	MovePreviousField: []string{"Backtab"},
+	MoveNextField:     []string{"Tab"},
+	MovePreviousPage:  []string{"PageUp", "Ctrl+B"},
+	MoveNextPage:      []string{"PageDown", "Ctrl+F"},
+
+	ShowContextMenu: []string{"Alt+Enter"},
+
+	Search:         []string{"/"},
+	SearchNext:     []string{"n"},
+	SearchPrevious: []string{"N"},
+	Filter:         []string{"f"},
+
+	GrowColumn:      []string{">"},
+	ShrinkColumn:    []string{"<"},
+	MoveColumnLeft:  []string{"Alt+Left"},
+	MoveColumnRight: []string{"Alt+Right"},
+
+	SelectUp:           []string{"Shift+Up"},
+	SelectDown:         []string{"Shift+Down"},
+	SelectLeft:         []string{"Shift+Left"},
+	SelectRight:        []string{"Shift+Right"},
+	SelectFirst:        []string{"Shift+Home"},
+	SelectLast:         []string{"Shift+End"},
+	SelectPreviousPage: []string{"Shift+PageUp"},
+	SelectNextPage:     []string{"Shift+PageDown"},
+	SelectAll:          []string{"Ctrl+A"},
+
+	Edit: []string{"F2"},
+
+	Copy:  []string{"Ctrl+C"},
+	Cut:   []string{"Ctrl+X"},
+	Paste: []string{"Ctrl+V"},
+}
+
+// KeymapPresets are the keymaps which may be selected with the "Preset" key of
+// a keymap file.
+var KeymapPresets = map[string]*Key{
+	"default": &DefaultKeys,
+	"emacs":   &EmacsKeys,
+	"vi":      &ViKeys,
+}
+
+// overridingActions are the actions which take precedence over other actions
+// bound to the same key where they apply, e.g. SelectAll over MoveFirst in a
+// Table or List with multi-selection. Such keys are not conflicts.
+var overridingActions = map[string][]string{
+	"SelectAll": {"MoveFirst"},
+}
+
+// KeyConflict is a key which is bound to more than one action.
+type KeyConflict struct {
+	// The key, as encoded by cbind.
+	Key string
+
+	// The names of the fields of Key the key is bound to.
+	Actions []string
+}
+
+// String returns a description of the conflict.
+func (c KeyConflict) String() string {
+	return fmt.Sprintf("%s is bound to %s", c.Key, strings.Join(c.Actions, ", "))
+}
+
+// LoadKeymap reads a keymap from a JSON, TOML or YAML document. The format is
+// detected automatically. Keys are the names of the fields of Key, values are
+// lists of keys in the format of cbind:
+//
+//	Preset = "vi"
+//	MoveFirst = ["Home", "Ctrl+Home"]
+//	MoveLast2 = []
+//
+// In YAML, lists are written in flow style ([Home, Ctrl+Home]). A single key may
+// be given without brackets. The optional "Preset" key selects one of the
+// KeymapPresets the keymap is based on, "default" if not present. Fields which
+// are not present keep the values of the preset.
+//
+// Keys bound to more than one action are returned as conflicts. The keymap is
+// usable regardless, the first matching action is performed for such keys.
+//
+// The returned keymap is not applied. Assign it to Keys, or set it on a
+// primitive with SetKeys, to use it.
+func LoadKeymap(r io.Reader) (*Key, []KeyConflict, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var values map[string][]string
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		values, err = parseKeymapJSON(trimmed)
+	} else {
+		values, err = parseKeymapLines(data)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	preset := "default"
+	if p, ok := values["Preset"]; ok {
+		if len(p) != 1 {
+			return nil, nil, fmt.Errorf("invalid keymap preset: expected a single name")
+		}
+		preset = p[0]
+		delete(values, "Preset")
+	}
+	base, ok := KeymapPresets[preset]
+	if !ok {
+		names := make([]string, 0, len(KeymapPresets))
+		for name := range KeymapPresets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, nil, fmt.Errorf("unknown keymap preset %q, valid presets are: %s", preset, strings.Join(names, ", "))
+	}
+
+	keys := copyKeys(base)
+	if err := setKeymapFields(reflect.ValueOf(keys).Elem(), values); err != nil {
+		return nil, nil, err
+	}
+	return keys, keys.Conflicts(), nil
+}
+
+// Conflicts returns the keys which are bound to more than one action, sorted
+// by key. Ctrl+A is bound to both MoveFirst and SelectAll by default, which is
+// not a conflict as SelectAll only applies with multi-selection.
+func (k *Key) Conflicts() []KeyConflict {
+	actions := make(map[string][]string)
+
+	v := reflect.ValueOf(k).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Name
+		for _, key := range v.Field(i).Interface().([]string) {
+			if encoded, err := normalizeKey(key); err == nil {
+				key = encoded
+			}
+			bound := actions[key]
+			if len(bound) == 0 || bound[len(bound)-1] != name {
+				actions[key] = append(bound, name)
+			}
+		}
+	}
+
+	var conflicts []KeyConflict
+	for key, bound := range actions {
+		for _, name := range bound {
+			for _, overridden := range overridingActions[name] {
+				for index, action := range bound {
+					if action == overridden {
+						bound = append(bound[:index:index], bound[index+1:]...)
+						break
+					}
+				}
+			}
+		}
+		if len(bound) > 1 {
+			conflicts = append(conflicts, KeyConflict{Key: key, Actions: bound})
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Key < conflicts[j].Key
+	})
+	return conflicts
+}
+
+// copyKeys returns a deep copy of a keymap.
+func copyKeys(k *Key) *Key {
+	c := *k
+	v := reflect.ValueOf(&c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		field.Set(reflect.ValueOf(append([]string(nil), field.Interface().([]string)...)))
+	}
+	return &c
+}
+
+// setKeymapFields sets the fields of a keymap from parsed keymap values.
+func setKeymapFields(v reflect.Value, values map[string][]string) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	t := v.Type()
+	for _, name := range names {
+		f, ok := t.FieldByName(name)
+		if !ok {
+			valid := []string{"Preset"}
+			for i := 0; i < t.NumField(); i++ {
+				valid = append(valid, t.Field(i).Name)
+			}
+			return fmt.Errorf("unknown keymap field %q, valid fields are: %s", name, strings.Join(valid, ", "))
+		}
+
+		keys := make([]string, 0, len(values[name]))
+		for _, key := range values[name] {
+			encoded, err := normalizeKey(key)
+			if err != nil {
+				return fmt.Errorf("invalid key %q for keymap field %q", key, name)
+			}
+			if !containsString(keys, encoded) {
+				keys = append(keys, encoded)
+			}
+		}
+		v.FieldByIndex(f.Index).Set(reflect.ValueOf(keys))
+	}
+	return nil
+}
+
+// normalizeKey returns a key in the encoding of cbind, which is the encoding
+// HitShortcut compares against.
+func normalizeKey(key string) (string, error) {
+	mod, k, ch, err := cbind.Decode(key)
+	if err != nil {
+		return "", err
+	}
+	return cbind.Encode(mod, k, ch)
+}
+
+// parseKeymapJSON parses a JSON keymap document.
+func parseKeymapJSON(data []byte) (map[string][]string, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse keymap: %s", err)
+	}
+
+	values := make(map[string][]string, len(raw))
+	for name, value := range raw {
+		switch value := value.(type) {
+		case string:
+			values[name] = []string{value}
+		case []interface{}:
+			keys := make([]string, len(value))
+			for i := range value {
+				key, ok := value[i].(string)
+				if !ok {
+					return nil, fmt.Errorf("invalid value for keymap field %q: expected a list of strings", name)
+				}
+				keys[i] = key
+			}
+			values[name] = keys
+		default:
+			return nil, fmt.Errorf("invalid value for keymap field %q: expected a list of strings", name)
+		}
+	}
+	return values, nil
+}
+
+// parseKeymapLines parses a TOML or YAML keymap document.
+func parseKeymapLines(data []byte) (map[string][]string, error) {
+	parsed, err := parseThemeLines(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse keymap: %s", strings.TrimPrefix(err.Error(), "failed to parse theme: "))
+	}
+
+	values := make(map[string][]string, len(parsed))
+	for name, value := range parsed {
+		v, ok := value.(themeValue)
+		if !ok {
+			return nil, fmt.Errorf("invalid value for keymap field %q: expected a list of keys, not a section", name)
+		}
+		if v.quoted || !strings.HasPrefix(v.text, "[") {
+			values[name] = []string{v.text}
+			continue
+		}
+
+		keys, err := parseKeymapList(v.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for keymap field %q: %s", name, err)
+		}
+		values[name] = keys
+	}
+	return values, nil
+}
+
+// parseKeymapList parses a flow-style list of quoted or bare keys.
+func parseKeymapList(text string) ([]string, error) {
+	if !strings.HasSuffix(text, "]") {
+		return nil, fmt.Errorf("unterminated list %s", text)
+	}
+	text = strings.TrimSpace(text[1 : len(text)-1])
+
+	var (
+		keys  []string
+		quote byte
+		start int
+	)
+	add := func(item string) error {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			return nil
+		}
+		value, err := parseThemeScalar(item)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, value.text)
+		return nil
+	}
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			if err := add(text[start:i]); err != nil {
+				return nil, err
+			}
+			start = i + 1
+		}
+	}
+	if err := add(text[start:]); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// containsString returns whether a slice contains a string.
+func containsString(s []string, v string) bool {
+	for _, item := range s {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package crtview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+var keymapTestDocuments = map[string]string{
+	"TOML": `
+# Emacs with vi-style first and last
+Preset = "emacs"
+MoveFirst2 = ["g"]
+MoveLast2 = 'G'
+Cancel = ["escape", "Ctrl+G", "Escape"]
+`,
+	"YAML": `
+Preset: emacs
+MoveFirst2: [g]
+MoveLast2: G
+Cancel: [escape, "Ctrl+G", Escape]
+`,
+	"JSON": `{
+	"Preset": "emacs",
+	"MoveFirst2": ["g"],
+	"MoveLast2": "G",
+	"Cancel": ["escape", "Ctrl+G", "Escape"]
+}`,
+}
+
+func TestLoadKeymap(t *testing.T) {
+	t.Parallel()
+
+	for format, document := range keymapTestDocuments {
+		format, document := format, document // Capture
+
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+
+			keys, conflicts, err := LoadKeymap(strings.NewReader(document))
+			if err != nil {
+				t.Fatalf("failed to load keymap: %s", err)
+			}
+			if len(conflicts) != 0 {
+				t.Errorf("failed to load keymap: unexpected conflicts %v", conflicts)
+			}
+
+			if !HitShortcut(tcell.NewEventKey(tcell.KeyCtrlP, 0, tcell.ModCtrl), keys.MoveUp) {
+				t.Errorf("failed to load preset: expected Ctrl+P to move up, got %v", keys.MoveUp)
+			}
+			if !HitShortcut(tcell.NewEventKey(tcell.KeyRune, 'g', tcell.ModNone), keys.MoveFirst2) {
+				t.Errorf("failed to load list: expected g to move first, got %v", keys.MoveFirst2)
+			}
+			if !HitShortcut(tcell.NewEventKey(tcell.KeyRune, 'G', tcell.ModNone), keys.MoveLast2) {
+				t.Errorf("failed to load single key: expected G to move last, got %v", keys.MoveLast2)
+			}
+			if len(keys.Cancel) != 2 || keys.Cancel[0] != "Escape" {
+				t.Errorf("failed to normalize keys: expected [Escape Ctrl+G], got %v", keys.Cancel)
+			}
+		})
+	}
+}
+
+func TestLoadKeymapErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range []struct {
+		document string
+		expected string
+	}{
+		{`MoveUpp = ["Up"]`, `unknown keymap field "MoveUpp", valid fields are: Preset, Cancel, Select`},
+		{`Preset = "nano"`, `unknown keymap preset "nano", valid presets are: default, emacs, vi`},
+		{`MoveUp = ["Ctrl+Nope"]`, `invalid key "Ctrl+Nope" for keymap field "MoveUp"`},
+		{`MoveUp = ["Up"`, `unterminated list`},
+	} {
+		_, _, err := LoadKeymap(strings.NewReader(c.document))
+		if err == nil {
+			t.Errorf("failed to reject %q: expected error", c.document)
+		} else if !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("unexpected error for %q: expected %q, got %q", c.document, c.expected, err)
+		}
+	}
+}
+
+func TestKeymapConflicts(t *testing.T) {
+	t.Parallel()
+
+	for name, keys := range KeymapPresets {
+		if conflicts := keys.Conflicts(); len(conflicts) != 0 {
+			t.Errorf("unexpected conflicts in preset %s: %v", name, conflicts)
+		}
+	}
+
+	_, conflicts, err := LoadKeymap(strings.NewReader(`MoveLeft = ["Left", "Ctrl+B"]`))
+	if err != nil {
+		t.Fatalf("failed to load keymap: %s", err)
+	}
+	if len(conflicts) != 1 || conflicts[0].String() != "Ctrl+B is bound to MoveLeft, MovePreviousPage" {
+		t.Errorf("failed to report conflict: expected Ctrl+B is bound to MoveLeft, MovePreviousPage, got %v", conflicts)
+	}
+}
+
+func TestSetKeys(t *testing.T) {
+	t.Parallel()
+
+	list := NewList()
+	list.AddItem(NewListItem("a"))
+	list.AddItem(NewListItem("b"))
+
+	keys := Keys
+	keys.MoveDown2 = nil
+	list.SetKeys(&keys)
+
+	list.InputHandler()(tcell.NewEventKey(tcell.KeyRune, 'j', tcell.ModNone), nil)
+	if list.GetCurrentItemIndex() != 0 {
+		t.Errorf("failed to disable key: expected item 0, got %d", list.GetCurrentItemIndex())
+	}
+
+	list.InputHandler()(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone), nil)
+	if list.GetCurrentItemIndex() != 1 {
+		t.Errorf("failed to keep key: expected item 1, got %d", list.GetCurrentItemIndex())
+	}
+}
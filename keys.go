@@ -33,6 +33,32 @@ type Key struct {
 	MoveNextPage      []string
 
 	ShowContextMenu []string
+
+	Search         []string
+	SearchNext     []string
+	SearchPrevious []string
+	Filter         []string
+
+	GrowColumn      []string
+	ShrinkColumn    []string
+	MoveColumnLeft  []string
+	MoveColumnRight []string
+
+	SelectUp           []string
+	SelectDown         []string
+	SelectLeft         []string
+	SelectRight        []string
+	SelectFirst        []string
+	SelectLast         []string
+	SelectPreviousPage []string
+	SelectNextPage     []string
+	SelectAll          []string
+
+	Edit []string
+
+	Copy  []string
+	Cut   []string
+	Paste []string
 }
 
 // Keys defines the keyboard shortcuts of an application.
@@ -63,6 +89,32 @@ var Keys = Key{
 	MoveNextPage:      []string{"PageDown", "Ctrl+F"},
 
 	ShowContextMenu: []string{"Alt+Enter"},
+
+	Search:         []string{"/"},
+	SearchNext:     []string{"n"},
+	SearchPrevious: []string{"N"},
+	Filter:         []string{"f"},
+
+	GrowColumn:      []string{">"},
+	ShrinkColumn:    []string{"<"},
+	MoveColumnLeft:  []string{"Alt+Left"},
+	MoveColumnRight: []string{"Alt+Right"},
+
+	SelectUp:           []string{"Shift+Up"},
+	SelectDown:         []string{"Shift+Down"},
+	SelectLeft:         []string{"Shift+Left"},
+	SelectRight:        []string{"Shift+Right"},
+	SelectFirst:        []string{"Shift+Home"},
+	SelectLast:         []string{"Shift+End"},
+	SelectPreviousPage: []string{"Shift+PageUp"},
+	SelectNextPage:     []string{"Shift+PageDown"},
+	SelectAll:          []string{"Ctrl+A"},
+
+	Edit: []string{"F2"},
+
+	Copy:  []string{"Ctrl+C"},
+	Cut:   []string{"Ctrl+X"},
+	Paste: []string{"Ctrl+V"},
 }
 
 // HitShortcut returns whether the EventKey provided is present in one or more
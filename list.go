@@ -21,6 +21,21 @@ type ListItem struct {
 	sync.RWMutex
 }
 
+// ListContent provides the items of a List. Implement it to back a list by a
+// data source which is too large to be held in memory. The list only requests
+// the items it draws, and the items it searches with FindItems or for a
+// shortcut.
+//
+// The methods are called with the list locked and must not call methods of
+// the list.
+type ListContent interface {
+	// GetItemCount returns the number of items.
+	GetItemCount() int
+
+	// GetItem returns the item at the given index, which is always in range.
+	GetItem(index int) *ListItem
+}
+
 // NewListItem returns a new item for a list.
 func NewListItem(mainText string) *ListItem {
 	return &ListItem{
@@ -125,9 +140,15 @@ type List struct {
 	*Box
 	*ContextMenu
 
+	// The context menu this list shows the items of, if any.
+	menu *ContextMenu
+
 	// The items of the list.
 	items []*ListItem
 
+	// An optional content the items are read from instead.
+	content ListContent
+
 	// The index of the currently selected item.
 	currentItem int
 
@@ -197,6 +218,38 @@ type List struct {
 	// The height of the list the last time it was drawn.
 	height int
 
+	// Whether or not more than one item may be selected.
+	multiSelect bool
+
+	// The indices of the selected items when multi-selection is enabled, and
+	// the index item ranges are selected from.
+	selectedItems   map[int]bool
+	selectionAnchor int
+
+	// The text color of the selected items.
+	selectedItemsColor tcell.Color
+
+	// An optional function which is called when the user changes the selected
+	// items.
+	selectedItemsChanged func(indices []int)
+
+	// Whether or not items may be dragged, and the index of the item the left
+	// mouse button was pressed on (-1 if none).
+	draggable bool
+	dragIndex int
+
+	// The item and position the drop indicator is drawn at, and its color.
+	dropIndex          int
+	dropPosition       DropPosition
+	dropIndicatorColor tcell.Color
+
+	// Optional functions which are called when data is dragged over the list,
+	// when the user moved an item and when data from another primitive is
+	// dropped on the list.
+	acceptDrop func(payload *DragPayload, index int, position DropPosition) bool
+	moved      func(from, to int)
+	dropped    func(payload *DragPayload, index int, position DropPosition)
+
 	sync.RWMutex
 }
 
@@ -213,6 +266,10 @@ func NewList() *List {
 		scrollBarColor:          Styles.ScrollBarColor,
 		selectedBackgroundColor: Styles.PrimaryTextColor,
 		disabledItemColor:       tcell.ColorLightGrey.TrueColor(),
+		selectedItemsColor:      Styles.SecondaryTextColor,
+		dragIndex:               -1,
+		dropIndex:               -1,
+		dropIndicatorColor:      Styles.SecondaryTextColor,
 	}
 
 	l.ContextMenu = NewContextMenu(l)
@@ -231,10 +288,10 @@ func (l *List) SetCurrentItem(index int) {
 	l.Lock()
 
 	if index < 0 {
-		index = len(l.items) + index
+		index = l.itemCount() + index
 	}
-	if index >= len(l.items) {
-		index = len(l.items) - 1
+	if index >= l.itemCount() {
+		index = l.itemCount() - 1
 	}
 	if index < 0 {
 		index = 0
@@ -245,8 +302,8 @@ func (l *List) SetCurrentItem(index int) {
 
 	l.updateOffset()
 
-	if index != previousItem && index < len(l.items) && l.changed != nil {
-		item := l.items[index]
+	if index != previousItem && index < l.itemCount() && l.changed != nil {
+		item := l.itemAt(index)
 		l.Unlock()
 		l.changed(index, item)
 	} else {
@@ -254,16 +311,73 @@ func (l *List) SetCurrentItem(index int) {
 	}
 }
 
+// SetContent sets a content the items of the list are read from, instead of
+// the items added with AddItem and InsertItem. The functions which add and
+// remove items have no effect on the items shown while a content is set.
+// Provide nil to show the added items again.
+func (l *List) SetContent(content ListContent) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.content = content
+	l.selectedItems = nil
+	l.currentItem = 0
+	l.itemOffset = 0
+	l.columnOffset = 0
+}
+
+// GetContent returns the content of the list, or nil if it shows the items
+// added with AddItem and InsertItem.
+func (l *List) GetContent() ListContent {
+	l.RLock()
+	defer l.RUnlock()
+
+	return l.content
+}
+
+// itemCount returns the number of items shown.
+func (l *List) itemCount() int {
+	if l.content != nil {
+		return l.content.GetItemCount()
+	}
+	return len(l.items)
+}
+
+// itemAt returns the item shown at the given index, which must be in range.
+func (l *List) itemAt(index int) *ListItem {
+	if l.content != nil {
+		return l.content.GetItem(index)
+	}
+	return l.items[index]
+}
+
+// measuredItems returns the range of items whose widths determine the width
+// of the list: all items, or only the visible items of a content.
+func (l *List) measuredItems() (from, to int) {
+	if l.content == nil {
+		return 0, len(l.items)
+	}
+
+	from, to = l.itemOffset, l.itemOffset+l.height
+	if count := l.content.GetItemCount(); to > count {
+		to = count
+	}
+	if from > to {
+		from = to
+	}
+	return from, to
+}
+
 // GetCurrentItem returns the currently selected list item,
 // Returns nil if no item is selected.
 func (l *List) GetCurrentItem() *ListItem {
 	l.RLock()
 	defer l.RUnlock()
 
-	if len(l.items) == 0 || l.currentItem >= len(l.items) {
+	if l.itemCount() == 0 || l.currentItem >= l.itemCount() {
 		return nil
 	}
-	return l.items[l.currentItem]
+	return l.itemAt(l.currentItem)
 }
 
 // GetCurrentItemIndex returns the index of the currently selected list item,
@@ -274,7 +388,8 @@ func (l *List) GetCurrentItemIndex() int {
 	return l.currentItem
 }
 
-// GetItems returns all list items.
+// GetItems returns all list items added with AddItem and InsertItem. The items
+// of a content set with SetContent are not included.
 func (l *List) GetItems() []*ListItem {
 	l.RLock()
 	defer l.RUnlock()
@@ -310,6 +425,7 @@ func (l *List) RemoveItem(index int) {
 
 	// Remove item.
 	l.items = append(l.items[:index], l.items[index+1:]...)
+	l.shiftSelectedItems(index, -1)
 
 	// If there is nothing left, we're done.
 	if len(l.items) == 0 {
@@ -574,6 +690,7 @@ func (l *List) InsertItem(index int, item *ListItem) {
 	if l.currentItem < len(l.items) && l.currentItem >= index {
 		l.currentItem++
 	}
+	l.shiftSelectedItems(index, 1)
 
 	// Insert item (make space for the new item, then shift and insert).
 	l.items = append(l.items, nil)
@@ -595,10 +712,10 @@ func (l *List) InsertItem(index int, item *ListItem) {
 // GetItem returns the ListItem at the given index.
 // Returns nil when index is out of bounds.
 func (l *List) GetItem(index int) *ListItem {
-	if index > len(l.items)-1 {
+	if index > l.itemCount()-1 {
 		return nil
 	}
-	return l.items[index]
+	return l.itemAt(index)
 }
 
 // GetItemCount returns the number of items in the list.
@@ -606,7 +723,7 @@ func (l *List) GetItemCount() int {
 	l.RLock()
 	defer l.RUnlock()
 
-	return len(l.items)
+	return l.itemCount()
 }
 
 // GetItemText returns an item's texts (main and secondary). Panics if the index
@@ -614,7 +731,7 @@ func (l *List) GetItemCount() int {
 func (l *List) GetItemText(index int) (main, secondary string) {
 	l.RLock()
 	defer l.RUnlock()
-	return string(l.items[index].mainText), string(l.items[index].secondaryText)
+	return string(l.itemAt(index).mainText), string(l.itemAt(index).secondaryText)
 }
 
 // SetItemText sets an item's main and secondary text. Panics if the index is
@@ -623,7 +740,7 @@ func (l *List) SetItemText(index int, main, secondary string) {
 	l.Lock()
 	defer l.Unlock()
 
-	item := l.items[index]
+	item := l.itemAt(index)
 	item.mainText = []byte(main)
 	item.secondaryText = []byte(secondary)
 }
@@ -634,7 +751,7 @@ func (l *List) SetItemEnabled(index int, enabled bool) {
 	l.Lock()
 	defer l.Unlock()
 
-	item := l.items[index]
+	item := l.itemAt(index)
 	item.disabled = !enabled
 }
 
@@ -664,7 +781,8 @@ func (l *List) FindItems(mainSearch, secondarySearch string, mustContainBoth, ig
 	mainSearchBytes := []byte(mainSearch)
 	secondarySearchBytes := []byte(secondarySearch)
 
-	for index, item := range l.items {
+	for index := 0; index < l.itemCount(); index++ {
+		item := l.itemAt(index)
 		mainText := item.mainText
 		secondaryText := item.secondaryText
 		if ignoreCase {
@@ -690,6 +808,7 @@ func (l *List) Clear() {
 	defer l.Unlock()
 
 	l.items = nil
+	l.selectedItems = nil
 	l.currentItem = 0
 	l.itemOffset = 0
 	l.columnOffset = 0
@@ -699,14 +818,19 @@ func (l *List) Clear() {
 func (l *List) Focus(delegate func(p Primitive)) {
 	l.Box.Focus(delegate)
 	if l.ContextMenu.open {
-		delegate(l.ContextMenu.list)
+		l.ContextMenu.l.RLock()
+		list := l.ContextMenu.focusList()
+		l.ContextMenu.l.RUnlock()
+		delegate(list)
 	}
 }
 
 // HasFocus returns whether or not this primitive has focus.
 func (l *List) HasFocus() bool {
 	if l.ContextMenu.open {
-		return l.ContextMenu.list.HasFocus()
+		l.ContextMenu.l.RLock()
+		defer l.ContextMenu.l.RUnlock()
+		return l.ContextMenu.menuHasFocus()
 	}
 
 	l.RLock()
@@ -722,8 +846,8 @@ func (l *List) Transform(tr Transformation) {
 
 	l.transform(tr)
 
-	if l.currentItem != previousItem && l.currentItem < len(l.items) && l.changed != nil {
-		item := l.items[l.currentItem]
+	if l.currentItem != previousItem && l.currentItem < l.itemCount() && l.changed != nil {
+		item := l.itemAt(l.currentItem)
 		l.Unlock()
 		l.changed(l.currentItem, item)
 	} else {
@@ -748,7 +872,7 @@ func (l *List) transform(tr Transformation) {
 		l.itemOffset = 0
 		decreasing = true
 	case TransformLastItem:
-		l.currentItem = len(l.items) - 1
+		l.currentItem = l.itemCount() - 1
 	case TransformPreviousItem:
 		l.currentItem--
 		decreasing = true
@@ -762,24 +886,24 @@ func (l *List) transform(tr Transformation) {
 		l.itemOffset += pageItems
 	}
 
-	for i := 0; i < len(l.items); i++ {
+	for i := 0; i < l.itemCount(); i++ {
 		if l.currentItem < 0 {
 			if l.wrapAround {
-				l.currentItem = len(l.items) - 1
+				l.currentItem = l.itemCount() - 1
 			} else {
 				l.currentItem = 0
 				l.itemOffset = 0
 			}
-		} else if l.currentItem >= len(l.items) {
+		} else if l.currentItem >= l.itemCount() {
 			if l.wrapAround {
 				l.currentItem = 0
 				l.itemOffset = 0
 			} else {
-				l.currentItem = len(l.items) - 1
+				l.currentItem = l.itemCount() - 1
 			}
 		}
 
-		item := l.items[l.currentItem]
+		item := l.itemAt(l.currentItem)
 		if !item.disabled && (item.shortcut > 0 || len(item.mainText) > 0 || len(item.secondaryText) > 0) {
 			break
 		}
@@ -815,12 +939,12 @@ func (l *List) updateOffset() {
 	}
 
 	if l.showSecondaryText {
-		if l.itemOffset > len(l.items)-(l.height/2) {
-			l.itemOffset = len(l.items) - l.height/2
+		if l.itemOffset > l.itemCount()-(l.height/2) {
+			l.itemOffset = l.itemCount() - l.height/2
 		}
 	} else {
-		if l.itemOffset > len(l.items)-l.height {
-			l.itemOffset = len(l.items) - l.height
+		if l.itemOffset > l.itemCount()-l.height {
+			l.itemOffset = l.itemCount() - l.height
 		}
 	}
 
@@ -830,7 +954,9 @@ func (l *List) updateOffset() {
 
 	// Maximum width of item text
 	maxWidth := 0
-	for _, option := range l.items {
+	from, to := l.measuredItems()
+	for index := from; index < to; index++ {
+		option := l.itemAt(index)
 		strWidth := TaggedTextWidth(option.mainText)
 		secondaryWidth := TaggedTextWidth(option.secondaryText)
 		if secondaryWidth > strWidth {
@@ -849,8 +975,8 @@ func (l *List) updateOffset() {
 	addWidth := 0
 	if l.scrollBarVisibility == ScrollBarAlways ||
 		(l.scrollBarVisibility == ScrollBarAuto &&
-			((!l.showSecondaryText && len(l.items) > l.innerHeight) ||
-				(l.showSecondaryText && len(l.items) > l.innerHeight/2))) {
+			((!l.showSecondaryText && l.itemCount() > l.innerHeight) ||
+				(l.showSecondaryText && l.itemCount() > l.innerHeight/2))) {
 		addWidth = 1
 	}
 
@@ -894,8 +1020,9 @@ func (l *List) Draw(screen tcell.Screen) {
 
 	// Do we show any shortcuts?
 	var showShortcuts bool
-	for _, item := range l.items {
-		if item.shortcut != 0 {
+	from, to := l.measuredItems()
+	for index := from; index < to; index++ {
+		if l.itemAt(index).shortcut != 0 {
 			showShortcuts = true
 			x += 4
 			width -= 4
@@ -908,18 +1035,16 @@ func (l *List) Draw(screen tcell.Screen) {
 		l.updateOffset()
 	}
 
-	scrollBarCursor := int(float64(len(l.items)) * (float64(l.itemOffset) / float64(len(l.items)-height)))
+	scrollBarCursor := int(float64(l.itemCount()) * (float64(l.itemOffset) / float64(l.itemCount()-height)))
 
 	// Draw the list items.
-	for index, item := range l.items {
-		if index < l.itemOffset {
-			continue
-		}
-
+	for index := l.itemOffset; index < l.itemCount(); index++ {
 		if y >= bottomLimit {
 			break
 		}
 
+		item := l.itemAt(index)
+
 		mainText := item.mainText
 		secondaryText := item.secondaryText
 		if l.columnOffset > 0 {
@@ -938,7 +1063,7 @@ func (l *List) Draw(screen tcell.Screen) {
 		if len(item.mainText) == 0 && len(item.secondaryText) == 0 && item.shortcut == 0 { // Divider
 			Print(screen, bytes.Repeat([]byte(string(tcell.RuneHLine)), width+l.paddingLeft+l.paddingRight), x-l.paddingLeft, y, width+l.paddingLeft+l.paddingRight, AlignLeft, l.mainTextColor)
 
-			RenderScrollBar(screen, l.scrollBarVisibility, scrollBarX, y, scrollBarHeight, len(l.items), scrollBarCursor, index-l.itemOffset, l.hasFocus, l.scrollBarColor)
+			RenderScrollBar(screen, l.scrollBarVisibility, scrollBarX, y, scrollBarHeight, l.itemCount(), scrollBarCursor, index-l.itemOffset, l.hasFocus, l.scrollBarColor)
 			y++
 			continue
 		} else if item.disabled {
@@ -950,7 +1075,7 @@ func (l *List) Draw(screen tcell.Screen) {
 			// Main text.
 			Print(screen, mainText, x, y, width, AlignLeft, l.disabledItemColor)
 
-			RenderScrollBar(screen, l.scrollBarVisibility, scrollBarX, y, scrollBarHeight, len(l.items), scrollBarCursor, index-l.itemOffset, l.hasFocus, l.scrollBarColor)
+			RenderScrollBar(screen, l.scrollBarVisibility, scrollBarX, y, scrollBarHeight, l.itemCount(), scrollBarCursor, index-l.itemOffset, l.hasFocus, l.scrollBarColor)
 			y++
 			continue
 		}
@@ -961,7 +1086,11 @@ func (l *List) Draw(screen tcell.Screen) {
 		}
 
 		// Main text.
-		Print(screen, mainText, x, y, width, AlignLeft, l.mainTextColor)
+		if l.multiSelect && l.selectedItems[index] {
+			PrintStyle(screen, mainText, x, y, width, AlignLeft, tcell.StyleDefault.Foreground(l.selectedItemsColor).Bold(true))
+		} else {
+			Print(screen, mainText, x, y, width, AlignLeft, l.mainTextColor)
+		}
 
 		// Background color of selected text.
 		if index == l.currentItem && (!l.selectedFocusOnly || hasFocus) {
@@ -983,7 +1112,7 @@ func (l *List) Draw(screen tcell.Screen) {
 			}
 		}
 
-		RenderScrollBar(screen, l.scrollBarVisibility, scrollBarX, y, scrollBarHeight, len(l.items), scrollBarCursor, index-l.itemOffset, l.hasFocus, l.scrollBarColor)
+		RenderScrollBar(screen, l.scrollBarVisibility, scrollBarX, y, scrollBarHeight, l.itemCount(), scrollBarCursor, index-l.itemOffset, l.hasFocus, l.scrollBarColor)
 
 		y++
 
@@ -995,7 +1124,7 @@ func (l *List) Draw(screen tcell.Screen) {
 		if l.showSecondaryText {
 			Print(screen, secondaryText, x, y, width, AlignLeft, l.secondaryTextColor)
 
-			RenderScrollBar(screen, l.scrollBarVisibility, scrollBarX, y, scrollBarHeight, len(l.items), scrollBarCursor, index-l.itemOffset, l.hasFocus, l.scrollBarColor)
+			RenderScrollBar(screen, l.scrollBarVisibility, scrollBarX, y, scrollBarHeight, l.itemCount(), scrollBarCursor, index-l.itemOffset, l.hasFocus, l.scrollBarColor)
 
 			y++
 		}
@@ -1003,37 +1132,18 @@ func (l *List) Draw(screen tcell.Screen) {
 
 	// Overdraw scroll bar when necessary.
 	for y < bottomLimit {
-		RenderScrollBar(screen, l.scrollBarVisibility, scrollBarX, y, scrollBarHeight, len(l.items), scrollBarCursor, bottomLimit-y, l.hasFocus, l.scrollBarColor)
+		RenderScrollBar(screen, l.scrollBarVisibility, scrollBarX, y, scrollBarHeight, l.itemCount(), scrollBarCursor, bottomLimit-y, l.hasFocus, l.scrollBarColor)
 
 		y++
 	}
 
+	// Draw the drop indicator.
+	if l.dropIndex >= 0 && isDropTarget(l) {
+		l.drawDropIndicator(screen)
+	}
+
 	// Draw context menu.
 	if hasFocus && l.ContextMenu.open {
-		ctx := l.ContextMenuList()
-
-		// What's the longest option text?
-		maxWidth := 0
-		for _, option := range ctx.items {
-			strWidth := TaggedTextWidth(option.mainText)
-			if option.shortcut != 0 {
-				strWidth += 4
-			}
-			if strWidth > maxWidth {
-				maxWidth = strWidth
-			}
-		}
-
-		lheight := len(ctx.items)
-		lwidth := maxWidth
-
-		// Add space for borders
-		lwidth += 2
-		lheight += 2
-
-		lwidth += ctx.paddingLeft + ctx.paddingRight
-		lheight += ctx.paddingTop + ctx.paddingBottom
-
 		cx, cy := l.ContextMenu.x, l.ContextMenu.y
 		if cx < 0 || cy < 0 {
 			offsetX := 7
@@ -1048,37 +1158,23 @@ func (l *List) Draw(screen tcell.Screen) {
 			cx, cy = x+offsetX, y+offsetY
 		}
 
-		_, sheight := screen.Size()
-		if cy+lheight >= sheight && cy-2 > lheight-cy {
-			for i := (cy + lheight) - sheight; i > 0; i-- {
-				cy--
-				if cy+lheight < sheight {
-					break
-				}
-			}
-			if cy < 0 {
-				cy = 0
-			}
-		}
-		if cy+lheight >= sheight {
-			lheight = sheight - cy
-		}
-
-		if ctx.scrollBarVisibility == ScrollBarAlways || (ctx.scrollBarVisibility == ScrollBarAuto && len(ctx.items) > lheight) {
-			lwidth++ // Add space for scroll bar
-		}
-
-		ctx.SetRect(cx, cy, lwidth, lheight)
-		ctx.Draw(screen)
+		l.ContextMenu.draw(screen, cx, cy, -1)
 	}
 }
 
 // InputHandler returns the handler for this primitive.
 func (l *List) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return l.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		if l.menu != nil && l.menu.handleKey(event, setFocus) {
+			return
+		}
+
+		keys := l.GetKeys()
+
 		l.Lock()
 
-		if HitShortcut(event, Keys.Cancel) {
+		previouslySelectedItems := l.selectedItemsSnapshot()
+		if HitShortcut(event, keys.Cancel) {
 			if l.ContextMenu.open {
 				l.Unlock()
 
@@ -1093,9 +1189,11 @@ func (l *List) InputHandler() func(event *tcell.EventKey, setFocus func(p Primit
 				l.Unlock()
 			}
 			return
-		} else if HitShortcut(event, Keys.Select, Keys.Select2) {
-			if l.currentItem >= 0 && l.currentItem < len(l.items) {
-				item := l.items[l.currentItem]
+		} else if l.multiSelect && HitShortcut(event, keys.Select2) {
+			l.toggleItem()
+		} else if HitShortcut(event, keys.Select, keys.Select2) {
+			if l.currentItem >= 0 && l.currentItem < l.itemCount() {
+				item := l.itemAt(l.currentItem)
 				if !item.disabled {
 					if item.selected != nil {
 						l.Unlock()
@@ -1109,9 +1207,9 @@ func (l *List) InputHandler() func(event *tcell.EventKey, setFocus func(p Primit
 					}
 				}
 			}
-		} else if HitShortcut(event, Keys.ShowContextMenu) {
+		} else if HitShortcut(event, keys.ShowContextMenu) {
 			defer l.ContextMenu.show(l.currentItem, -1, -1, setFocus)
-		} else if len(l.items) == 0 {
+		} else if l.itemCount() == 0 {
 			l.Unlock()
 			return
 		}
@@ -1120,12 +1218,12 @@ func (l *List) InputHandler() func(event *tcell.EventKey, setFocus func(p Primit
 			ch := event.Rune()
 			if ch != ' ' {
 				// It's not a space bar. Is it a shortcut?
-				for index, item := range l.items {
-					if !item.disabled && item.shortcut == ch {
+				for index := 0; index < l.itemCount(); index++ {
+					if item := l.itemAt(index); !item.disabled && item.shortcut == ch {
 						// We have a shortcut.
 						l.currentItem = index
 
-						item := l.items[l.currentItem]
+						item := l.itemAt(l.currentItem)
 						if item.selected != nil {
 							l.Unlock()
 							item.selected()
@@ -1146,28 +1244,52 @@ func (l *List) InputHandler() func(event *tcell.EventKey, setFocus func(p Primit
 
 		previousItem := l.currentItem
 
-		if HitShortcut(event, Keys.MoveFirst, Keys.MoveFirst2) {
+		extend := l.multiSelect && HitShortcut(event, keys.SelectUp, keys.SelectDown, keys.SelectFirst, keys.SelectLast, keys.SelectPreviousPage, keys.SelectNextPage)
+		if extend {
+			switch {
+			case HitShortcut(event, keys.SelectUp):
+				l.transform(TransformPreviousItem)
+			case HitShortcut(event, keys.SelectDown):
+				l.transform(TransformNextItem)
+			case HitShortcut(event, keys.SelectFirst):
+				l.transform(TransformFirstItem)
+			case HitShortcut(event, keys.SelectLast):
+				l.transform(TransformLastItem)
+			case HitShortcut(event, keys.SelectPreviousPage):
+				l.transform(TransformPreviousPage)
+			default:
+				l.transform(TransformNextPage)
+			}
+			l.selectItemRange()
+		} else if l.multiSelect && HitShortcut(event, keys.SelectAll) {
+			l.selectAllItems()
+		} else if HitShortcut(event, keys.MoveFirst, keys.MoveFirst2) {
 			l.transform(TransformFirstItem)
-		} else if HitShortcut(event, Keys.MoveLast, Keys.MoveLast2) {
+		} else if HitShortcut(event, keys.MoveLast, keys.MoveLast2) {
 			l.transform(TransformLastItem)
-		} else if HitShortcut(event, Keys.MoveUp, Keys.MoveUp2, Keys.MovePreviousField) {
+		} else if HitShortcut(event, keys.MoveUp, keys.MoveUp2, keys.MovePreviousField) {
 			l.transform(TransformPreviousItem)
-		} else if HitShortcut(event, Keys.MoveDown, Keys.MoveDown2, Keys.MoveNextField) {
+		} else if HitShortcut(event, keys.MoveDown, keys.MoveDown2, keys.MoveNextField) {
 			l.transform(TransformNextItem)
-		} else if HitShortcut(event, Keys.MoveLeft, Keys.MoveLeft2) {
+		} else if HitShortcut(event, keys.MoveLeft, keys.MoveLeft2) {
 			l.columnOffset--
 			l.updateOffset()
-		} else if HitShortcut(event, Keys.MoveRight, Keys.MoveRight2) {
+		} else if HitShortcut(event, keys.MoveRight, keys.MoveRight2) {
 			l.columnOffset++
 			l.updateOffset()
-		} else if HitShortcut(event, Keys.MovePreviousPage) {
+		} else if HitShortcut(event, keys.MovePreviousPage) {
 			l.transform(TransformPreviousPage)
-		} else if HitShortcut(event, Keys.MoveNextPage) {
+		} else if HitShortcut(event, keys.MoveNextPage) {
 			l.transform(TransformNextPage)
 		}
 
-		if l.currentItem != previousItem && l.currentItem < len(l.items) && l.changed != nil {
-			item := l.items[l.currentItem]
+		if l.multiSelect && !extend {
+			l.selectionAnchor = l.currentItem
+		}
+		l.notifySelectedItemsChanged(previouslySelectedItems)
+
+		if l.currentItem != previousItem && l.currentItem < l.itemCount() && l.changed != nil {
+			item := l.itemAt(l.currentItem)
 			l.Unlock()
 			l.changed(l.currentItem, item)
 		} else {
@@ -1190,7 +1312,7 @@ func (l *List) indexAtY(y int) int {
 	}
 	index += l.itemOffset
 
-	if index >= len(l.items) {
+	if index >= l.itemCount() {
 		return -1
 	}
 	return index
@@ -1210,7 +1332,7 @@ func (l *List) indexAtPoint(x, y int) int {
 	}
 	index += l.itemOffset
 
-	if index >= len(l.items) {
+	if index >= l.itemCount() {
 		return -1
 	}
 	return index
@@ -1219,14 +1341,21 @@ func (l *List) indexAtPoint(x, y int) int {
 // MouseHandler returns the mouse handler for this primitive.
 func (l *List) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 	return l.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		// Drag items and drop data.
+		if consumed, capture = l.handleDrag(action, event); consumed {
+			return
+		}
+
 		l.Lock()
 
 		// Pass events to context menu.
-		if l.ContextMenuVisible() && l.ContextMenuList().InRect(event.Position()) {
-			defer l.ContextMenuList().MouseHandler()(action, event, setFocus)
-			consumed = true
-			l.Unlock()
-			return
+		if l.ContextMenuVisible() {
+			if menu := l.ContextMenu.listAtPoint(event.Position()); menu != nil {
+				defer menu.MouseHandler()(action, event, setFocus)
+				consumed = true
+				l.Unlock()
+				return
+			}
 		}
 
 		if !l.InRect(event.Position()) {
@@ -1250,9 +1379,14 @@ func (l *List) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 
 			index := l.indexAtPoint(event.Position())
 			if index != -1 {
-				item := l.items[index]
+				item := l.itemAt(index)
 				if !item.disabled {
 					l.currentItem = index
+					if l.multiSelect {
+						previouslySelectedItems := l.selectedItemsSnapshot()
+						l.clickItem(event.Modifiers())
+						l.notifySelectedItemsChanged(previouslySelectedItems)
+					}
 					if item.selected != nil {
 						l.Unlock()
 						item.selected()
@@ -1288,7 +1422,7 @@ func (l *List) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 
 			index := l.indexAtPoint(event.Position())
 			if index != -1 {
-				item := l.items[index]
+				item := l.itemAt(index)
 				if !item.disabled {
 					l.currentItem = index
 					if index != l.currentItem && l.changed != nil {
@@ -1307,10 +1441,13 @@ func (l *List) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 				_, y := event.Position()
 				index := l.indexAtY(y)
 				if index >= 0 {
-					item := l.items[index]
+					item := l.itemAt(index)
 					if !item.disabled {
 						l.currentItem = index
 					}
+					if l.menu != nil {
+						defer l.menu.hover(index, setFocus)
+					}
 				}
 
 				consumed = true
@@ -1321,7 +1458,7 @@ func (l *List) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 			}
 			consumed = true
 		case MouseScrollDown:
-			lines := len(l.items) - l.itemOffset
+			lines := l.itemCount() - l.itemOffset
 			if l.showSecondaryText {
 				lines *= 2
 			}
@@ -1335,3 +1472,22 @@ func (l *List) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 		return
 	})
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme. Colors which were set explicitly are kept.
+func (l *List) ApplyTheme(previous, theme *Theme) {
+	l.Box.ApplyTheme(previous, theme)
+
+	l.Lock()
+	themeColor(&l.mainTextColor, previous.PrimaryTextColor, theme.PrimaryTextColor)
+	themeColor(&l.secondaryTextColor, previous.TertiaryTextColor, theme.TertiaryTextColor)
+	themeColor(&l.shortcutColor, previous.SecondaryTextColor, theme.SecondaryTextColor)
+	themeColor(&l.selectedTextColor, previous.PrimitiveBackgroundColor, theme.PrimitiveBackgroundColor)
+	themeColor(&l.scrollBarColor, previous.ScrollBarColor, theme.ScrollBarColor)
+	themeColor(&l.selectedBackgroundColor, previous.PrimaryTextColor, theme.PrimaryTextColor)
+	themeColor(&l.selectedItemsColor, previous.SecondaryTextColor, theme.SecondaryTextColor)
+	themeColor(&l.dropIndicatorColor, previous.SecondaryTextColor, theme.SecondaryTextColor)
+	l.Unlock()
+
+	l.ContextMenu.applyTheme(previous, theme)
+}
@@ -1,7 +1,11 @@
 package crtview
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/gdamore/tcell/v2"
 )
 
 const (
@@ -73,3 +77,112 @@ func TestList(t *testing.T) {
 
 	l.Draw(app.screen)
 }
+
+// generatedListContent is a ListContent of generated items which counts the
+// items requested.
+type generatedListContent struct {
+	count     int
+	requested int
+}
+
+func (c *generatedListContent) GetItemCount() int {
+	return c.count
+}
+
+func (c *generatedListContent) GetItem(index int) *ListItem {
+	c.requested++
+	return NewListItem(fmt.Sprintf("item %d", index))
+}
+
+func TestListContent(t *testing.T) {
+	t.Parallel()
+
+	content := &generatedListContent{count: 500000}
+	l := NewList()
+	l.ShowSecondaryText(false)
+	l.AddItem(NewListItem(listTextA))
+	l.SetContent(content)
+
+	app, err := newTestApp(l)
+	if err != nil {
+		t.Fatalf("failed to initialize Application: %s", err)
+	}
+	l.SetRect(0, 0, 40, 10)
+
+	if l.GetItemCount() != 500000 {
+		t.Errorf("failed to use content: expected item count 500000, got %d", l.GetItemCount())
+	}
+	l.SetCurrentItem(250000)
+	content.requested = 0
+	l.Draw(app.screen)
+	if content.requested > 100 {
+		t.Errorf("failed to request visible items only: expected at most 100 items, got %d", content.requested)
+	}
+	if line := screenLine(app.screen, 9); !strings.HasPrefix(line, "item 250000 ") {
+		t.Errorf("failed to draw current item: expected item 250000, got %q", line)
+	}
+
+	l.SetContent(nil)
+	if l.GetItemCount() != 1 || l.GetCurrentItem().GetMainText() != listTextA {
+		t.Errorf("failed to restore items after removing content")
+	}
+}
+
+func TestListMultiSelect(t *testing.T) {
+	t.Parallel()
+
+	l := NewList()
+	for i := 0; i < 5; i++ {
+		l.AddItem(NewListItem(fmt.Sprintf("item %d", i)))
+	}
+	l.SetItemEnabled(3, false)
+	l.SetMultiSelect(true)
+	var changed []int
+	l.SetSelectedItemsChangedFunc(func(indices []int) {
+		changed = indices
+	})
+
+	handler := l.InputHandler()
+	handler(tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone), nil)
+	if indices := l.GetSelectedItemIndices(); fmt.Sprint(indices) != "[0]" || fmt.Sprint(changed) != "[0]" {
+		t.Errorf("failed to toggle item: expected [0], got %v (reported %v)", indices, changed)
+	}
+	handler(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModShift), nil)
+	handler(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModShift), nil)
+	if indices := l.GetSelectedItemIndices(); fmt.Sprint(indices) != "[0 1 2]" {
+		t.Errorf("failed to select range: expected [0 1 2], got %v", indices)
+	}
+
+	// Disabled items are never selected.
+	handler(tcell.NewEventKey(tcell.KeyCtrlA, 0, tcell.ModCtrl), nil)
+	if indices := l.GetSelectedItemIndices(); fmt.Sprint(indices) != "[0 1 2 4]" {
+		t.Errorf("failed to select all items: expected [0 1 2 4], got %v", indices)
+	}
+	if items := l.GetSelectedItems(); len(items) != 4 || items[3].GetMainText() != "item 4" {
+		t.Errorf("failed to get selected items: got %d items", len(items))
+	}
+
+	l.RemoveItem(0)
+	l.InsertItem(1, NewListItem("new"))
+	if indices := l.GetSelectedItemIndices(); fmt.Sprint(indices) != "[0 2 4]" {
+		t.Errorf("failed to shift selected items: expected [0 2 4], got %v", indices)
+	}
+
+	// Space no longer selects the current item.
+	var selected bool
+	l.SetSelectedFunc(func(int, *ListItem) {
+		selected = true
+	})
+	handler(tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone), nil)
+	if selected {
+		t.Errorf("failed to toggle item: Space selected the item")
+	}
+
+	// Without multi-selection, Ctrl+A moves to the first item.
+	l.SetMultiSelect(false)
+	l.SetCurrentItem(2)
+	handler(tcell.NewEventKey(tcell.KeyCtrlA, 0, tcell.ModCtrl), nil)
+	if index := l.GetCurrentItemIndex(); index != 0 {
+		t.Errorf("failed to move to first item: expected 0, got %d", index)
+	}
+}
@@ -0,0 +1,217 @@
+package crtview
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// SetDraggable sets a flag which determines whether the user may move items by
+// dragging them with the mouse. While an item is dragged over the list, a line
+// indicates where it will be dropped. Items may also be dragged to other
+// primitives which accept drops (see StartDrag), e.g. a TreeView.
+//
+// Items of a content set with SetContent are not moved by the list, the
+// handler set with SetMovedFunc() is expected to move them.
+func (l *List) SetDraggable(draggable bool) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.draggable = draggable
+}
+
+// SetDropIndicatorColor sets the color of the line which indicates where
+// dragged data is dropped.
+func (l *List) SetDropIndicatorColor(color tcell.Color) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.dropIndicatorColor = color
+}
+
+// SetAcceptDropFunc sets a handler which is called while the user drags data
+// over the list. It receives the dragged data, the index of the item the data
+// would be dropped before or after (0 if the list is empty) and the position,
+// and returns whether the data may be dropped there. Without a handler, items
+// of this list are accepted anywhere and data dragged from other primitives is
+// accepted if a handler was set with SetDroppedFunc().
+func (l *List) SetAcceptDropFunc(handler func(payload *DragPayload, index int, position DropPosition) bool) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.acceptDrop = handler
+}
+
+// SetMovedFunc sets a handler which is called after the user moved an item by
+// dragging it. It receives the previous and the new index of the item.
+func (l *List) SetMovedFunc(handler func(from, to int)) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.moved = handler
+}
+
+// SetDroppedFunc sets a handler which is called when the user drops data
+// dragged from another primitive on the list. It receives the data, the index
+// of the item it was dropped before or after (0 if the list is empty) and the
+// position.
+func (l *List) SetDroppedFunc(handler func(payload *DragPayload, index int, position DropPosition)) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.dropped = handler
+}
+
+// dropTarget returns the index of the item data dropped at the given row is
+// dropped before or after, and the position. Items of this list, dragged from
+// the given index, are dropped after items below them and before items above
+// them, so that they take the place of the item they are dropped on.
+func (l *List) dropTarget(payload *DragPayload, from, y int) (index int, position DropPosition) {
+	index = l.indexAtY(y)
+	if index < 0 {
+		// Below the last item.
+		if count := l.itemCount(); count > 0 {
+			return count - 1, DropAfter
+		}
+		return 0, DropBefore
+	}
+	if payload.Source == l && index > from {
+		return index, DropAfter
+	}
+	return index, DropBefore
+}
+
+// acceptsDrop returns whether the data may be dropped at the given position.
+// Items of this list are dragged from the given index. The list must not be
+// locked.
+func (l *List) acceptsDrop(payload *DragPayload, from, index int, position DropPosition) bool {
+	l.RLock()
+	accept, internal := l.acceptDrop, payload.Source == l
+	defaultAccept := internal || l.dropped != nil
+	l.RUnlock()
+
+	if internal && (from < 0 || index == from) {
+		return false
+	} else if accept == nil {
+		return defaultAccept
+	}
+	return accept(payload, index, position)
+}
+
+// handleDrag handles mouse events which drag items and drop data on the list.
+// It returns whether the event was consumed and the primitive which captures
+// the mouse. The list must not be locked.
+func (l *List) handleDrag(action MouseAction, event *tcell.EventMouse) (consumed bool, capture Primitive) {
+	x, y := event.Position()
+	payload := GetDrag()
+
+	switch action {
+	case MouseLeftDown:
+		l.Lock()
+		defer l.Unlock()
+
+		l.dragIndex = -1
+		if index := l.indexAtPoint(x, y); l.draggable && payload == nil && index >= 0 && !l.itemAt(index).disabled {
+			// Capture the mouse until dragging starts.
+			l.dragIndex = index
+			return true, l
+		}
+	case MouseMove:
+		l.RLock()
+		from := l.dragIndex
+		pressed := payload == nil && from >= 0 && event.Buttons()&tcell.ButtonPrimary != 0
+		var item *ListItem
+		if pressed && l.indexAtY(y) != from {
+			item = l.itemAt(from)
+		}
+		l.RUnlock()
+		if item != nil {
+			// Start dragging when the mouse leaves the pressed item.
+			StartDrag(l, item)
+			payload = GetDrag()
+		} else if pressed {
+			return true, l
+		}
+		if payload == nil || !l.InRect(x, y) {
+			return false, nil
+		}
+
+		l.RLock()
+		index, position := l.dropTarget(payload, from, y)
+		l.RUnlock()
+		if l.acceptsDrop(payload, from, index, position) {
+			l.Lock()
+			l.dropIndex, l.dropPosition = index, position
+			l.Unlock()
+			setDropTarget(l)
+		}
+		return true, nil
+	case MouseLeftUp:
+		l.Lock()
+		from := l.dragIndex
+		l.dragIndex = -1
+		l.Unlock()
+		if payload == nil || !l.InRect(x, y) {
+			return false, nil
+		}
+
+		l.RLock()
+		index, position := l.dropTarget(payload, from, y)
+		dropped := l.dropped
+		l.RUnlock()
+		if !l.acceptsDrop(payload, from, index, position) {
+			return true, nil
+		}
+		if payload.Source == l {
+			l.moveItem(from, index)
+		} else if dropped != nil {
+			dropped(payload, index, position)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// moveItem moves an item to a new index and calls the moved handler. The list
+// must not be locked.
+func (l *List) moveItem(from, to int) {
+	l.Lock()
+	if l.content == nil {
+		item := l.items[from]
+		wasSelected := l.selectedItems[from]
+		l.items = append(l.items[:from], l.items[from+1:]...)
+		l.shiftSelectedItems(from, -1)
+		l.items = append(l.items, nil)
+		copy(l.items[to+1:], l.items[to:])
+		l.items[to] = item
+		l.shiftSelectedItems(to, 1)
+		if wasSelected {
+			l.setItemSelected(to, true)
+		}
+		l.currentItem = to
+	}
+	moved := l.moved
+	l.Unlock()
+
+	if moved != nil {
+		moved(from, to)
+	}
+}
+
+// drawDropIndicator draws a line under the item data is dropped after, or
+// under the item above the item data is dropped before.
+func (l *List) drawDropIndicator(screen tcell.Screen) {
+	index := l.dropIndex
+	if l.dropPosition == DropBefore {
+		index--
+	}
+	if index < l.itemOffset {
+		return
+	}
+	x, y, width, height := l.GetInnerRect()
+	lines := 1
+	if l.showSecondaryText {
+		lines = 2
+	}
+	if row := (index-l.itemOffset+1)*lines - 1; row < height {
+		drawDropIndicator(screen, x, y+row, width, l.dropIndicatorColor)
+	}
+}
@@ -0,0 +1,212 @@
+package crtview
+
+import (
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// SetMultiSelect sets a flag which determines whether more than one item may
+// be selected. The user toggles the current item with Space, selects ranges
+// of items with Shift and the arrow keys or Shift-click, toggles items with
+// Ctrl-click and selects all items with Ctrl+A instead of moving to the first
+// item. Selected items are drawn in the color set with SetSelectedItemsColor.
+// Disabled items and dividers are never selected. Disabling multi-selection
+// clears the selected items.
+//
+// While multi-selection is enabled, Space no longer selects the current item
+// as Enter does.
+func (l *List) SetMultiSelect(multiSelect bool) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.multiSelect = multiSelect
+	if !multiSelect {
+		l.selectedItems = nil
+	}
+}
+
+// SetSelectedItemsColor sets the text color of items which are selected when
+// multi-selection is enabled.
+func (l *List) SetSelectedItemsColor(color tcell.Color) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.selectedItemsColor = color
+}
+
+// SetSelectedItemsChangedFunc sets the function which is called when the user
+// changes the selected items. The function receives the indices of all
+// selected items in ascending order.
+func (l *List) SetSelectedItemsChangedFunc(handler func(indices []int)) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.selectedItemsChanged = handler
+}
+
+// GetSelectedItems returns the items which are selected when multi-selection
+// is enabled, in the order of the list.
+func (l *List) GetSelectedItems() []*ListItem {
+	l.RLock()
+	defer l.RUnlock()
+
+	indices := l.getSelectedItems()
+	items := make([]*ListItem, len(indices))
+	for i, index := range indices {
+		items[i] = l.itemAt(index)
+	}
+	return items
+}
+
+// GetSelectedItemIndices returns the indices of the items which are selected
+// when multi-selection is enabled, in ascending order.
+func (l *List) GetSelectedItemIndices() []int {
+	l.RLock()
+	defer l.RUnlock()
+
+	return l.getSelectedItems()
+}
+
+// SetSelectedItems sets the indices of the items which are selected when
+// multi-selection is enabled.
+func (l *List) SetSelectedItems(indices []int) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.selectedItems = nil
+	for _, index := range indices {
+		l.setItemSelected(index, true)
+	}
+}
+
+// IsItemSelected returns whether the item with the given index is selected
+// when multi-selection is enabled.
+func (l *List) IsItemSelected(index int) bool {
+	l.RLock()
+	defer l.RUnlock()
+
+	return l.selectedItems[index]
+}
+
+// getSelectedItems returns the indices of the selected items in ascending
+// order.
+func (l *List) getSelectedItems() []int {
+	indices := make([]int, 0, len(l.selectedItems))
+	for index := range l.selectedItems {
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+	return indices
+}
+
+// setItemSelected selects or deselects the item with the given index.
+func (l *List) setItemSelected(index int, selected bool) {
+	if !selected {
+		delete(l.selectedItems, index)
+		return
+	}
+	if index < 0 || index >= l.itemCount() {
+		return
+	}
+	if item := l.itemAt(index); item.disabled || (len(item.mainText) == 0 && len(item.secondaryText) == 0 && item.shortcut == 0) {
+		return
+	}
+	if l.selectedItems == nil {
+		l.selectedItems = make(map[int]bool)
+	}
+	l.selectedItems[index] = true
+}
+
+// selectItemRange selects the items between the selection anchor and the
+// current item, replacing the selected items.
+func (l *List) selectItemRange() {
+	from, to := l.selectionAnchor, l.currentItem
+	if from > to {
+		from, to = to, from
+	}
+	l.selectedItems = nil
+	for index := from; index <= to; index++ {
+		l.setItemSelected(index, true)
+	}
+}
+
+// selectAllItems selects all items.
+func (l *List) selectAllItems() {
+	for index := 0; index < l.itemCount(); index++ {
+		l.setItemSelected(index, true)
+	}
+}
+
+// toggleItem toggles the selection of the current item and makes it the
+// anchor of ranges.
+func (l *List) toggleItem() {
+	l.setItemSelected(l.currentItem, !l.selectedItems[l.currentItem])
+	l.selectionAnchor = l.currentItem
+}
+
+// clickItem updates the selected items after the current item was clicked
+// with the given modifier keys: Shift selects a range of items, Ctrl toggles
+// the item and without modifiers the item becomes the anchor of ranges.
+func (l *List) clickItem(modifiers tcell.ModMask) {
+	switch {
+	case modifiers&tcell.ModShift != 0:
+		l.selectItemRange()
+	case modifiers&tcell.ModCtrl != 0:
+		l.toggleItem()
+	default:
+		l.selectionAnchor = l.currentItem
+	}
+}
+
+// shiftSelectedItems updates the selected items after an item was inserted
+// (delta 1) or removed (delta -1) at the given index.
+func (l *List) shiftSelectedItems(at, delta int) {
+	if len(l.selectedItems) == 0 {
+		return
+	}
+	selected := l.selectedItems
+	l.selectedItems = make(map[int]bool, len(selected))
+	for index := range selected {
+		switch {
+		case index < at:
+			l.selectedItems[index] = true
+		case delta < 0 && index == at:
+			// The item was removed.
+		default:
+			l.selectedItems[index+delta] = true
+		}
+	}
+}
+
+// selectedItemsSnapshot returns a copy of the selected items, to detect
+// changes.
+func (l *List) selectedItemsSnapshot() map[int]bool {
+	snapshot := make(map[int]bool, len(l.selectedItems))
+	for index := range l.selectedItems {
+		snapshot[index] = true
+	}
+	return snapshot
+}
+
+// notifySelectedItemsChanged calls the selected items changed handler if the
+// selected items differ from the given ones. The list must be locked.
+func (l *List) notifySelectedItemsChanged(previous map[int]bool) {
+	if l.selectedItemsChanged == nil {
+		return
+	}
+	changed := len(previous) != len(l.selectedItems)
+	for index := range l.selectedItems {
+		if changed {
+			break
+		}
+		changed = !previous[index]
+	}
+	if !changed {
+		return
+	}
+	handler, indices := l.selectedItemsChanged, l.getSelectedItems()
+	l.Unlock()
+	handler(indices)
+	l.Lock()
+}
@@ -0,0 +1,829 @@
+package crtview
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// MenuItem is an item of a Menu. An item either executes a function, toggles
+// its checked state or opens a submenu.
+type MenuItem struct {
+	// The label without the accelerator marker, the accelerator rune and its
+	// byte index in the label, or -1 if there is none.
+	label          string
+	accelerator    rune
+	acceleratorPos int
+
+	// The text shown right of the label, typically a keyboard shortcut.
+	shortcut string
+
+	// Whether the item is a separator line.
+	separator bool
+
+	// Whether the item may be selected.
+	disabled bool
+
+	// Whether the item shows a check mark and its state.
+	checkable, checked bool
+
+	// An optional submenu opened by the item.
+	submenu *Menu
+
+	// An optional function called when the item is selected.
+	selected func()
+
+	sync.RWMutex
+}
+
+// NewMenuItem returns a new menu item. An ampersand in the label marks the
+// following character as the item's accelerator, which selects the item when
+// its menu is open ("&Open" is selected with O). Use "&&" for a literal
+// ampersand. The function is called when the item is selected and may be nil.
+func NewMenuItem(label string, selected func()) *MenuItem {
+	i := &MenuItem{selected: selected}
+	i.label, i.accelerator, i.acceleratorPos = parseAccelerator(label)
+	return i
+}
+
+// NewMenuSeparator returns a menu item which draws a separator line.
+func NewMenuSeparator() *MenuItem {
+	return &MenuItem{separator: true, disabled: true}
+}
+
+// SetLabel sets the label of the item. See NewMenuItem for accelerators.
+func (i *MenuItem) SetLabel(label string) *MenuItem {
+	i.Lock()
+	defer i.Unlock()
+
+	i.label, i.accelerator, i.acceleratorPos = parseAccelerator(label)
+	return i
+}
+
+// GetLabel returns the label of the item, without the accelerator marker.
+func (i *MenuItem) GetLabel() string {
+	i.RLock()
+	defer i.RUnlock()
+
+	return i.label
+}
+
+// SetShortcutText sets the text shown right of the label, typically the
+// keyboard shortcut of the command the item executes. It is for display only.
+func (i *MenuItem) SetShortcutText(text string) *MenuItem {
+	i.Lock()
+	defer i.Unlock()
+
+	i.shortcut = text
+	return i
+}
+
+// SetEnabled sets whether the item may be selected. Disabled items are drawn
+// in a different color and skipped when navigating.
+func (i *MenuItem) SetEnabled(enabled bool) *MenuItem {
+	i.Lock()
+	defer i.Unlock()
+
+	i.disabled = !enabled || i.separator
+	return i
+}
+
+// IsEnabled returns whether the item may be selected.
+func (i *MenuItem) IsEnabled() bool {
+	i.RLock()
+	defer i.RUnlock()
+
+	return !i.disabled
+}
+
+// SetCheckable sets whether the item shows a check mark. Selecting a
+// checkable item toggles its checked state before its function is called.
+func (i *MenuItem) SetCheckable(checkable bool) *MenuItem {
+	i.Lock()
+	defer i.Unlock()
+
+	i.checkable = checkable
+	return i
+}
+
+// SetChecked sets the checked state of the item and makes it checkable.
+func (i *MenuItem) SetChecked(checked bool) *MenuItem {
+	i.Lock()
+	defer i.Unlock()
+
+	i.checkable = true
+	i.checked = checked
+	return i
+}
+
+// IsChecked returns whether the item is checked.
+func (i *MenuItem) IsChecked() bool {
+	i.RLock()
+	defer i.RUnlock()
+
+	return i.checked
+}
+
+// SetSubmenu sets a submenu which is opened when the item is selected.
+func (i *MenuItem) SetSubmenu(submenu *Menu) *MenuItem {
+	i.Lock()
+	defer i.Unlock()
+
+	i.submenu = submenu
+	return i
+}
+
+// GetSubmenu returns the submenu of the item, or nil if it has none.
+func (i *MenuItem) GetSubmenu() *Menu {
+	i.RLock()
+	defer i.RUnlock()
+
+	return i.submenu
+}
+
+// SetSelectedFunc sets the function which is called when the item is
+// selected.
+func (i *MenuItem) SetSelectedFunc(handler func()) *MenuItem {
+	i.Lock()
+	defer i.Unlock()
+
+	i.selected = handler
+	return i
+}
+
+// Menu is a titled list of menu items. Its items are shown in the List of a
+// ContextMenu when the menu is open.
+type Menu struct {
+	// The title without the accelerator marker, the accelerator rune and its
+	// byte index in the title, or -1 if there is none.
+	title          string
+	accelerator    rune
+	acceleratorPos int
+
+	items []*MenuItem
+
+	*ContextMenu
+
+	sync.RWMutex
+}
+
+// NewMenu returns a new menu. An ampersand in the title marks the following
+// character as the menu's accelerator, which opens the menu of a MenuBar with
+// Alt ("&File" is opened with Alt+F). Without a marker, the first letter of the
+// title is used.
+func NewMenu(title string) *Menu {
+	m := &Menu{}
+	m.title, m.accelerator, m.acceleratorPos = parseAccelerator(title)
+	if m.accelerator == 0 {
+		for index, r := range m.title {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				m.accelerator, m.acceleratorPos = unicode.ToLower(r), index
+				break
+			}
+		}
+	}
+	m.ContextMenu = NewContextMenu(nil)
+	return m
+}
+
+// GetTitle returns the title of the menu, without the accelerator marker.
+func (m *Menu) GetTitle() string {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.title
+}
+
+// AddItem adds an item to the menu.
+func (m *Menu) AddItem(item *MenuItem) *Menu {
+	m.Lock()
+	defer m.Unlock()
+
+	m.items = append(m.items, item)
+	return m
+}
+
+// AddSeparator adds a separator line to the menu.
+func (m *Menu) AddSeparator() *Menu {
+	return m.AddItem(NewMenuSeparator())
+}
+
+// GetItems returns the items of the menu.
+func (m *Menu) GetItems() []*MenuItem {
+	m.RLock()
+	defer m.RUnlock()
+
+	return append([]*MenuItem(nil), m.items...)
+}
+
+// Clear removes all items from the menu.
+func (m *Menu) Clear() *Menu {
+	m.Lock()
+	defer m.Unlock()
+
+	m.items = nil
+	return m
+}
+
+// update fills the context menu with the current state of the items and
+// returns its list.
+func (m *Menu) update() *List {
+	items := m.GetItems()
+
+	var checkable bool
+	labels := make([]string, len(items))
+	shortcuts := make([]string, len(items))
+	var labelWidth, shortcutWidth int
+	for index, item := range items {
+		item.RLock()
+		checkable = checkable || item.checkable
+		labels[index] = formatAccelerator(item.label, item.acceleratorPos)
+		if item.submenu != nil {
+			shortcuts[index] = "►"
+		} else {
+			shortcuts[index] = Escape(item.shortcut)
+		}
+		item.RUnlock()
+
+		if w := TaggedStringWidth(labels[index]); w > labelWidth {
+			labelWidth = w
+		}
+		if w := TaggedStringWidth(shortcuts[index]); w > shortcutWidth {
+			shortcutWidth = w
+		}
+	}
+
+	m.ClearContextMenu()
+	list := m.ContextMenuList()
+	for index, item := range items {
+		item.RLock()
+		separator, disabled, checked := item.separator, item.disabled, item.checked
+		item.RUnlock()
+
+		if separator {
+			m.AddContextItem("", 0, func(int) {})
+			continue
+		}
+
+		var b strings.Builder
+		if checkable {
+			if checked {
+				b.WriteString("✓ ")
+			} else {
+				b.WriteString("  ")
+			}
+		}
+		b.WriteString(labels[index])
+		if shortcutWidth > 0 {
+			b.WriteString(strings.Repeat(" ", labelWidth-TaggedStringWidth(labels[index])+2))
+			b.WriteString(strings.Repeat(" ", shortcutWidth-TaggedStringWidth(shortcuts[index])))
+			b.WriteString(shortcuts[index])
+		}
+		m.AddContextItem(b.String(), 0, func(int) {})
+		if disabled {
+			list.SetItemEnabled(index, false)
+		}
+	}
+
+	list.SetCurrentItem(0)
+	list.Transform(TransformFirstItem)
+	return list
+}
+
+// MenuBar is a horizontal bar of menus. A menu is opened with the mouse, with
+// Alt and the accelerator of the menu, or with Enter when the bar has focus.
+// While a menu is open, Up and Down select items, Left and Right switch to the
+// neighbouring menus (or close and open submenus), Enter selects an item and
+// Escape closes the menu.
+//
+// Open menus are drawn below the bar, over anything drawn before the bar. Use
+// WindowManager.SetMenuBar to show a menu bar above the windows of a window
+// manager. Open menus receive all key events passed to HandleKey, so they do
+// not need the focus. To make the accelerators work regardless of the focus,
+// call HandleKey from the application's input capture function:
+//
+//	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+//		if menuBar.HandleKey(event) {
+//			return nil
+//		}
+//		return event
+//	})
+type MenuBar struct {
+	*Box
+
+	menus []*Menu
+
+	// The open menus, starting with the menu of the bar, followed by the open
+	// submenus.
+	open []*Menu
+
+	// The index of the open menu in the bar, or of the menu highlighted when
+	// the bar has focus.
+	current int
+
+	// The x-coordinates of the menu titles as determined during the last call
+	// to Draw().
+	titleX []int
+
+	textColor               tcell.Color
+	selectedTextColor       tcell.Color
+	selectedBackgroundColor tcell.Color
+
+	sync.RWMutex
+}
+
+// NewMenuBar returns a new, empty menu bar.
+func NewMenuBar() *MenuBar {
+	m := &MenuBar{
+		Box:                     NewBox(),
+		textColor:               Styles.PrimaryTextColor,
+		selectedTextColor:       Styles.InverseTextColor,
+		selectedBackgroundColor: Styles.PrimaryTextColor,
+	}
+	m.SetBackgroundColor(Styles.ContrastBackgroundColor)
+	m.focus = m
+	return m
+}
+
+// AddMenu adds a menu to the bar.
+func (m *MenuBar) AddMenu(menu *Menu) *MenuBar {
+	m.Lock()
+	defer m.Unlock()
+
+	menu.ContextMenu.parent = m
+	m.menus = append(m.menus, menu)
+	return m
+}
+
+// GetMenus returns the menus of the bar.
+func (m *MenuBar) GetMenus() []*Menu {
+	m.RLock()
+	defer m.RUnlock()
+
+	return append([]*Menu(nil), m.menus...)
+}
+
+// SetTextColor sets the color of the menu titles.
+func (m *MenuBar) SetTextColor(color tcell.Color) *MenuBar {
+	m.Lock()
+	defer m.Unlock()
+
+	m.textColor = color
+	return m
+}
+
+// SetSelectedTextColor sets the text color of the open menu's title.
+func (m *MenuBar) SetSelectedTextColor(color tcell.Color) *MenuBar {
+	m.Lock()
+	defer m.Unlock()
+
+	m.selectedTextColor = color
+	return m
+}
+
+// SetSelectedBackgroundColor sets the background color of the open menu's
+// title.
+func (m *MenuBar) SetSelectedBackgroundColor(color tcell.Color) *MenuBar {
+	m.Lock()
+	defer m.Unlock()
+
+	m.selectedBackgroundColor = color
+	return m
+}
+
+// Open opens the menu at the given index, closing any other open menu.
+func (m *MenuBar) Open(index int) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.openMenu(index)
+}
+
+// Close closes all open menus.
+func (m *MenuBar) Close() {
+	m.Lock()
+	defer m.Unlock()
+
+	m.open = nil
+}
+
+// IsOpen returns whether a menu is open.
+func (m *MenuBar) IsOpen() bool {
+	m.RLock()
+	defer m.RUnlock()
+
+	return len(m.open) > 0
+}
+
+func (m *MenuBar) openMenu(index int) {
+	if len(m.menus) == 0 {
+		return
+	}
+	index = (index + len(m.menus)) % len(m.menus)
+
+	m.current = index
+	m.open = []*Menu{m.menus[index]}
+	m.menus[index].update()
+}
+
+// openSubmenu opens the submenu of the selected item of the deepest open
+// menu, if there is one.
+func (m *MenuBar) openSubmenu() bool {
+	item := m.selectedItem()
+	if item == nil || !item.IsEnabled() || item.GetSubmenu() == nil {
+		return false
+	}
+	submenu := item.GetSubmenu()
+	m.open = append(m.open, submenu)
+	submenu.update()
+	return true
+}
+
+// selectedItem returns the selected item of the deepest open menu.
+func (m *MenuBar) selectedItem() *MenuItem {
+	if len(m.open) == 0 {
+		return nil
+	}
+	menu := m.open[len(m.open)-1]
+	items := menu.GetItems()
+	index := menu.ContextMenuList().GetCurrentItemIndex()
+	if index < 0 || index >= len(items) {
+		return nil
+	}
+	return items[index]
+}
+
+// activate selects an item: submenus are opened, other items close the menus,
+// toggle their checked state and call their function. It returns the
+// function to call after the menu bar was unlocked.
+func (m *MenuBar) activate(item *MenuItem) func() {
+	if item == nil || !item.IsEnabled() {
+		return nil
+	}
+	if item.GetSubmenu() != nil {
+		m.openSubmenu()
+		return nil
+	}
+
+	m.open = nil
+
+	item.Lock()
+	if item.checkable {
+		item.checked = !item.checked
+	}
+	selected := item.selected
+	item.Unlock()
+
+	return selected
+}
+
+// HandleKey handles a key event: Alt and the accelerator of a menu opens the
+// menu, and all keys are handled while a menu is open. It returns whether the
+// key was handled. See MenuBar for how to use it as an input capture.
+func (m *MenuBar) HandleKey(event *tcell.EventKey) bool {
+	keys := m.GetKeys()
+
+	m.Lock()
+
+	// Alt+accelerator opens a menu, whether a menu is open or not.
+	if event.Key() == tcell.KeyRune && event.Modifiers()&tcell.ModAlt != 0 {
+		for index, menu := range m.menus {
+			if menu.accelerator != 0 && unicode.ToLower(event.Rune()) == menu.accelerator {
+				m.openMenu(index)
+				m.Unlock()
+				return true
+			}
+		}
+	}
+
+	if len(m.open) == 0 {
+		m.Unlock()
+		return false
+	}
+
+	var selected func()
+	switch {
+	case HitShortcut(event, keys.Cancel):
+		m.open = m.open[:len(m.open)-1]
+	case HitShortcut(event, keys.Select):
+		selected = m.activate(m.selectedItem())
+	case HitShortcut(event, keys.MoveLeft):
+		if len(m.open) > 1 {
+			m.open = m.open[:len(m.open)-1]
+		} else {
+			m.openMenu(m.current - 1)
+		}
+	case HitShortcut(event, keys.MoveRight):
+		if !m.openSubmenu() {
+			m.openMenu(m.current + 1)
+		}
+	case event.Key() == tcell.KeyRune && event.Modifiers()&(tcell.ModAlt|tcell.ModCtrl) == 0:
+		// Item accelerators.
+		menu := m.open[len(m.open)-1]
+		for index, item := range menu.GetItems() {
+			if item.accelerator != 0 && unicode.ToLower(event.Rune()) == item.accelerator {
+				menu.ContextMenuList().SetCurrentItem(index)
+				selected = m.activate(item)
+				break
+			}
+		}
+	default:
+		list := m.open[len(m.open)-1].ContextMenuList()
+		list.InputHandler()(event, func(Primitive) {})
+	}
+	m.Unlock()
+
+	if selected != nil {
+		selected()
+	}
+	return true
+}
+
+// Draw draws this primitive onto the screen.
+func (m *MenuBar) Draw(screen tcell.Screen) {
+	if !m.IsVisible() {
+		return
+	}
+
+	m.Box.Draw(screen)
+
+	x, y, width, _ := m.GetInnerRect()
+	right := x + width
+	hasFocus := m.Box.HasFocus()
+	background := m.GetBackgroundColor()
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.titleX = m.titleX[:0]
+	for index, menu := range m.menus {
+		title := []byte(" " + formatAccelerator(menu.title, menu.acceleratorPos) + " ")
+		titleWidth := TaggedTextWidth(title)
+		m.titleX = append(m.titleX, x)
+
+		style := tcell.StyleDefault.Foreground(m.textColor).Background(background)
+		if index == m.current && (len(m.open) > 0 || hasFocus) {
+			style = style.Foreground(m.selectedTextColor).Background(m.selectedBackgroundColor)
+			for i := 0; i < titleWidth && x+i < right; i++ {
+				screen.SetContent(x+i, y, ' ', nil, style)
+			}
+		}
+		PrintStyle(screen, title, x, y, right-x, AlignLeft, style)
+		x += titleWidth
+	}
+
+	// Draw the open menus.
+	screenWidth, screenHeight := screen.Size()
+	var parent *List
+	for index, menu := range m.open {
+		list := menu.ContextMenuList()
+
+		menuWidth, menuHeight := menuSize(list)
+		var menuX, menuY int
+		if parent == nil {
+			menuX, menuY = m.titleX[m.current], y+1
+		} else {
+			// Align the first item of the submenu with the selected item.
+			px, _, pw, _ := parent.GetRect()
+			_, iy, _, _ := parent.GetInnerRect()
+			offset, _ := parent.GetOffset()
+			menuX, menuY = px+pw, iy+parent.GetCurrentItemIndex()-offset-1-list.paddingTop
+			if menuX+menuWidth > screenWidth {
+				menuX = px - menuWidth
+			}
+		}
+		if menuX+menuWidth > screenWidth {
+			menuX = screenWidth - menuWidth
+		}
+		if menuX < 0 {
+			menuX = 0
+		}
+		if menuY+menuHeight > screenHeight {
+			menuY = screenHeight - menuHeight
+		}
+		if menuY < 0 {
+			menuY = 0
+			if menuHeight > screenHeight {
+				menuHeight = screenHeight
+			}
+		}
+
+		list.SetRect(menuX, menuY, menuWidth, menuHeight)
+		if index == len(m.open)-1 {
+			list.Box.Focus(nil)
+		} else {
+			list.Box.Blur()
+		}
+		list.Draw(screen)
+		parent = list
+	}
+}
+
+// menuSize returns the size of the list of an open menu.
+func menuSize(list *List) (width, height int) {
+	list.RLock()
+	defer list.RUnlock()
+
+	for _, item := range list.items {
+		if w := TaggedTextWidth(item.mainText); w > width {
+			width = w
+		}
+	}
+	width += 2 + list.paddingLeft + list.paddingRight
+	height = len(list.items) + 2 + list.paddingTop + list.paddingBottom
+	return width, height
+}
+
+// InputHandler returns the handler for this primitive.
+func (m *MenuBar) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return m.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		if m.HandleKey(event) {
+			return
+		}
+
+		// Navigate the bar while it has focus.
+		keys := m.GetKeys()
+
+		m.Lock()
+		defer m.Unlock()
+
+		switch {
+		case HitShortcut(event, keys.MoveLeft):
+			if len(m.menus) > 0 {
+				m.current = (m.current - 1 + len(m.menus)) % len(m.menus)
+			}
+		case HitShortcut(event, keys.MoveRight):
+			if len(m.menus) > 0 {
+				m.current = (m.current + 1) % len(m.menus)
+			}
+		case HitShortcut(event, keys.Select, keys.MoveDown):
+			m.openMenu(m.current)
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive. Unlike other
+// primitives, it also handles events outside of its rectangle while a menu is
+// open.
+func (m *MenuBar) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return m.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+
+		m.Lock()
+
+		// Open menus, the deepest first.
+		for index := len(m.open) - 1; index >= 0; index-- {
+			list := m.open[index].ContextMenuList()
+			if !list.InRect(x, y) {
+				continue
+			}
+
+			var selected func()
+			item := list.indexAtPoint(x, y)
+			if item >= 0 {
+				items := m.open[index].GetItems()
+				switch action {
+				case MouseMove, MouseLeftDown:
+					if items[item].IsEnabled() && list.GetCurrentItemIndex() != item {
+						list.SetCurrentItem(item)
+						m.open = m.open[:index+1]
+						if items[item].GetSubmenu() != nil {
+							m.openSubmenu()
+						}
+					}
+				case MouseLeftClick:
+					if items[item].IsEnabled() {
+						list.SetCurrentItem(item)
+						m.open = m.open[:index+1]
+						selected = m.activate(items[item])
+					}
+				}
+			}
+			m.Unlock()
+
+			if selected != nil {
+				selected()
+			}
+			return true, nil
+		}
+
+		// Menu titles.
+		if m.InRect(x, y) {
+			title := -1
+			for index, titleX := range m.titleX {
+				if x >= titleX {
+					title = index
+				}
+			}
+			if title >= 0 && title < len(m.menus) {
+				if x >= m.titleX[title]+TaggedStringWidth(" "+m.menus[title].title+" ") {
+					title = -1
+				}
+			}
+
+			switch action {
+			case MouseLeftDown:
+				if title >= 0 {
+					if len(m.open) > 0 && m.current == title {
+						m.open = nil
+					} else {
+						m.openMenu(title)
+					}
+				} else {
+					m.open = nil
+				}
+			case MouseMove:
+				if title >= 0 && len(m.open) > 0 && m.current != title {
+					m.openMenu(title)
+				}
+			}
+			m.Unlock()
+			return true, nil
+		}
+
+		// Clicks anywhere else close the menus.
+		if len(m.open) > 0 && (action == MouseLeftDown || action == MouseRightDown || action == MouseMiddleDown) {
+			m.open = nil
+			m.Unlock()
+			return true, nil
+		}
+
+		m.Unlock()
+		return false, nil
+	})
+}
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme, including the colors of the menus. Colors which were set
+// explicitly are kept.
+func (m *MenuBar) ApplyTheme(previous, theme *Theme) {
+	m.Box.applyTheme(previous.ContrastBackgroundColor, theme.ContrastBackgroundColor, previous, theme)
+
+	m.Lock()
+	themeColor(&m.textColor, previous.PrimaryTextColor, theme.PrimaryTextColor)
+	themeColor(&m.selectedTextColor, previous.InverseTextColor, theme.InverseTextColor)
+	themeColor(&m.selectedBackgroundColor, previous.PrimaryTextColor, theme.PrimaryTextColor)
+	menus := append([]*Menu(nil), m.menus...)
+	m.Unlock()
+
+	applied := make(map[*Menu]bool)
+	var apply func(menus []*Menu)
+	apply = func(menus []*Menu) {
+		for _, menu := range menus {
+			if applied[menu] {
+				continue
+			}
+			applied[menu] = true
+			menu.ContextMenuList().ApplyTheme(previous, theme)
+
+			var submenus []*Menu
+			for _, item := range menu.GetItems() {
+				if submenu := item.GetSubmenu(); submenu != nil {
+					submenus = append(submenus, submenu)
+				}
+			}
+			apply(submenus)
+		}
+	}
+	apply(menus)
+}
+
+// parseAccelerator removes the accelerator marker from a label and returns the
+// label, the lower case accelerator rune, or 0 if there is none, and the byte
+// index of the accelerator in the returned label, or -1 if there is none.
+func parseAccelerator(label string) (string, rune, int) {
+	var (
+		b           bytes.Buffer
+		accelerator rune
+		pos         = -1
+		marker      bool
+	)
+	for _, r := range label {
+		if marker {
+			marker = false
+			if r != '&' && accelerator == 0 {
+				accelerator, pos = unicode.ToLower(r), b.Len()
+			}
+		} else if r == '&' {
+			marker = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String(), accelerator, pos
+}
+
+// formatAccelerator escapes a label and underlines the character at the given
+// byte index, unless it is negative.
+func formatAccelerator(label string, pos int) string {
+	if pos < 0 || pos >= len(label) {
+		return Escape(label)
+	}
+	_, size := utf8.DecodeRuneInString(label[pos:])
+	next := pos + size
+	return Escape(label[:pos]) + "[::u]" + Escape(label[pos:next]) + "[::-]" + Escape(label[next:])
+}
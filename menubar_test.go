@@ -0,0 +1,166 @@
+package crtview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func newTestMenuBar(selected *[]string) *MenuBar {
+	handler := func(name string) func() {
+		return func() {
+			*selected = append(*selected, name)
+		}
+	}
+
+	recent := NewMenu("Recent")
+	recent.AddItem(NewMenuItem("&a.txt", handler("a.txt")))
+	recent.AddItem(NewMenuItem("&b.txt", handler("b.txt")))
+
+	file := NewMenu("&File")
+	file.AddItem(NewMenuItem("&Open", handler("open")).SetShortcutText("Ctrl+O"))
+	file.AddItem(NewMenuItem("&Recent", nil).SetSubmenu(recent))
+	file.AddSeparator()
+	file.AddItem(NewMenuItem("&Print", handler("print")).SetEnabled(false))
+	file.AddItem(NewMenuItem("&Quit", handler("quit")))
+
+	view := NewMenu("&View")
+	view.AddItem(NewMenuItem("&Wrap", handler("wrap")).SetChecked(true))
+
+	m := NewMenuBar()
+	m.AddMenu(file)
+	m.AddMenu(view)
+	return m
+}
+
+func TestMenuBar(t *testing.T) {
+	t.Parallel()
+
+	var selected []string
+	m := newTestMenuBar(&selected)
+	file, view := m.GetMenus()[0], m.GetMenus()[1]
+
+	key := func(k tcell.Key, r rune, mod tcell.ModMask) bool {
+		return m.HandleKey(tcell.NewEventKey(k, r, mod))
+	}
+
+	if key(tcell.KeyRune, 'f', tcell.ModNone) || m.IsOpen() {
+		t.Fatalf("failed to ignore key: expected f to be passed on while closed")
+	}
+	if !key(tcell.KeyRune, 'f', tcell.ModAlt) || !m.IsOpen() {
+		t.Fatalf("failed to open menu: expected Alt+F to open the file menu")
+	}
+
+	// Disabled items and separators are skipped.
+	key(tcell.KeyDown, 0, tcell.ModNone)
+	key(tcell.KeyDown, 0, tcell.ModNone)
+	if index := file.ContextMenuList().GetCurrentItemIndex(); index != 4 {
+		t.Errorf("failed to skip disabled items: expected item 4, got %d", index)
+	}
+	key(tcell.KeyRune, 'p', tcell.ModNone)
+	if len(selected) != 0 || !m.IsOpen() {
+		t.Errorf("failed to ignore disabled item: got %v", selected)
+	}
+
+	// Submenus are opened with Right and closed with Left.
+	key(tcell.KeyUp, 0, tcell.ModNone)
+	key(tcell.KeyRight, 0, tcell.ModNone)
+	key(tcell.KeyDown, 0, tcell.ModNone)
+	key(tcell.KeyEnter, 0, tcell.ModNone)
+	if strings.Join(selected, ",") != "b.txt" || m.IsOpen() {
+		t.Errorf("failed to select submenu item: expected b.txt, got %v", selected)
+	}
+
+	// Right moves to the next menu when the item has no submenu.
+	key(tcell.KeyRune, 'F', tcell.ModAlt)
+	key(tcell.KeyRight, 0, tcell.ModNone)
+	key(tcell.KeyRune, 'w', tcell.ModNone)
+	if strings.Join(selected, ",") != "b.txt,wrap" {
+		t.Errorf("failed to select item by accelerator: expected b.txt,wrap, got %v", selected)
+	} else if view.GetItems()[0].IsChecked() {
+		t.Errorf("failed to toggle checked item: expected unchecked")
+	}
+
+	key(tcell.KeyRune, 'v', tcell.ModAlt)
+	key(tcell.KeyEscape, 0, tcell.ModNone)
+	if m.IsOpen() {
+		t.Errorf("failed to close menu: expected Escape to close the menu")
+	}
+}
+
+func TestMenuBarDraw(t *testing.T) {
+	t.Parallel()
+
+	var selected []string
+	m := newTestMenuBar(&selected)
+
+	wm := NewWindowManager()
+	wm.SetMenuBar(m)
+	w := NewWindow(NewBox())
+	w.SetPosition(2, 1)
+	w.SetSize(20, 8)
+	wm.Add(w)
+
+	app, err := newTestApp(wm)
+	if err != nil {
+		t.Fatalf("failed to initialize Application: %s", err)
+	}
+	wm.SetRect(0, 0, 40, 12)
+
+	m.Open(0)
+	m.HandleKey(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone))
+	m.HandleKey(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone))
+	wm.Draw(app.screen)
+
+	expected := []string{
+		" File  View",
+		"┌────────────────┐",
+		"│ Open    Ctrl+O │┌───────┐",
+		"│ Recent       ► ││ a.txt │",
+		"│────────────────││ b.txt │",
+		"│ Print          │└───────┘",
+		"│ Quit           │",
+		"└────────────────┘",
+	}
+	for y, line := range expected {
+		if got := screenLine(app.screen, y); !strings.HasPrefix(got, line) {
+			t.Errorf("failed to draw menu line %d: expected %q, got %q", y, line, got)
+		}
+	}
+
+	// Clicks outside of the menus close them.
+	consumed, _ := wm.MouseHandler()(MouseLeftDown, tcell.NewEventMouse(30, 10, tcell.Button1, 0), func(Primitive) {})
+	if !consumed || m.IsOpen() {
+		t.Errorf("failed to close menus on click: expected the click to be consumed")
+	}
+
+	// Centered windows are not moved by the menu bar.
+	w.SetPositionCenter()
+	wm.Draw(app.screen)
+	_, centeredY, _, _ := w.GetRect()
+	wm.SetMenuBar(nil)
+	wm.Draw(app.screen)
+	if _, y, _, _ := w.GetRect(); y != centeredY {
+		t.Errorf("failed to keep centered window in place: expected row %d, got %d", centeredY, y)
+	}
+}
+
+func TestMenuAccelerator(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range []struct {
+		label, expected string
+		accelerator     rune
+	}{
+		{"Save &As", "Save [::u]A[::-]s", 'a'},
+		{"&Open", "[::u]O[::-]pen", 'o'},
+		{"Fish && Chips", "Fish & Chips", 0},
+		{"Ex&it [now]", "Ex[::u]i[::-]t [now[]", 'i'},
+	} {
+		label, accelerator, pos := parseAccelerator(c.label)
+		if formatted := formatAccelerator(label, pos); formatted != c.expected || accelerator != c.accelerator {
+			t.Errorf("failed to underline accelerator of %q: expected %q (%c), got %q (%c)", c.label, c.expected, c.accelerator, formatted, accelerator)
+		}
+	}
+}
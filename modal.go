@@ -242,3 +242,29 @@ func (m *Modal) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 		return
 	})
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme. Colors which were set explicitly are kept.
+func (m *Modal) ApplyTheme(previous, theme *Theme) {
+	m.Box.ApplyTheme(previous, theme)
+
+	m.Lock()
+	defer m.Unlock()
+
+	themeColor(&m.textColor, previous.PrimaryTextColor, theme.PrimaryTextColor)
+
+	f := m.form
+	f.Box.applyTheme(previous.ContrastBackgroundColor, theme.ContrastBackgroundColor, previous, theme)
+	f.Lock()
+	themeColor(&f.buttonBackgroundColor, previous.PrimitiveBackgroundColor, theme.PrimitiveBackgroundColor)
+	themeColor(&f.buttonTextColor, previous.PrimaryTextColor, theme.PrimaryTextColor)
+	themeColor(&f.buttonBackgroundColorFocused, previous.ButtonFocusedBackgroundColor, theme.ButtonFocusedBackgroundColor)
+	themeColor(&f.buttonTextColorFocused, previous.ButtonTextFocusedColor, theme.ButtonTextFocusedColor)
+	buttons := append([]*Button(nil), f.buttons...)
+	f.Unlock()
+	for _, button := range buttons {
+		button.ApplyTheme(previous, theme)
+	}
+
+	m.frame.Box.applyTheme(previous.ContrastBackgroundColor, theme.ContrastBackgroundColor, previous, theme)
+}
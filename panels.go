@@ -437,3 +437,18 @@ func (p *Pages) SwitchToPage(name string) {
 func (p *Pages) GetFrontPage() (name string, item Primitive) {
 	return p.GetFrontPanel()
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme, for the panels and all of their primitives. Colors which were set
+// explicitly are kept.
+func (p *Panels) ApplyTheme(previous, theme *Theme) {
+	p.Box.ApplyTheme(previous, theme)
+
+	p.RLock()
+	panels := append([]*panel(nil), p.panels...)
+	p.RUnlock()
+
+	for _, panel := range panels {
+		ApplyTheme(panel.Item, previous, theme)
+	}
+}
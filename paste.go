@@ -0,0 +1,30 @@
+package crtview
+
+import (
+	"bytes"
+)
+
+// Pastable is implemented by primitives which receive text pasted into the
+// terminal at once instead of key by key. Pasted text is delivered to the
+// primitive which has focus if bracketed paste mode is enabled (see
+// Application.EnableBracketedPaste). Primitives which do not implement this
+// interface receive the pasted keys one by one.
+type Pastable interface {
+	// PasteHandler returns a handler which receives pasted text when the
+	// primitive has focus. Line breaks are "\n". If it returns nil, the pasted
+	// keys are passed to the primitive one by one.
+	PasteHandler() func(text string, setFocus func(p Primitive))
+}
+
+// pasteLine returns pasted text as a single line. Line breaks at the end are
+// removed, other line breaks are replaced with spaces and tabs with TabSize
+// spaces.
+func pasteLine(text string) []byte {
+	line := bytes.TrimRight([]byte(text), "\n")
+	return bytes.Replace(pasteLines(string(line)), []byte{'\n'}, []byte{' '}, -1)
+}
+
+// pasteLines returns pasted text with tabs replaced with TabSize spaces.
+func pasteLines(text string) []byte {
+	return bytes.Replace([]byte(text), []byte{'\t'}, bytes.Repeat([]byte{' '}, TabSize), -1)
+}
@@ -0,0 +1,99 @@
+package crtview
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// pasteKeys returns the keys received for pasted text.
+func pasteKeys(text string) []*tcell.EventKey {
+	var keys []*tcell.EventKey
+	for _, r := range text {
+		switch r {
+		case '\n':
+			keys = append(keys, tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+		case '\t':
+			keys = append(keys, tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone))
+		default:
+			keys = append(keys, tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+		}
+	}
+	return keys
+}
+
+func TestPaste(t *testing.T) {
+	t.Parallel()
+
+	// Paste into an input field.
+	i := NewInputField()
+	i.SetText("ab")
+	i.SetCursorPosition(1)
+	var accepted, changed int
+	i.SetAcceptanceFunc(func(text string, lastChar rune) bool {
+		accepted++
+		return lastChar == 'y'
+	})
+	i.SetChangedFunc(func(text string) {
+		changed++
+	})
+	app, err := newTestApp(i)
+	if err != nil {
+		t.Fatalf("failed to initialize Application: %s", err)
+	} else if err = app.screen.Init(); err != nil {
+		t.Fatalf("failed to initialize screen: %s", err)
+	}
+	app.firePaste(pasteKeys("x\ny\n"))
+	if text := i.GetText(); text != "ax yb" || accepted != 1 || changed != 1 {
+		t.Errorf("failed to paste into input field: expected ax yb, accepted and changed once, got %q, %d, %d", text, accepted, changed)
+	} else if pos := i.GetCursorPosition(); pos != 4 {
+		t.Errorf("failed to move cursor after pasted text: expected 4, got %d", pos)
+	}
+	app.firePaste(pasteKeys("z"))
+	if text := i.GetText(); text != "ax yb" {
+		t.Errorf("failed to reject pasted text: expected ax yb, got %q", text)
+	}
+
+	// Paste into a text area, replacing the selection.
+	a := NewTextArea()
+	a.SetText("one two")
+	a.Select(4, 7)
+	app.SetRoot(a, true)
+	app.firePaste(pasteKeys("2\n\t3"))
+	if text := a.GetText(); text != "one 2\n    3" {
+		t.Errorf("failed to paste into text area: expected \"one 2\\n    3\", got %q", text)
+	}
+	a.InputHandler()(tcell.NewEventKey(tcell.KeyCtrlZ, 0, tcell.ModCtrl), nil)
+	if text := a.GetText(); text != "one two" {
+		t.Errorf("failed to undo paste: expected one two, got %q", text)
+	}
+
+	// Paste into the editor of a table cell.
+	table := NewTable()
+	table.SetSelectable(true, true)
+	table.SetCellsEditable(true)
+	table.SetCellSimple(0, 0, "cell")
+	app.SetRoot(table, true)
+	table.InputHandler()(tcell.NewEventKey(tcell.KeyF2, 0, tcell.ModNone), app.SetFocus)
+	if !table.IsEditing() {
+		t.Fatalf("failed to start editing cell")
+	}
+	app.firePaste(pasteKeys(" 1"))
+	table.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), app.SetFocus)
+	if text := table.GetCell(0, 0).GetText(); text != "cell 1" {
+		t.Errorf("failed to paste into table cell: expected cell 1, got %s", text)
+	}
+
+	// Pass pasted keys one by one to other primitives.
+	b := NewBox()
+	var keys string
+	b.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		keys += string(event.Rune())
+		return nil
+	})
+	app.SetRoot(b, true)
+	app.firePaste(pasteKeys("abc"))
+	if keys != "abc" {
+		t.Errorf("failed to pass pasted keys: expected abc, got %s", keys)
+	}
+}
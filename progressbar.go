@@ -190,3 +190,15 @@ func (p *ProgressBar) Draw(screen tcell.Screen) {
 		}
 	}
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme. Colors which were set explicitly are kept.
+func (p *ProgressBar) ApplyTheme(previous, theme *Theme) {
+	p.Box.ApplyTheme(previous, theme)
+
+	p.Lock()
+	defer p.Unlock()
+
+	themeColor(&p.emptyColor, previous.PrimitiveBackgroundColor, theme.PrimitiveBackgroundColor)
+	themeColor(&p.filledColor, previous.PrimaryTextColor, theme.PrimaryTextColor)
+}
@@ -267,7 +267,9 @@ func (s *Slider) Draw(screen tcell.Screen) {
 // InputHandler returns the handler for this primitive.
 func (s *Slider) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return s.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
-		if HitShortcut(event, Keys.Cancel, Keys.MovePreviousField, Keys.MoveNextField) {
+		keys := s.GetKeys()
+
+		if HitShortcut(event, keys.Cancel, keys.MovePreviousField, keys.MoveNextField) {
 			if s.done != nil {
 				s.done(event.Key())
 			}
@@ -279,13 +281,13 @@ func (s *Slider) InputHandler() func(event *tcell.EventKey, setFocus func(p Prim
 
 		previous := s.progress
 
-		if HitShortcut(event, Keys.MoveFirst, Keys.MoveFirst2) {
+		if HitShortcut(event, keys.MoveFirst, keys.MoveFirst2) {
 			s.SetProgress(0)
-		} else if HitShortcut(event, Keys.MoveLast, Keys.MoveLast2) {
+		} else if HitShortcut(event, keys.MoveLast, keys.MoveLast2) {
 			s.SetProgress(s.max)
-		} else if HitShortcut(event, Keys.MoveUp, Keys.MoveUp2, Keys.MoveRight, Keys.MoveRight2, Keys.MovePreviousField) {
+		} else if HitShortcut(event, keys.MoveUp, keys.MoveUp2, keys.MoveRight, keys.MoveRight2, keys.MovePreviousField) {
 			s.AddProgress(s.increment)
-		} else if HitShortcut(event, Keys.MoveDown, Keys.MoveDown2, Keys.MoveLeft, Keys.MoveLeft2, Keys.MoveNextField) {
+		} else if HitShortcut(event, keys.MoveDown, keys.MoveDown2, keys.MoveLeft, keys.MoveLeft2, keys.MoveNextField) {
 			s.AddProgress(s.increment * -1)
 		}
 
@@ -362,3 +364,16 @@ func (s *Slider) MouseHandler() func(action MouseAction, event *tcell.EventMouse
 		return
 	})
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme. Colors which were set explicitly are kept.
+func (s *Slider) ApplyTheme(previous, theme *Theme) {
+	s.ProgressBar.ApplyTheme(previous, theme)
+
+	s.Lock()
+	defer s.Unlock()
+
+	themeColor(&s.labelColor, previous.SecondaryTextColor, theme.SecondaryTextColor)
+	themeColor(&s.fieldBackgroundColor, previous.ContrastBackgroundColor, theme.ContrastBackgroundColor)
+	themeColor(&s.fieldTextColor, previous.PrimaryTextColor, theme.PrimaryTextColor)
+}
@@ -61,6 +61,10 @@ type Theme struct {
 	AlertDialogBackgroundColor   tcell.Color
 	AlertDialogTextColor         tcell.Color
 	AlertDialogBorderColor       tcell.Color
+
+	// Border runes. These are not used directly by primitives, assign them to
+	// Borders to apply them.
+	Borders BorderSet
 }
 
 // Styles defines the appearance of an application. The default is for a black
@@ -118,4 +122,93 @@ var Styles = Theme{
 	AlertDialogBackgroundColor:   tcell.ColorRed.TrueColor(),
 	AlertDialogTextColor:         tcell.ColorWhite.TrueColor(),
 	AlertDialogBorderColor:       tcell.ColorLightYellow.TrueColor(),
+
+	Borders: Borders,
+}
+
+// DarkTheme is the default theme. It is a copy of the initial Styles.
+var DarkTheme = Styles
+
+// LightTheme is a theme with dark text on a light background.
+var LightTheme = newLightTheme()
+
+// HighContrastTheme is a theme with white text on a black background, using
+// yellow for emphasis and navy for input fields.
+var HighContrastTheme = newHighContrastTheme()
+
+func newLightTheme() Theme {
+	t := DarkTheme
+
+	t.TitleColor = tcell.ColorNavy.TrueColor()
+	t.BorderColor = tcell.ColorGray.TrueColor()
+	t.GraphicsColor = tcell.ColorGray.TrueColor()
+
+	t.PrimaryTextColor = tcell.ColorBlack.TrueColor()
+	t.SecondaryTextColor = tcell.ColorNavy.TrueColor()
+	t.TertiaryTextColor = tcell.ColorGreen.TrueColor()
+	t.InverseTextColor = tcell.ColorWhite.TrueColor()
+	t.ContrastPrimaryTextColor = tcell.ColorBlack.TrueColor()
+	t.ContrastSecondaryTextColor = tcell.ColorDarkCyan.TrueColor()
+
+	t.PrimitiveBackgroundColor = tcell.ColorWhite.TrueColor()
+	t.ContrastBackgroundColor = tcell.ColorLightGray.TrueColor()
+	t.MoreContrastBackgroundColor = tcell.ColorTeal.TrueColor()
+
+	t.ButtonBackgroundColor = tcell.ColorNavy.TrueColor()
+	t.ButtonFocusedBackgroundColor = tcell.ColorBlue.TrueColor()
+	t.ButtonTextColor = tcell.ColorWhite.TrueColor()
+	t.ButtonTextFocusedColor = tcell.ColorWhite.TrueColor()
+
+	t.ShadowTextColor = tcell.NewRGBColor(0x99, 0x99, 0x99)
+	t.ShadowColor = tcell.ColorGray.TrueColor()
+
+	t.ScrollBarColor = tcell.ColorGray.TrueColor()
+
+	return t
+}
+
+func newHighContrastTheme() Theme {
+	t := DarkTheme
+
+	black, white, yellow, navy := tcell.ColorBlack.TrueColor(), tcell.ColorWhite.TrueColor(), tcell.ColorYellow.TrueColor(), tcell.ColorNavy.TrueColor()
+
+	t.TitleColor = yellow
+	t.BorderColor = white
+	t.GraphicsColor = white
+
+	t.PrimaryTextColor = white
+	t.SecondaryTextColor = yellow
+	t.TertiaryTextColor = white
+	t.InverseTextColor = black
+	t.ContrastPrimaryTextColor = yellow
+	t.ContrastSecondaryTextColor = yellow
+
+	t.PrimitiveBackgroundColor = black
+	t.ContrastBackgroundColor = navy
+	t.MoreContrastBackgroundColor = yellow
+
+	t.ButtonBackgroundColor = white
+	t.ButtonFocusedBackgroundColor = yellow
+	t.ButtonTextColor = black
+	t.ButtonTextFocusedColor = black
+
+	t.ShadowTextColor = white
+	t.ShadowColor = black
+
+	t.ScrollBarColor = white
+
+	t.InfoDialogBackgroundColor = black
+	t.InfoDialogTextColor = white
+	t.InfoDialogBorderColor = white
+	t.AltInfoDialogBackgroundColor = black
+	t.AltInfoDialogTextColor = white
+	t.AltInfoDialogBorderColor = yellow
+	t.WarningDialogBackgroundColor = black
+	t.WarningDialogTextColor = yellow
+	t.WarningDialogBorderColor = yellow
+	t.AlertDialogBackgroundColor = black
+	t.AlertDialogTextColor = white
+	t.AlertDialogBorderColor = yellow
+
+	return t
 }
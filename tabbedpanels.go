@@ -368,3 +368,18 @@ func (t *TabbedPanels) MouseHandler() func(action MouseAction, event *tcell.Even
 		return t.Flex.MouseHandler()(action, event, setFocus)
 	})
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme, for the tabs and all panels. Colors which were set explicitly are
+// kept.
+func (t *TabbedPanels) ApplyTheme(previous, theme *Theme) {
+	t.Flex.ApplyTheme(previous, theme)
+
+	t.Lock()
+	defer t.Unlock()
+
+	themeColor(&t.tabTextColor, previous.PrimaryTextColor, theme.PrimaryTextColor)
+	themeColor(&t.tabTextColorFocused, previous.InverseTextColor, theme.InverseTextColor)
+	themeColor(&t.tabBackgroundColorFocused, previous.PrimaryTextColor, theme.PrimaryTextColor)
+	t.updateTabLabels()
+}
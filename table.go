@@ -1,12 +1,12 @@
 package crtview
 
 import (
-	"bytes"
 	"sort"
 	"sync"
 
 	"github.com/gdamore/tcell/v2"
 	colorful "github.com/lucasb-eyer/go-colorful"
+	"github.com/mattn/go-runewidth"
 )
 
 // TableCell represents one cell inside a Table. You can instantiate this type
@@ -237,6 +237,12 @@ func (c *TableCell) GetLastPosition() (x, y, width int) {
 // Columns will use as much horizontal space as they need. You can constrain
 // their size with the MaxWidth parameter of the TableCell type.
 //
+// # Content
+//
+// By default, all cells are kept in memory. Large data sets may instead be
+// provided by a TableContent set with SetContent(), which is only asked for the
+// cells that are drawn.
+//
 // # Fixed Columns
 //
 // You can define fixed rows and rolumns via SetFixed(). They will always stay
@@ -251,6 +257,21 @@ func (c *TableCell) GetLastPosition() (x, y, width int) {
 // set, individual cells can be selected. The "selected" handler set via
 // SetSelectedFunc() is invoked when the user presses Enter on a selection.
 //
+// SetMultiSelect() lets the user select more than one row, which are returned
+// by GetSelectedRows():
+//
+//   - Space, Ctrl-click: Toggle the selection of a row.
+//   - Shift and the navigation keys, Shift-click: Select a range of rows.
+//   - Ctrl-A: Select all rows.
+//
+// # Editing
+//
+// SetCellsEditable() lets the user edit cells in place by pressing Enter or F2
+// or by double-clicking a cell. Cells are edited in an InputField, or in a
+// DropDown or CheckBox set with SetColumnEditor(). New texts may be rejected
+// by the handler set with SetCellValidateFunc(), changes are reported to the
+// handler set with SetCellEditedFunc().
+//
 // # Navigation
 //
 // If the table extends beyond the available space, it can be navigated with
@@ -269,6 +290,38 @@ func (c *TableCell) GetLastPosition() (x, y, width int) {
 // rows and columns). When there is a selection, the user moves the selection.
 // The class will attempt to keep the selection from moving out of the screen.
 //
+// # Sorting
+//
+// Sort() and SortBy() sort the rows below the fixed rows by one or more
+// columns. Clicking a fixed row sorts by the clicked column, Shift-clicking
+// adds the column as another sort key. The columns are compared by their text
+// unless a comparator is set with SetColumnComparator(), such as
+// CompareNumeric, CompareHumanSize, CompareDate or CompareNatural. The sort
+// keys are shown in the last fixed row.
+//
+// # Column Layout
+//
+// The user may resize a column by dragging the separator right of it, and
+// move a column by dragging it by a fixed row. Right-clicking a fixed row, or
+// pressing Alt+Enter, opens a menu in which columns are hidden and shown. The
+// keys > and < widen and narrow the selected column, Alt+Left and Alt+Right
+// move it. GetColumnLayout() and SetColumnLayout() save and restore the
+// resulting layout.
+//
+// # Filtering and Searching
+//
+// Rows may be filtered by column with SetColumnFilter() and
+// SetColumnFilterText(). Fixed rows are never filtered. SetFilterRow() shows a
+// row below the fixed rows in which the user edits the filter texts after
+// pressing f. Filters are applied again after sorting. Row indices passed to
+// and returned by the methods of Table always refer to the rows of the
+// content, whether or not rows are filtered out.
+//
+//   - /: Type a search query. Matching cells are highlighted and the first
+//     match is selected as you type. Enter keeps the query, Escape cancels it.
+//   - n: Move to the next match.
+//   - N: Move to the previous match.
+//
 // Use SetInputCapture() to override or modify keyboard input.
 type Table struct {
 	*Box
@@ -282,11 +335,8 @@ type Table struct {
 	// If there are no borders, the column separator.
 	separator rune
 
-	// The cells of the table. Rows first, then columns.
-	cells [][]*TableCell
-
-	// The rightmost column in the data set.
-	lastColumn int
+	// The cells of the table.
+	content TableContent
 
 	// If true, when calculating the widths of the columns, all rows are evaluated
 	// instead of only the visible ones.
@@ -299,7 +349,7 @@ type Table struct {
 	// cells can be selected.
 	rowsSelectable, columnsSelectable bool
 
-	// The currently selected row and column.
+	// The currently selected row and column, as drawn.
 	selectedRow, selectedColumn int
 
 	// The number of rows/columns by which the table is scrolled down/to the
@@ -312,14 +362,109 @@ type Table struct {
 	// The sort function of the table. Defaults to a case-sensitive comparison.
 	sortFunc func(column, i, j int) bool
 
+	// The comparators of the columns, which take precedence over sortFunc.
+	comparators map[int]func(a, b string) int
+
 	// Whether or not the table should be sorted when a fixed row is clicked.
 	sortClicked bool
 
-	// The last direction the table was sorted by when clicked.
-	sortClickedDescending bool
+	// The columns the table was last sorted by.
+	sortKeys []TableSortKey
+
+	// Whether or not the sort keys are shown in the last fixed row.
+	sortIndicators bool
+
+	// The filter functions and filter texts of the columns.
+	filters     map[int]func(cell *TableCell) bool
+	filterTexts map[int]string
+
+	// Whether or not the filter row is shown below the fixed rows.
+	filterRow bool
+
+	// Whether or not the filter row is being edited, and the column edited (as
+	// drawn).
+	filterEditing bool
+	filterColumn  int
+
+	// The color of the filter row and of the search query.
+	filterColor tcell.Color
+
+	// The rows of the content as drawn, -1 being the filter row. The selection
+	// and the row offset refer to these rows. If nil, all rows are drawn.
+	rowIndex []int
+
+	// The positions in rowIndex of the rows of the content which are drawn.
+	rowPositions map[int]int
+
+	// Whether the content changed since the rows were filtered, and the number
+	// of rows of the content when they were filtered.
+	filterDirty  bool
+	filteredRows int
+
+	// The search query, and whether or not it is being typed.
+	searchQuery string
+	searching   bool
 
-	// The last column the table was sorted by when clicked.
-	sortClickedColumn int
+	// The selection and row offset when the search query was started.
+	searchRow, searchColumn, searchOffset int
+
+	// The background color of cells which match the search query.
+	searchColor tcell.Color
+
+	// The order in which the columns of the content are drawn, the hidden
+	// columns and the widths set by the user.
+	columnOrder   []int
+	hiddenColumns map[int]bool
+	columnWidths  map[int]int
+
+	// The columns of the content as drawn. The selection and the column offset
+	// refer to these columns. If nil, all columns are drawn in order.
+	columnIndex []int
+
+	// Whether or not the user may resize and reorder columns.
+	columnsResizable, columnsReorderable bool
+
+	// The column being resized or moved with the mouse, and the screen column
+	// at which the text of a resized column starts.
+	dragMode, dragColumn, dragX int
+
+	// The menu in which the user hides and shows columns.
+	columnMenu *ContextMenu
+
+	// Whether or not more than one row may be selected.
+	multiSelect bool
+
+	// The selected rows of the content when multi-selection is enabled, and
+	// the row ranges are selected from.
+	selectedRows    map[int]bool
+	selectionAnchor int
+
+	// The text color of the selected rows.
+	selectedRowsColor tcell.Color
+
+	// An optional function which gets called when the user changes the
+	// selected rows.
+	selectedRowsChanged func(rows []int)
+
+	// Whether or not the user may edit cells.
+	cellsEditable bool
+
+	// The form items the cells of columns are edited in, and the input field
+	// the other columns are edited in.
+	columnEditors map[int]FormItem
+	inputEditor   *InputField
+
+	// The editor of the cell being edited (nil if none), its row and column
+	// of the content and its text before the edit.
+	editor                  FormItem
+	editRow, editColumn     int
+	editText                string
+	editX, editY, editWidth int
+
+	// Optional functions which get called when the user accepts the new text
+	// of a cell, and after the text was changed.
+	cellValidate func(row, column int, text string) bool
+	cellEdited   func(row, column int, oldText, newText string)
 
 	// The number of visible rows the last time the table was drawn.
 	visibleRows int
@@ -360,15 +505,55 @@ type Table struct {
 
 // NewTable returns a new table.
 func NewTable() *Table {
-	return &Table{
+	t := &Table{
 		Box:                 NewBox(),
 		scrollBarVisibility: ScrollBarAuto,
 		scrollBarColor:      Styles.ScrollBarColor,
 		bordersColor:        Styles.GraphicsColor,
 		separator:           ' ',
 		sortClicked:         true,
-		lastColumn:          -1,
+		sortIndicators:      true,
+		content:             NewTableContent(),
+		filterColor:         Styles.SecondaryTextColor,
+		searchColor:         Styles.MoreContrastBackgroundColor,
+		columnsResizable:    true,
+		columnsReorderable:  true,
+		selectedRowsColor:   Styles.SecondaryTextColor,
 	}
+	t.columnMenu = NewContextMenu(t)
+	return t
+}
+
+// SetContent sets the content the cells of the table are read from. By
+// default, tables keep their cells in memory. The methods of Table which
+// modify cells, such as SetCell, have no effect unless the content implements
+// EditableTableContent. Provide nil to return to an empty in-memory content.
+func (t *Table) SetContent(content TableContent) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	if content == nil {
+		content = NewTableContent()
+	}
+	t.content = content
+	t.selectedRows = nil
+	t.filterRows()
+	t.layoutColumns()
+	return t
+}
+
+// GetContent returns the content of the table.
+func (t *Table) GetContent() TableContent {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.content
+}
+
+// editable returns the content of the table if it may be modified, or nil.
+func (t *Table) editable() EditableTableContent {
+	content, _ := t.content.(EditableTableContent)
+	return content
 }
 
 // Clear removes all table data.
@@ -376,8 +561,11 @@ func (t *Table) Clear() {
 	t.Lock()
 	defer t.Unlock()
 
-	t.cells = nil
-	t.lastColumn = -1
+	if content := t.editable(); content != nil {
+		content.Clear()
+	}
+	t.selectedRows = nil
+	t.filterRows()
 }
 
 // SetInputCapture installs a function which captures key events before they are
@@ -480,6 +668,7 @@ func (t *Table) SetFixed(rows, columns int) *Table {
 	defer t.Unlock()
 
 	t.fixedRows, t.fixedColumns = rows, columns
+	t.filterRows()
 	return t
 }
 
@@ -509,24 +698,30 @@ func (t *Table) GetSelectable() (rows, columns bool) {
 
 // GetSelection returns the position of the current selection.
 // If entire rows are selected, the column index is undefined.
-// Likewise for entire columns.
+// Likewise for entire columns. Rows are rows of the content, regardless of
+// filters.
 func (t *Table) GetSelection() (row, column int) {
 	t.RLock()
 	defer t.RUnlock()
 
-	return t.selectedRow, t.selectedColumn
+	return t.contentRow(t.selectedRow), t.contentColumn(t.selectedColumn)
 }
 
 // Select sets the selected cell. Depending on the selection settings
 // specified via SetSelectable(), this may be an entire row or column, or even
 // ignored completely. The "selection changed" event is fired if such a callback
 // is available (even if the selection ends up being the same as before and even
-// if cells are not selectable).
+// if cells are not selectable). Rows which are filtered out are not selected.
 func (t *Table) Select(row, column int) *Table {
 	t.Lock()
 	defer t.Unlock()
 
-	t.selectedRow, t.selectedColumn = row, column
+	if displayRow := t.displayRow(row); displayRow >= 0 || t.rowIndex == nil {
+		t.selectedRow = displayRow
+	}
+	if displayColumn := t.displayColumn(column); displayColumn >= 0 || t.columnIndex == nil {
+		t.selectedColumn = displayColumn
+	}
 	if t.selectionChanged != nil {
 		t.Unlock()
 		t.selectionChanged(row, column)
@@ -622,19 +817,9 @@ func (t *Table) SetCell(row, column int, cell *TableCell) *Table {
 	t.Lock()
 	defer t.Unlock()
 
-	if row >= len(t.cells) {
-		t.cells = append(t.cells, make([][]*TableCell, row-len(t.cells)+1)...)
-	}
-	rowLen := len(t.cells[row])
-	if column >= rowLen {
-		t.cells[row] = append(t.cells[row], make([]*TableCell, column-rowLen+1)...)
-		for c := rowLen; c < column; c++ {
-			t.cells[row][c] = &TableCell{}
-		}
-	}
-	t.cells[row][column] = cell
-	if column > t.lastColumn {
-		t.lastColumn = column
+	if content := t.editable(); content != nil {
+		content.SetCell(row, column, cell)
+		t.filterDirty = true
 	}
 	return t
 }
@@ -653,10 +838,11 @@ func (t *Table) GetCell(row, column int) *TableCell {
 	t.RLock()
 	defer t.RUnlock()
 
-	if row >= len(t.cells) || column >= len(t.cells[row]) {
+	cell := t.content.GetCell(row, column)
+	if cell == nil {
 		return &TableCell{}
 	}
-	return t.cells[row][column]
+	return cell
 }
 
 // RemoveRow removes the row at the given position from the table. If there is
@@ -665,11 +851,11 @@ func (t *Table) RemoveRow(row int) *Table {
 	t.Lock()
 	defer t.Unlock()
 
-	if row < 0 || row >= len(t.cells) {
-		return t
+	if content := t.editable(); content != nil {
+		content.RemoveRow(row)
+		t.shiftSelectedRows(row, -1)
+		t.filterDirty = true
 	}
-
-	t.cells = append(t.cells[:row], t.cells[row+1:]...)
 	return t
 }
 
@@ -679,11 +865,9 @@ func (t *Table) RemoveColumn(column int) *Table {
 	t.Lock()
 	defer t.Unlock()
 
-	for row := range t.cells {
-		if column < 0 || column >= len(t.cells[row]) {
-			continue
-		}
-		t.cells[row] = append(t.cells[row][:column], t.cells[row][column+1:]...)
+	if content := t.editable(); content != nil {
+		content.RemoveColumn(column)
+		t.filterDirty = true
 	}
 	return t
 }
@@ -695,12 +879,11 @@ func (t *Table) InsertRow(row int) *Table {
 	t.Lock()
 	defer t.Unlock()
 
-	if row >= len(t.cells) {
-		return t
+	if content := t.editable(); content != nil {
+		content.InsertRow(row)
+		t.shiftSelectedRows(row, 1)
+		t.filterDirty = true
 	}
-	t.cells = append(t.cells, nil)       // Extend by one.
-	copy(t.cells[row+1:], t.cells[row:]) // Shift down.
-	t.cells[row] = nil                   // New row is uninitialized.
 	return t
 }
 
@@ -712,13 +895,9 @@ func (t *Table) InsertColumn(column int) *Table {
 	t.Lock()
 	defer t.Unlock()
 
-	for row := range t.cells {
-		if column >= len(t.cells[row]) {
-			continue
-		}
-		t.cells[row] = append(t.cells[row], nil)             // Extend by one.
-		copy(t.cells[row][column+1:], t.cells[row][column:]) // Shift to the right.
-		t.cells[row][column] = &TableCell{}                  // New element is an uninitialized table cell.
+	if content := t.editable(); content != nil {
+		content.InsertColumn(column)
+		t.filterDirty = true
 	}
 	return t
 }
@@ -728,7 +907,7 @@ func (t *Table) GetRowCount() int {
 	t.RLock()
 	defer t.RUnlock()
 
-	return len(t.cells)
+	return t.content.GetRowCount()
 }
 
 // GetColumnCount returns the (maximum) number of columns in the table.
@@ -736,16 +915,14 @@ func (t *Table) GetColumnCount() int {
 	t.RLock()
 	defer t.RUnlock()
 
-	if len(t.cells) == 0 {
-		return 0
-	}
-	return t.lastColumn + 1
+	return t.content.GetColumnCount()
 }
 
 // cellAt returns the row and column located at the given screen coordinates.
 // Each returned value may be negative if there is no row and/or cell. This
 // function will also process coordinates outside the table's inner rectangle so
-// callers will need to check for bounds themselves.
+// callers will need to check for bounds themselves. The row is a row as drawn,
+// see contentRow.
 func (t *Table) cellAt(x, y int) (row, column int) {
 	rectX, rectY, _, _ := t.GetInnerRect()
 
@@ -758,10 +935,10 @@ func (t *Table) cellAt(x, y int) (row, column int) {
 
 	// Respect fixed rows and row offset.
 	if row >= 0 {
-		if row >= t.fixedRows {
+		if row >= t.displayFixedRows() {
 			row += t.rowOffset
 		}
-		if row >= len(t.cells) {
+		if row >= t.rowCount() {
 			row = -1
 		}
 	}
@@ -808,12 +985,14 @@ func (t *Table) ScrollToEnd() *Table {
 
 	t.trackEnd = true
 	t.columnOffset = 0
-	t.rowOffset = len(t.cells)
+	t.rowOffset = t.content.GetRowCount()
 	return t
 }
 
 // SetSortClicked sets a flag which determines whether the table is sorted when
-// a fixed row is clicked. This flag is enabled by default.
+// a fixed row is clicked. Clicking a column again reverses the order. Clicking
+// a column while holding Shift sorts by that column in addition to the
+// previous ones. This flag is enabled by default.
 func (t *Table) SetSortClicked(sortClicked bool) *Table {
 	t.Lock()
 	defer t.Unlock()
@@ -833,34 +1012,45 @@ func (t *Table) SetSortFunc(sortFunc func(column, i, j int) bool) *Table {
 }
 
 // Sort sorts the table by the column at the given index. You may set a custom
-// sorting function with SetSortFunc.
+// sorting function with SetSortFunc or a comparator with SetColumnComparator.
+// Fixed rows are not sorted. Tables whose content is not an
+// EditableTableContent are not sorted. See SortBy for sorting by more than one
+// column.
 func (t *Table) Sort(column int, descending bool) *Table {
 	t.Lock()
 	defer t.Unlock()
 
-	if len(t.cells) == 0 || column < 0 || column >= len(t.cells[0]) {
-		return t
-	}
-
-	if t.sortFunc == nil {
-		t.sortFunc = func(column, i, j int) bool {
-			return bytes.Compare(t.cells[i][column].Text, t.cells[j][column].Text) == -1
-		}
-	}
+	t.sortBy([]TableSortKey{{Column: column, Descending: descending}})
+	return t
+}
 
-	sort.SliceStable(t.cells, func(i, j int) bool {
-		if i < t.fixedRows {
-			return i < j
-		} else if j < t.fixedRows {
-			return j > i
+// reorderRows reorders the rows of the content so that row order[i] becomes
+// row i.
+func (t *Table) reorderRows(order []int) {
+	switch content := t.content.(type) {
+	case *tableContentData:
+		content.reorderRows(order)
+	case EditableTableContent:
+		columns := content.GetColumnCount()
+		rows := make([][]*TableCell, len(order))
+		for i, row := range order {
+			if row == i {
+				continue
+			}
+			rows[i] = make([]*TableCell, columns)
+			for column := range rows[i] {
+				rows[i][column] = content.GetCell(row, column)
+			}
 		}
-
-		if !descending {
-			return t.sortFunc(column, i, j)
+		for row, cells := range rows {
+			for column, cell := range cells {
+				if cell == nil {
+					cell = &TableCell{}
+				}
+				content.SetCell(row, column, cell)
+			}
 		}
-		return t.sortFunc(column, j, i)
-	})
-	return t
+	}
 }
 
 // Draw draws this primitive onto the screen.
@@ -873,6 +1063,16 @@ func (t *Table) Draw(screen tcell.Screen) {
 
 	t.Lock()
 	defer t.Unlock()
+	defer t.drawColumnMenu(screen)
+
+	if t.rowIndex != nil && (t.filterDirty || t.filteredRows != t.content.GetRowCount()) {
+		t.filterRows() // The content has changed.
+	}
+	if t.columnIndex != nil {
+		t.layoutColumns()
+	}
+	rowCount, lastColumn := t.rowCount(), t.columnCount()-1
+	fixedRows := t.displayFixedRows()
 
 	// What's our available screen space?
 	x, y, width, height := t.GetInnerRect()
@@ -881,18 +1081,21 @@ func (t *Table) Draw(screen tcell.Screen) {
 	} else {
 		t.visibleRows = height
 	}
+	defer t.drawSearch(screen, x, y+height-1, width)
+	defer t.drawEditor(screen)
+	t.editWidth = 0
 
-	showVerticalScrollBar := t.scrollBarVisibility == ScrollBarAlways || (t.scrollBarVisibility == ScrollBarAuto && len(t.cells) > t.visibleRows-t.fixedRows)
+	showVerticalScrollBar := t.scrollBarVisibility == ScrollBarAlways || (t.scrollBarVisibility == ScrollBarAuto && rowCount > t.visibleRows-fixedRows)
 	if showVerticalScrollBar {
 		width-- // Subtract space for scroll bar.
 	}
 
 	// Return the cell at the specified position (nil if it doesn't exist).
 	getCell := func(row, column int) *TableCell {
-		if row < 0 || column < 0 || row >= len(t.cells) || column >= len(t.cells[row]) {
+		if row < 0 || column < 0 || row >= rowCount || column > lastColumn {
 			return nil
 		}
-		return t.cells[row][column]
+		return t.displayCell(row, column)
 	}
 
 	// If this cell is not selectable, find the next one.
@@ -903,13 +1106,13 @@ func (t *Table) Draw(screen tcell.Screen) {
 		if t.selectedRow < 0 {
 			t.selectedRow = 0
 		}
-		for t.selectedRow < len(t.cells) {
+		for t.selectedRow < rowCount {
 			cell := getCell(t.selectedRow, t.selectedColumn)
 			if cell == nil || !cell.NotSelectable {
 				break
 			}
 			t.selectedColumn++
-			if t.selectedColumn > t.lastColumn {
+			if t.selectedColumn > lastColumn {
 				t.selectedColumn = 0
 				t.selectedRow++
 			}
@@ -918,8 +1121,8 @@ func (t *Table) Draw(screen tcell.Screen) {
 
 	// Clamp row offsets.
 	if t.rowsSelectable {
-		if t.selectedRow >= t.fixedRows && t.selectedRow < t.fixedRows+t.rowOffset {
-			t.rowOffset = t.selectedRow - t.fixedRows
+		if t.selectedRow >= fixedRows && t.selectedRow < fixedRows+t.rowOffset {
+			t.rowOffset = t.selectedRow - fixedRows
 			t.trackEnd = false
 		}
 		if t.borders {
@@ -935,19 +1138,19 @@ func (t *Table) Draw(screen tcell.Screen) {
 		}
 	}
 	if t.borders {
-		if 2*(len(t.cells)-t.rowOffset) < height {
+		if 2*(rowCount-t.rowOffset) < height {
 			t.trackEnd = true
 		}
 	} else {
-		if len(t.cells)-t.rowOffset < height {
+		if rowCount-t.rowOffset < height {
 			t.trackEnd = true
 		}
 	}
 	if t.trackEnd {
 		if t.borders {
-			t.rowOffset = len(t.cells) - height/2
+			t.rowOffset = rowCount - height/2
 		} else {
-			t.rowOffset = len(t.cells) - height
+			t.rowOffset = rowCount - height
 		}
 	}
 	if t.rowOffset < 0 {
@@ -978,8 +1181,8 @@ func (t *Table) Draw(screen tcell.Screen) {
 		tableWidth = 1 // We start at the second character because of the left table border.
 	}
 	if t.evaluateAllRows {
-		allRows = make([]int, len(t.cells))
-		for row := range t.cells {
+		allRows = make([]int, rowCount)
+		for row := range allRows {
 			allRows[row] = row
 		}
 	}
@@ -991,12 +1194,12 @@ func (t *Table) Draw(screen tcell.Screen) {
 		tableHeight += rowStep
 		return true
 	}
-	for row := 0; row < t.fixedRows && row < len(t.cells); row++ { // Do the fixed rows first.
+	for row := 0; row < fixedRows && row < rowCount; row++ { // Do the fixed rows first.
 		if !indexRow(row) {
 			break
 		}
 	}
-	for row := t.fixedRows + t.rowOffset; row < len(t.cells); row++ { // Then the remaining rows.
+	for row := fixedRows + t.rowOffset; row < rowCount; row++ { // Then the remaining rows.
 		if !indexRow(row) {
 			break
 		}
@@ -1049,6 +1252,7 @@ ColumnLoop:
 				if cell.MaxWidth > 0 && cell.MaxWidth < cellWidth {
 					cellWidth = cell.MaxWidth
 				}
+				cellWidth += runewidth.StringWidth(t.sortIndicator(row, column))
 				if cellWidth > maxWidth {
 					maxWidth = cellWidth
 				}
@@ -1060,6 +1264,9 @@ ColumnLoop:
 		if maxWidth < 0 {
 			break // No more cells found in this column.
 		}
+		if columnWidth, ok := t.columnWidths[t.contentColumn(column)]; ok {
+			maxWidth = columnWidth // The width was set by the user.
+		}
 
 		// Store new column info at the end.
 		columns = append(columns, column)
@@ -1128,17 +1335,29 @@ ColumnLoop:
 			}
 
 			// Get the cell.
+			finalWidth := columnWidth
+			if columnX+1+columnWidth >= width {
+				finalWidth = width - columnX - 1
+			}
+			if t.editor != nil && t.contentRow(row) == t.editRow && t.contentColumn(column) == t.editColumn {
+				t.editX, t.editY, t.editWidth = x+columnX+1, y+rowY, finalWidth
+			}
 			cell := getCell(row, column)
 			if cell == nil {
 				continue
 			}
 
 			// Draw text.
-			finalWidth := columnWidth
-			if columnX+1+columnWidth >= width {
-				finalWidth = width - columnX - 1
-			}
 			cell.x, cell.y, cell.width = x+columnX+1, y+rowY, finalWidth
+			if indicator := t.sortIndicator(row, column); indicator != "" {
+				// Draw the sort indicator at the right end of the cell.
+				indicatorWidth := runewidth.StringWidth(indicator)
+				if indicatorWidth > finalWidth {
+					indicatorWidth = finalWidth
+				}
+				finalWidth -= indicatorWidth
+				PrintStyle(screen, []byte(indicator), x+columnX+1+finalWidth, y+rowY, indicatorWidth, AlignRight, SetAttributes(tcell.StyleDefault.Foreground(cell.Color), cell.Attributes))
+			}
 			_, printed := PrintStyle(screen, cell.Text, x+columnX+1, y+rowY, finalWidth, cell.Align, SetAttributes(tcell.StyleDefault.Foreground(cell.Color), cell.Attributes))
 			if TaggedTextWidth(cell.Text)-printed > 0 && printed > 0 {
 				_, _, style, _ := screen.GetContent(x+columnX+finalWidth, y+rowY)
@@ -1162,7 +1381,7 @@ ColumnLoop:
 	}
 
 	// Draw right border.
-	if t.borders && len(t.cells) > 0 && columnX < width {
+	if t.borders && rowCount > 0 && columnX < width {
 		for rowY := range rows {
 			rowY *= 2
 			if rowY+1 < height {
@@ -1181,13 +1400,13 @@ ColumnLoop:
 
 	if showVerticalScrollBar {
 		// Calculate scroll bar position and dimensions.
-		rows := len(t.cells)
+		rows := rowCount
 
-		scrollBarItems := rows - t.fixedRows
-		scrollBarHeight := t.visibleRows - t.fixedRows
+		scrollBarItems := rows - fixedRows
+		scrollBarHeight := t.visibleRows - fixedRows
 
 		scrollBarX := x + width
-		scrollBarY := y + t.fixedRows
+		scrollBarY := y + fixedRows
 		if scrollBarX > x+tableWidth {
 			scrollBarX = x + tableWidth
 		}
@@ -1199,11 +1418,11 @@ ColumnLoop:
 			scrollBarItems *= 2
 			scrollBarHeight = (scrollBarHeight * 2) - 1
 
-			scrollBarY += t.fixedRows + 1
+			scrollBarY += fixedRows + 1
 		}
 
 		// Draw scroll bar.
-		cursor := int(float64(scrollBarItems) * (float64(t.rowOffset) / float64(((rows-t.fixedRows)-t.visibleRows)+padTotalOffset)))
+		cursor := int(float64(scrollBarItems) * (float64(t.rowOffset) / float64(((rows-fixedRows)-t.visibleRows)+padTotalOffset)))
 		for printed := 0; printed < scrollBarHeight; printed++ {
 			RenderScrollBar(screen, t.scrollBarVisibility, scrollBarX, scrollBarY+printed, scrollBarHeight, scrollBarItems, cursor, printed, t.hasFocus, t.scrollBarColor)
 		}
@@ -1253,12 +1472,14 @@ ColumnLoop:
 		x, y, w, h int
 		color      tcell.Color
 		selected   bool
+		marked     bool
 	}
 	cellsByBackgroundColor := make(map[tcell.Color][]*cellInfo)
 	var backgroundColors []tcell.Color
 	for rowY, row := range rows {
 		columnX := 0
 		rowSelected := t.rowsSelectable && !t.columnsSelectable && row == t.selectedRow
+		rowMarked := t.multiSelect && t.selectedRows[t.contentRow(row)]
 		for columnIndex, column := range columns {
 			columnWidth := widths[columnIndex]
 			cell := getCell(row, column)
@@ -1273,17 +1494,22 @@ ColumnLoop:
 			}
 			columnSelected := t.columnsSelectable && !t.rowsSelectable && column == t.selectedColumn
 			cellSelected := !cell.NotSelectable && (columnSelected || rowSelected || t.rowsSelectable && t.columnsSelectable && column == t.selectedColumn && row == t.selectedRow)
-			entries, ok := cellsByBackgroundColor[cell.BackgroundColor]
-			cellsByBackgroundColor[cell.BackgroundColor] = append(entries, &cellInfo{
+			backgroundColor := cell.BackgroundColor
+			if t.searchMatch(cell) {
+				backgroundColor = t.searchColor
+			}
+			entries, ok := cellsByBackgroundColor[backgroundColor]
+			cellsByBackgroundColor[backgroundColor] = append(entries, &cellInfo{
 				x:        bx,
 				y:        by,
 				w:        bw,
 				h:        bh,
 				color:    cell.Color,
 				selected: cellSelected,
+				marked:   rowMarked && !cell.NotSelectable,
 			})
 			if !ok {
-				backgroundColors = append(backgroundColors, cell.BackgroundColor)
+				backgroundColors = append(backgroundColors, backgroundColor)
 			}
 			columnX += columnWidth + 1
 		}
@@ -1308,6 +1534,8 @@ ColumnLoop:
 				} else {
 					defer colorBackground(cell.x, cell.y, cell.w, cell.h, bgColor, cell.color, 0, true)
 				}
+			} else if cell.marked {
+				colorBackground(cell.x, cell.y, cell.w, cell.h, bgColor, t.selectedRowsColor, tcell.AttrBold, false)
 			} else {
 				colorBackground(cell.x, cell.y, cell.w, cell.h, bgColor, tcell.ColorDefault, 0, false)
 			}
@@ -1318,14 +1546,75 @@ ColumnLoop:
 	t.visibleColumnIndices, t.visibleColumnWidths = columns, widths
 }
 
+// Focus is called by the application when the primitive receives focus.
+func (t *Table) Focus(delegate func(p Primitive)) {
+	t.RLock()
+	editor := t.editor
+	t.RUnlock()
+
+	t.Box.Focus(delegate)
+	if editor != nil {
+		delegate(editor)
+	} else if t.columnMenu.ContextMenuVisible() {
+		t.columnMenu.l.RLock()
+		list := t.columnMenu.focusList()
+		t.columnMenu.l.RUnlock()
+		delegate(list)
+	}
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (t *Table) HasFocus() bool {
+	if t.columnMenu.ContextMenuVisible() {
+		t.columnMenu.l.RLock()
+		defer t.columnMenu.l.RUnlock()
+		return t.columnMenu.menuHasFocus()
+	}
+
+	t.RLock()
+	editor := t.editor
+	t.RUnlock()
+	if editor != nil && editor.GetFocusable().HasFocus() {
+		return true
+	}
+	return t.Box.HasFocus()
+}
+
 // InputHandler returns the handler for this primitive.
 func (t *Table) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return t.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		keys := t.GetKeys()
+
 		t.Lock()
 		defer t.Unlock()
 
 		key := event.Key()
 
+		// Pass events to the editor.
+		if editor := t.editor; editor != nil {
+			t.Unlock()
+			editor.InputHandler()(event, setFocus)
+			t.Lock()
+			return
+		}
+
+		// Editing the filter row or typing a search query.
+		previouslySelectedRow, previouslySelectedColumn := t.contentRow(t.selectedRow), t.contentColumn(t.selectedColumn)
+		if t.filterEditing {
+			t.handleFilterKey(event)
+			t.notifySelectionChanged(previouslySelectedRow, previouslySelectedColumn)
+			return
+		} else if t.searching {
+			t.handleSearchKey(event)
+			t.notifySelectionChanged(previouslySelectedRow, previouslySelectedColumn)
+			return
+		}
+
+		if key == tcell.KeyEscape && t.searchQuery != "" {
+			t.searchQuery = "" // End the search first.
+			return
+		}
+
 		if (!t.rowsSelectable && !t.columnsSelectable && key == tcell.KeyEnter) ||
 			key == tcell.KeyEscape ||
 			key == tcell.KeyTab ||
@@ -1339,13 +1628,14 @@ func (t *Table) InputHandler() func(event *tcell.EventKey, setFocus func(p Primi
 		}
 
 		// Movement functions.
-		previouslySelectedRow, previouslySelectedColumn := t.selectedRow, t.selectedColumn
+		rowCount, lastColumn := t.rowCount(), t.columnCount()-1
+		fixedRows := t.displayFixedRows()
 		var (
 			validSelection = func(row, column int) bool {
-				if row < t.fixedRows || row >= len(t.cells) || column < t.fixedColumns || column > t.lastColumn {
+				if row < fixedRows || row >= rowCount || column < t.fixedColumns || column > lastColumn {
 					return false
 				}
-				cell := t.cells[row][column]
+				cell := t.displayCell(row, column)
 				return cell == nil || !cell.NotSelectable
 			}
 
@@ -1362,8 +1652,8 @@ func (t *Table) InputHandler() func(event *tcell.EventKey, setFocus func(p Primi
 
 			end = func() {
 				if t.rowsSelectable {
-					t.selectedRow = len(t.cells) - 1
-					t.selectedColumn = t.lastColumn
+					t.selectedRow = rowCount - 1
+					t.selectedColumn = lastColumn
 				} else {
 					t.trackEnd = true
 					t.columnOffset = 0
@@ -1412,15 +1702,15 @@ func (t *Table) InputHandler() func(event *tcell.EventKey, setFocus func(p Primi
 			}
 
 			pageDown = func() {
-				offsetAmount := t.visibleRows - t.fixedRows
+				offsetAmount := t.visibleRows - fixedRows
 				if offsetAmount < 0 {
 					offsetAmount = 0
 				}
 
 				if t.rowsSelectable {
 					t.selectedRow += offsetAmount
-					if t.selectedRow >= len(t.cells) {
-						t.selectedRow = len(t.cells) - 1
+					if t.selectedRow >= rowCount {
+						t.selectedRow = rowCount - 1
 					}
 				} else {
 					t.rowOffset += offsetAmount
@@ -1428,7 +1718,7 @@ func (t *Table) InputHandler() func(event *tcell.EventKey, setFocus func(p Primi
 			}
 
 			pageUp = func() {
-				offsetAmount := t.visibleRows - t.fixedRows
+				offsetAmount := t.visibleRows - fixedRows
 				if offsetAmount < 0 {
 					offsetAmount = 0
 				}
@@ -1445,48 +1735,166 @@ func (t *Table) InputHandler() func(event *tcell.EventKey, setFocus func(p Primi
 			}
 		)
 
-		if HitShortcut(event, Keys.MoveFirst, Keys.MoveFirst2) {
+		multiSelect := t.multiSelect && t.rowsSelectable
+		previouslySelectedRows := t.selectedRowsSnapshot()
+		if multiSelect && HitShortcut(event, keys.SelectUp, keys.SelectDown, keys.SelectFirst, keys.SelectLast, keys.SelectPreviousPage, keys.SelectNextPage) {
+			switch {
+			case HitShortcut(event, keys.SelectUp):
+				up()
+			case HitShortcut(event, keys.SelectDown):
+				down()
+			case HitShortcut(event, keys.SelectFirst):
+				home()
+			case HitShortcut(event, keys.SelectLast):
+				end()
+			case HitShortcut(event, keys.SelectPreviousPage):
+				pageUp()
+			default:
+				pageDown()
+			}
+			t.selectRowRange()
+			t.notifySelectionChanged(previouslySelectedRow, previouslySelectedColumn)
+			t.notifySelectedRowsChanged(previouslySelectedRows)
+			return
+		} else if multiSelect && HitShortcut(event, keys.SelectAll) {
+			t.selectAllRows()
+		} else if multiSelect && HitShortcut(event, keys.Select2) {
+			t.toggleRow()
+		} else if HitShortcut(event, keys.MoveFirst, keys.MoveFirst2) {
 			home()
-		} else if HitShortcut(event, Keys.MoveLast, Keys.MoveLast2) {
+		} else if HitShortcut(event, keys.MoveLast, keys.MoveLast2) {
 			end()
-		} else if HitShortcut(event, Keys.MoveUp, Keys.MoveUp2, Keys.MovePreviousField) {
+		} else if HitShortcut(event, keys.MoveUp, keys.MoveUp2, keys.MovePreviousField) {
 			up()
-		} else if HitShortcut(event, Keys.MoveDown, Keys.MoveDown2, Keys.MoveNextField) {
+		} else if HitShortcut(event, keys.MoveDown, keys.MoveDown2, keys.MoveNextField) {
 			down()
-		} else if HitShortcut(event, Keys.MoveLeft, Keys.MoveLeft2) {
+		} else if HitShortcut(event, keys.MoveLeft, keys.MoveLeft2) {
 			left()
-		} else if HitShortcut(event, Keys.MoveRight, Keys.MoveRight2) {
+		} else if HitShortcut(event, keys.MoveRight, keys.MoveRight2) {
 			right()
-		} else if HitShortcut(event, Keys.MovePreviousPage) {
+		} else if HitShortcut(event, keys.MovePreviousPage) {
 			pageUp()
-		} else if HitShortcut(event, Keys.MoveNextPage) {
+		} else if HitShortcut(event, keys.MoveNextPage) {
 			pageDown()
-		} else if HitShortcut(event, Keys.Select, Keys.Select2) {
+		} else if t.cellsEditable && HitShortcut(event, keys.Select, keys.Edit) {
+			if editor := t.startEdit(setFocus); editor != nil && setFocus != nil {
+				t.Unlock()
+				setFocus(editor)
+				t.Lock()
+			}
+		} else if HitShortcut(event, keys.Select, keys.Select2) {
 			if (t.rowsSelectable || t.columnsSelectable) && t.selected != nil {
+				row, column := t.contentRow(t.selectedRow), t.contentColumn(t.selectedColumn)
 				t.Unlock()
-				t.selected(t.selectedRow, t.selectedColumn)
+				t.selected(row, column)
 				t.Lock()
 			}
-		}
-
-		// If the selection has changed, notify the handler.
-		if t.selectionChanged != nil && ((t.rowsSelectable && previouslySelectedRow != t.selectedRow) || (t.columnsSelectable && previouslySelectedColumn != t.selectedColumn)) {
+		} else if HitShortcut(event, keys.Search) {
+			t.searching, t.searchQuery = true, ""
+			t.searchRow, t.searchColumn, t.searchOffset = t.selectedRow, t.selectedColumn, t.rowOffset
+		} else if HitShortcut(event, keys.SearchNext) {
+			t.searchSelect(t.selectedRow, t.selectedColumn, 1, true)
+		} else if HitShortcut(event, keys.SearchPrevious) {
+			t.searchSelect(t.selectedRow, t.selectedColumn, -1, true)
+		} else if HitShortcut(event, keys.ShowContextMenu) {
 			t.Unlock()
-			t.selectionChanged(t.selectedRow, t.selectedColumn)
+			t.showColumnMenu(-1, -1, setFocus)
 			t.Lock()
+		} else if HitShortcut(event, keys.GrowColumn) {
+			if t.columnsResizable {
+				t.resizeColumn(t.currentColumn(), 1)
+			}
+		} else if HitShortcut(event, keys.ShrinkColumn) {
+			if t.columnsResizable {
+				t.resizeColumn(t.currentColumn(), -1)
+			}
+		} else if HitShortcut(event, keys.MoveColumnLeft) {
+			if column := t.currentColumn(); t.columnsReorderable && t.moveDisplayColumn(column, column-1) && t.columnsSelectable {
+				t.selectedColumn = column - 1
+			}
+		} else if HitShortcut(event, keys.MoveColumnRight) {
+			if column := t.currentColumn(); t.columnsReorderable && column < t.columnCount()-1 && t.moveDisplayColumn(column, column+1) && t.columnsSelectable {
+				t.selectedColumn = column + 1
+			}
+		} else if HitShortcut(event, keys.Filter) {
+			if t.filterRow && lastColumn >= 0 {
+				t.filterEditing = true
+				if t.columnsSelectable && t.selectedColumn <= lastColumn {
+					t.filterColumn = t.selectedColumn
+				} else if len(t.visibleColumnIndices) > t.fixedColumns {
+					t.filterColumn = t.visibleColumnIndices[t.fixedColumns]
+				} else {
+					t.filterColumn = 0
+				}
+			}
+		}
+
+		if multiSelect {
+			t.selectionAnchor = t.contentRow(t.selectedRow)
 		}
+		t.notifySelectionChanged(previouslySelectedRow, previouslySelectedColumn)
+		t.notifySelectedRowsChanged(previouslySelectedRows)
 	})
 }
 
+// notifySelectionChanged calls the selection changed handler if the selection
+// differs from the given row of the content and column. The table must be
+// locked.
+func (t *Table) notifySelectionChanged(previousRow, previousColumn int) {
+	row, column := t.contentRow(t.selectedRow), t.contentColumn(t.selectedColumn)
+	if t.selectionChanged != nil && ((t.rowsSelectable && previousRow != row) || (t.columnsSelectable && previousColumn != column)) {
+		t.Unlock()
+		t.selectionChanged(row, column)
+		t.Lock()
+	}
+}
+
 // MouseHandler returns the mouse handler for this primitive.
 func (t *Table) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 	return t.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 		x, y := event.Position()
+
+		// Pass events to the column menu.
+		if t.columnMenu.ContextMenuVisible() {
+			if menu := t.columnMenu.listAtPoint(x, y); menu != nil {
+				return menu.MouseHandler()(action, event, setFocus)
+			}
+			switch action {
+			case MouseLeftClick, MouseMiddleClick, MouseRightClick:
+				t.columnMenu.HideContextMenu(setFocus)
+			}
+			return true, nil
+		}
+
+		// Pass events to the editor. Clicking elsewhere accepts the edit.
+		t.RLock()
+		editor := t.editor
+		t.RUnlock()
+		if editor != nil {
+			if consumed, capture := editor.MouseHandler()(action, event, setFocus); consumed {
+				return consumed, capture
+			}
+			switch action {
+			case MouseLeftClick, MouseMiddleClick, MouseRightClick:
+				t.finishEdit(true, setFocus)
+			}
+		}
+
+		// Resize and reorder columns.
+		if t.handleColumnDrag(action, x, y) {
+			return true, t
+		}
+
 		if !t.InRect(x, y) {
 			return false, nil
 		}
 
 		switch action {
+		case MouseRightClick:
+			if row, _ := t.cellAt(x, y); row >= 0 && row < t.fixedRows {
+				t.showColumnMenu(x, y, setFocus)
+				consumed = true
+			}
 		case MouseLeftClick:
 			_, tableY, _, _ := t.GetInnerRect()
 			mul := 1
@@ -1498,23 +1906,46 @@ func (t *Table) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 
 			if t.sortClicked && t.fixedRows > 0 && (y >= tableY && y < maxY+(t.fixedRows*mul)) {
 				_, column := t.cellAt(x, y)
-				if t.sortClickedColumn != column {
-					t.sortClickedColumn = column
-					t.sortClickedDescending = false
-				} else {
-					t.sortClickedDescending = !t.sortClickedDescending
-				}
-				t.Sort(column, t.sortClickedDescending)
-
+				t.Lock()
+				t.sortClick(t.contentColumn(column), event.Modifiers()&tcell.ModShift != 0)
 				if t.columnsSelectable {
 					t.selectedColumn = column
 				}
+				t.Unlock()
+			} else if row, column := t.cellAt(x, y); t.isFilterRow(row) {
+				t.Lock()
+				if column >= 0 {
+					t.filterEditing, t.filterColumn = true, column
+				}
+				t.Unlock()
 			} else if t.rowsSelectable || t.columnsSelectable {
-				t.Select(t.cellAt(x, y))
+				t.RLock()
+				if row >= 0 {
+					row = t.contentRow(row)
+				}
+				t.RUnlock()
+				t.Select(row, column)
+
+				t.Lock()
+				if t.multiSelect && t.rowsSelectable {
+					t.clickRow(event.Modifiers())
+				}
+				t.Unlock()
 			}
 
 			consumed = true
 			setFocus(t)
+		case MouseLeftDoubleClick:
+			t.Lock()
+			var editor FormItem
+			if row, column := t.cellAt(x, y); row == t.selectedRow && column == t.selectedColumn {
+				editor = t.startEdit(setFocus)
+			}
+			t.Unlock()
+			if editor != nil {
+				setFocus(editor)
+			}
+			consumed = true
 		case MouseScrollUp:
 			t.trackEnd = false
 			t.rowOffset--
@@ -1527,3 +1958,45 @@ func (t *Table) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 		return
 	})
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme, including the colors of the cells of the default in-memory
+// content. Colors which were set explicitly are kept.
+func (t *Table) ApplyTheme(previous, theme *Theme) {
+	t.Box.ApplyTheme(previous, theme)
+
+	t.Lock()
+	defer t.Unlock()
+
+	themeColor(&t.scrollBarColor, previous.ScrollBarColor, theme.ScrollBarColor)
+	themeColor(&t.bordersColor, previous.GraphicsColor, theme.GraphicsColor)
+	themeColor(&t.filterColor, previous.SecondaryTextColor, theme.SecondaryTextColor)
+	themeColor(&t.searchColor, previous.MoreContrastBackgroundColor, theme.MoreContrastBackgroundColor)
+	themeColor(&t.selectedRowsColor, previous.SecondaryTextColor, theme.SecondaryTextColor)
+	t.columnMenu.applyTheme(previous, theme)
+	if t.inputEditor != nil {
+		t.inputEditor.ApplyTheme(previous, theme)
+	}
+	for _, editor := range t.columnEditors {
+		ApplyTheme(editor, previous, theme)
+	}
+
+	// The cells of other contents are created by their implementation.
+	content, ok := t.content.(*tableContentData)
+	if !ok {
+		return
+	}
+	content.RLock()
+	defer content.RUnlock()
+
+	for _, row := range content.cells {
+		for _, cell := range row {
+			if cell == nil {
+				continue
+			}
+			cell.Lock()
+			themeColor(&cell.Color, previous.PrimaryTextColor, theme.PrimaryTextColor)
+			cell.Unlock()
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package crtview
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/gdamore/tcell/v2"
 )
 
 var tableTestCases = generateTableTestCases()
@@ -104,3 +108,622 @@ func tc(c *tableTestCase) *Table {
 
 	return table
 }
+
+// countingContent is a read-only TableContent of generated cells which counts
+// the cells requested.
+type countingContent struct {
+	rows, columns int
+	requested     int
+}
+
+func (c *countingContent) GetCell(row, column int) *TableCell {
+	c.requested++
+	return NewTableCell(fmt.Sprintf("%d,%d", column, row))
+}
+
+func (c *countingContent) GetRowCount() int {
+	return c.rows
+}
+
+func (c *countingContent) GetColumnCount() int {
+	return c.columns
+}
+
+func TestTableContent(t *testing.T) {
+	t.Parallel()
+
+	content := &countingContent{rows: 500000, columns: 3}
+	table := NewTable()
+	table.SetContent(content)
+
+	app, err := newTestApp(table)
+	if err != nil {
+		t.Fatalf("failed to initialize Application: %s", err)
+	}
+	table.SetRect(0, 0, 80, 24)
+	table.Select(250000, 0)
+	table.SetSelectable(true, false)
+	table.Draw(app.screen)
+
+	if content.requested > 1000 {
+		t.Errorf("failed to materialize visible rows only: expected at most 1000 cells, got %d", content.requested)
+	}
+	if got := screenLine(app.screen, 23); !strings.HasPrefix(got, "0,250000 1,250000 2,250000") {
+		t.Errorf("failed to draw selected row: got %q", got)
+	}
+
+	// Read-only contents are not modified.
+	table.SetCellSimple(0, 0, "x")
+	table.InsertRow(0)
+	table.Sort(0, true)
+	if table.GetRowCount() != 500000 || table.GetCell(0, 0).GetText() != "0,0" {
+		t.Errorf("failed to ignore modification of read-only content")
+	}
+
+	// Editable contents are sorted.
+	table.SetContent(nil)
+	for row, text := range []string{"b", "c", "a"} {
+		table.SetCellSimple(row, 0, text)
+	}
+	table.Sort(0, false)
+	if table.GetCell(0, 0).GetText() != "a" || table.GetCell(2, 0).GetText() != "c" {
+		t.Errorf("failed to sort in-memory content")
+	}
+}
+
+func newFilterTestTable() *Table {
+	table := NewTable()
+	table.SetFixed(1, 0)
+	table.SetSelectable(true, false)
+	for column, text := range []string{"Name", "Size"} {
+		table.SetCellSimple(0, column, text)
+	}
+	for row, name := range []string{"beta", "alpha", "gamma", "alphabet"} {
+		table.SetCellSimple(row+1, 0, name)
+		table.SetCellSimple(row+1, 1, fmt.Sprintf("%d", len(name)))
+	}
+	table.SetRect(0, 0, 80, 24)
+	return table
+}
+
+func TestTableFilter(t *testing.T) {
+	t.Parallel()
+
+	table := newFilterTestTable()
+	app, err := newTestApp(table)
+	if err != nil {
+		t.Fatalf("failed to initialize Application: %s", err)
+	}
+
+	table.SetColumnFilterText(0, "ALPHA")
+	if count := table.GetFilteredRowCount(); count != 3 {
+		t.Errorf("failed to filter rows by text: expected 3 rows, got %d", count)
+	}
+	table.SetColumnFilter(1, func(cell *TableCell) bool {
+		return cell.GetText() == "8"
+	})
+	if count := table.GetFilteredRowCount(); count != 2 {
+		t.Errorf("failed to filter rows by function: expected 2 rows, got %d", count)
+	}
+	table.SetColumnFilter(1, nil)
+
+	// Filters are applied again after sorting, fixed rows are kept.
+	table.Sort(0, true)
+	table.SetFilterRow(true)
+	table.Draw(app.screen)
+	expected := []string{"Name   ▼ Size", "ALPHA", "alphabet 8", "alpha    5", ""}
+	for y, line := range expected {
+		if got := screenLine(app.screen, y); got != line {
+			t.Errorf("failed to draw filtered line %d: expected %q, got %q", y, line, got)
+		}
+	}
+
+	// The selection refers to rows of the content.
+	table.Select(3, 0)
+	if row, _ := table.GetSelection(); row != 3 || table.GetCell(row, 0).GetText() != "alphabet" {
+		t.Errorf("failed to select filtered row: expected 3, got %d", row)
+	}
+	handler := table.InputHandler()
+	handler(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone), nil)
+	if row, _ := table.GetSelection(); row != 4 {
+		t.Errorf("failed to move to next filtered row: expected 4, got %d", row)
+	}
+	handler(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone), nil)
+	if row, _ := table.GetSelection(); row != 4 {
+		t.Errorf("failed to stop at last filtered row: expected 4, got %d", row)
+	}
+	handler(tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone), nil)
+	handler(tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone), nil)
+	if row, _ := table.GetSelection(); row != 3 {
+		t.Errorf("failed to skip filter row: expected 3, got %d", row)
+	}
+
+	// Edit the filter row.
+	handler(tcell.NewEventKey(tcell.KeyRune, 'f', tcell.ModNone), nil)
+	handler(tcell.NewEventKey(tcell.KeyCtrlU, 0, tcell.ModCtrl), nil)
+	for _, r := range "mm" {
+		handler(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone), nil)
+	}
+	handler(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), nil)
+	if text := table.GetColumnFilterText(0); text != "mm" {
+		t.Errorf("failed to edit filter text: expected mm, got %q", text)
+	}
+	if row, _ := table.GetSelection(); row < 0 || table.GetCell(row, 0).GetText() != "gamma" {
+		t.Errorf("failed to select remaining row: got %d", row)
+	}
+
+	table.ClearFilters()
+	table.SetFilterRow(false)
+	if count := table.GetFilteredRowCount(); count != 5 {
+		t.Errorf("failed to clear filters: expected 5 rows, got %d", count)
+	}
+
+	// Rows are filtered again only when the content changes.
+	content := &countingContent{rows: 10000, columns: 2}
+	table.SetContent(content)
+	table.SetColumnFilter(0, func(cell *TableCell) bool {
+		return cell.GetText() != "0,5"
+	})
+	table.Draw(app.screen)
+	content.requested = 0
+	table.Draw(app.screen)
+	if content.requested >= content.rows {
+		t.Errorf("failed to keep filtered rows: %d cells requested", content.requested)
+	}
+	content.rows++
+	table.Draw(app.screen)
+	if count := table.GetFilteredRowCount(); count != content.rows-1 {
+		t.Errorf("failed to filter added row: expected %d rows, got %d", content.rows-1, count)
+	}
+}
+
+func TestTableSearch(t *testing.T) {
+	t.Parallel()
+
+	table := newFilterTestTable()
+	table.SetSelectable(true, true)
+	app, err := newTestApp(table)
+	if err != nil {
+		t.Fatalf("failed to initialize Application: %s", err)
+	}
+	table.Draw(app.screen)
+
+	handler := table.InputHandler()
+	handler(tcell.NewEventKey(tcell.KeyRune, '/', tcell.ModNone), nil)
+	for _, r := range "ALP" {
+		handler(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone), nil)
+	}
+	table.Draw(app.screen)
+	if got := screenLine(app.screen, 23); got != "/ALP" {
+		t.Errorf("failed to draw search query: expected /ALP, got %q", got)
+	}
+	handler(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), nil)
+	if row, column := table.GetSelection(); row != 2 || column != 0 {
+		t.Errorf("failed to select first match: expected 2,0, got %d,%d", row, column)
+	}
+
+	handler(tcell.NewEventKey(tcell.KeyRune, 'n', tcell.ModNone), nil)
+	if row, _ := table.GetSelection(); row != 4 {
+		t.Errorf("failed to select next match: expected 4, got %d", row)
+	}
+	handler(tcell.NewEventKey(tcell.KeyRune, 'n', tcell.ModNone), nil)
+	if row, _ := table.GetSelection(); row != 2 {
+		t.Errorf("failed to wrap to first match: expected 2, got %d", row)
+	}
+	handler(tcell.NewEventKey(tcell.KeyRune, 'N', tcell.ModNone), nil)
+	if row, _ := table.GetSelection(); row != 4 {
+		t.Errorf("failed to select previous match: expected 4, got %d", row)
+	}
+
+	// Matches are highlighted.
+	table.Draw(app.screen)
+	_, _, style, _ := app.screen.GetContent(0, 2)
+	if _, bg, _ := style.Decompose(); bg != Styles.MoreContrastBackgroundColor {
+		t.Errorf("failed to highlight match: expected %v, got %v", Styles.MoreContrastBackgroundColor, bg)
+	}
+
+	// Matches which are filtered out are skipped.
+	table.SetColumnFilterText(1, "8")
+	if !table.SearchNext() {
+		t.Errorf("failed to find match")
+	} else if row, _ := table.GetSelection(); row != 4 {
+		t.Errorf("failed to skip filtered match: expected 4, got %d", row)
+	}
+
+	handler(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone), nil)
+	if query := table.GetSearch(); query != "" {
+		t.Errorf("failed to end search: got %q", query)
+	}
+}
+
+func TestTableSortBy(t *testing.T) {
+	t.Parallel()
+
+	table := NewTable()
+	table.SetFixed(1, 0)
+	table.SetCellSimple(0, 0, "Type")
+	table.SetCellSimple(0, 1, "Size")
+	for row, cells := range [][]string{{"b", "1.5G"}, {"a", "512"}, {"b", "10K"}, {"a", "2M"}, {"b", "10K"}} {
+		table.SetCell(row+1, 0, NewTableCell(cells[0]).SetReference(row))
+		table.SetCellSimple(row+1, 1, cells[1])
+	}
+	table.SetColumnComparator(1, CompareHumanSize)
+	table.SortBy(TableSortKey{Column: 0}, TableSortKey{Column: 1, Descending: true})
+
+	expected := []string{"a 2M", "a 512", "b 1.5G", "b 10K", "b 10K"}
+	for i, line := range expected {
+		got := table.GetCell(i+1, 0).GetText() + " " + table.GetCell(i+1, 1).GetText()
+		if got != line {
+			t.Errorf("failed to sort row %d: expected %q, got %q", i+1, line, got)
+		}
+	}
+	// Equal rows keep their order.
+	if table.GetCell(4, 0).GetReference() != 2 || table.GetCell(5, 0).GetReference() != 4 {
+		t.Errorf("failed to sort stably")
+	}
+
+	app, err := newTestApp(table)
+	if err != nil {
+		t.Fatalf("failed to initialize Application: %s", err)
+	}
+	table.SetRect(0, 0, 80, 24)
+	table.Draw(app.screen)
+	if got := screenLine(app.screen, 0); got != "Type ▲1 Size ▼2" {
+		t.Errorf("failed to draw sort indicators: expected %q, got %q", "Type ▲1 Size ▼2", got)
+	}
+
+	// Clicking replaces the sort keys, Shift-clicking adds one.
+	table.sortClick(1, false)
+	table.sortClick(0, true)
+	keys := table.GetSortKeys()
+	if len(keys) != 2 || keys[0] != (TableSortKey{Column: 1}) || keys[1] != (TableSortKey{Column: 0}) {
+		t.Errorf("failed to add sort key: got %v", keys)
+	}
+	table.sortClick(0, true)
+	if keys = table.GetSortKeys(); keys[1] != (TableSortKey{Column: 0, Descending: true}) {
+		t.Errorf("failed to reverse sort key: got %v", keys)
+	}
+}
+
+func TestTableComparators(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		compare func(a, b string) int
+		a, b    string
+		less    bool
+	}{
+		{CompareText, "B", "a", true},
+		{CompareNatural, "file2", "file10", true},
+		{CompareNatural, "File2", "file1", false},
+		{CompareNatural, "a", "ab", true},
+		{CompareNumeric, "9", "10", true},
+		{CompareNumeric, "1,000", "999.5", false},
+		{CompareNumeric, "-3", "x", true},
+		{CompareHumanSize, "1.5G", "900M", false},
+		{CompareHumanSize, "512", "1K", true},
+		{CompareHumanSize, "10 MB", "3GiB", true},
+		{CompareDate, "2020-12-31", "2021-01-01", true},
+		{CompareDate, "02.01.2021", "Jan 1, 2021", false},
+		{CompareDate, "2021-01-01", "unknown", true},
+	}
+	for _, c := range testCases {
+		if less := c.compare(c.a, c.b) < 0; less != c.less {
+			t.Errorf("failed to compare %q and %q: expected less %t, got %t", c.a, c.b, c.less, less)
+		}
+		if c.compare(c.a, c.a) != 0 {
+			t.Errorf("failed to compare %q to itself", c.a)
+		}
+	}
+}
+
+func TestTableColumnLayout(t *testing.T) {
+	t.Parallel()
+
+	table := NewTable()
+	table.SetFixed(1, 0)
+	table.SetSelectable(true, true)
+	for column, text := range []string{"A", "B", "C"} {
+		table.SetCellSimple(0, column, text)
+		table.SetCellSimple(1, column, strings.Repeat(strings.ToLower(text), 3))
+	}
+	app, err := newTestApp(table)
+	if err != nil {
+		t.Fatalf("failed to initialize Application: %s", err)
+	}
+	table.SetRect(0, 0, 80, 24)
+
+	table.SetColumnOrder([]int{2})
+	table.SetColumnHidden(1, true)
+	table.SetColumnWidth(0, 5)
+	table.Draw(app.screen)
+	if got := screenLine(app.screen, 1); got != "ccc aaa" {
+		t.Errorf("failed to draw column layout: expected %q, got %q", "ccc aaa", got)
+	}
+	if order := table.GetColumnOrder(); fmt.Sprint(order) != "[2 0 1]" {
+		t.Errorf("failed to get column order: expected [2 0 1], got %v", order)
+	}
+
+	// The selection refers to columns of the content.
+	table.Select(1, 0)
+	if row, column := table.GetSelection(); row != 1 || column != 0 {
+		t.Errorf("failed to select column: expected 1,0, got %d,%d", row, column)
+	}
+
+	// Save and restore the layout.
+	data, err := json.Marshal(table.GetColumnLayout())
+	if err != nil {
+		t.Fatalf("failed to encode column layout: %s", err)
+	}
+	if string(data) != `{"order":[2,0,1],"hidden":[1],"widths":{"0":5}}` {
+		t.Errorf("failed to encode column layout: got %s", data)
+	}
+	table.SetColumnLayout(TableColumnLayout{})
+	table.Draw(app.screen)
+	if got := screenLine(app.screen, 1); got != "aaa bbb ccc" {
+		t.Errorf("failed to reset column layout: got %q", got)
+	}
+	var layout TableColumnLayout
+	if err := json.Unmarshal(data, &layout); err != nil {
+		t.Fatalf("failed to decode column layout: %s", err)
+	}
+	table.SetColumnLayout(layout)
+	if table.GetColumnWidth(0) != 5 || !table.IsColumnHidden(1) {
+		t.Errorf("failed to restore column layout")
+	}
+	table.SetColumnLayout(TableColumnLayout{})
+
+	// Move and resize the selected column with the keyboard.
+	handler := table.InputHandler()
+	handler(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModAlt), nil)
+	handler(tcell.NewEventKey(tcell.KeyRune, '>', tcell.ModNone), nil)
+	table.Draw(app.screen)
+	if got := screenLine(app.screen, 1); got != "bbb aaa  ccc" {
+		t.Errorf("failed to move and resize column: expected %q, got %q", "bbb aaa  ccc", got)
+	}
+	if _, column := table.GetSelection(); column != 0 {
+		t.Errorf("failed to keep moved column selected: expected 0, got %d", column)
+	}
+
+	// Resize a column by dragging its separator, move it by its header.
+	mouse := table.MouseHandler()
+	drag := func(fromX, fromY, toX, toY int) {
+		mouse(MouseLeftDown, tcell.NewEventMouse(fromX, fromY, tcell.ButtonPrimary, 0), func(Primitive) {})
+		mouse(MouseMove, tcell.NewEventMouse(toX, toY, tcell.ButtonPrimary, 0), func(Primitive) {})
+		mouse(MouseLeftUp, tcell.NewEventMouse(toX, toY, 0, 0), func(Primitive) {})
+		table.Draw(app.screen)
+	}
+	drag(3, 1, 5, 1)
+	if got := screenLine(app.screen, 1); got != "bbb   aaa  ccc" {
+		t.Errorf("failed to resize column by dragging: got %q", got)
+	}
+	drag(0, 0, 12, 0)
+	if got := screenLine(app.screen, 1); got != "aaa  ccc bbb" {
+		t.Errorf("failed to move column by dragging: got %q", got)
+	}
+
+	// Hide a column in the column menu.
+	table.showColumnMenu(-1, -1, func(Primitive) {})
+	table.columnMenu.ContextMenuList().InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), func(Primitive) {})
+	if !table.IsColumnHidden(0) || table.columnMenu.ContextMenuVisible() {
+		t.Errorf("failed to hide column in column menu")
+	}
+}
+
+func TestTableMultiSelect(t *testing.T) {
+	t.Parallel()
+
+	table := newFilterTestTable()
+	table.SetMultiSelect(true)
+	var changed []int
+	table.SetSelectedRowsChangedFunc(func(rows []int) {
+		changed = rows
+	})
+
+	handler := table.InputHandler()
+	table.Select(1, 0)
+	handler(tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone), nil)
+	if rows := table.GetSelectedRows(); fmt.Sprint(rows) != "[1]" || fmt.Sprint(changed) != "[1]" {
+		t.Errorf("failed to toggle row: expected [1], got %v (reported %v)", rows, changed)
+	}
+	handler(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModShift), nil)
+	handler(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModShift), nil)
+	if rows := table.GetSelectedRows(); fmt.Sprint(rows) != "[1 2 3]" || fmt.Sprint(changed) != "[1 2 3]" {
+		t.Errorf("failed to select range: expected [1 2 3], got %v (reported %v)", rows, changed)
+	}
+	handler(tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModShift), nil)
+	if rows := table.GetSelectedRows(); fmt.Sprint(rows) != "[1 2]" {
+		t.Errorf("failed to shrink range: expected [1 2], got %v", rows)
+	}
+
+	// Moving without Shift starts a new range.
+	handler(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone), nil)
+	handler(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModShift), nil)
+	if rows := table.GetSelectedRows(); fmt.Sprint(rows) != "[3 4]" {
+		t.Errorf("failed to start new range: expected [3 4], got %v", rows)
+	}
+
+	handler(tcell.NewEventKey(tcell.KeyCtrlA, 0, tcell.ModCtrl), nil)
+	if rows := table.GetSelectedRows(); fmt.Sprint(rows) != "[1 2 3 4]" {
+		t.Errorf("failed to select all rows: expected [1 2 3 4], got %v", rows)
+	}
+
+	// Selected rows follow the content.
+	table.SetSelectedRows([]int{0, 1, 4})
+	table.RemoveRow(2)
+	if rows := table.GetSelectedRows(); fmt.Sprint(rows) != "[1 3]" {
+		t.Errorf("failed to shift selected rows: expected [1 3], got %v", rows)
+	}
+	table.SortBy(TableSortKey{Column: 0})
+	var names []string
+	for _, row := range table.GetSelectedRows() {
+		names = append(names, table.GetCell(row, 0).GetText())
+	}
+	if strings.Join(names, ",") != "alphabet,beta" {
+		t.Errorf("failed to keep selected rows after sorting: expected alphabet,beta, got %v", names)
+	}
+
+	table.SetMultiSelect(false)
+	if rows := table.GetSelectedRows(); len(rows) != 0 {
+		t.Errorf("failed to clear selected rows: expected none, got %v", rows)
+	}
+}
+
+func TestTableEdit(t *testing.T) {
+	t.Parallel()
+
+	table := newFilterTestTable()
+	table.SetSelectable(true, true)
+	table.SetCellsEditable(true)
+	table.SetCellValidateFunc(func(row, column int, text string) bool {
+		return text != ""
+	})
+	var edits []string
+	table.SetCellEditedFunc(func(row, column int, oldText, newText string) {
+		edits = append(edits, fmt.Sprintf("%d,%d:%s->%s", row, column, oldText, newText))
+	})
+	app, err := newTestApp(table)
+	if err != nil {
+		t.Fatalf("failed to initialize Application: %s", err)
+	}
+
+	handler := table.InputHandler()
+	key := func(key tcell.Key, r rune) {
+		handler(tcell.NewEventKey(key, r, tcell.ModNone), nil)
+	}
+
+	// Edit a cell in an input field drawn over the cell.
+	table.Select(2, 0)
+	key(tcell.KeyEnter, 0)
+	if !table.IsEditing() {
+		t.Fatalf("failed to start editing")
+	}
+	key(tcell.KeyCtrlU, 0)
+	for _, r := range "delta" {
+		key(tcell.KeyRune, r)
+	}
+	table.Draw(app.screen)
+	if line := screenLine(app.screen, 2); !strings.HasPrefix(line, "delta") {
+		t.Errorf("failed to draw editor: expected delta, got %q", line)
+	}
+	key(tcell.KeyEnter, 0)
+	if table.IsEditing() || table.GetCell(2, 0).GetText() != "delta" {
+		t.Errorf("failed to accept edit: expected delta, got %s", table.GetCell(2, 0).GetText())
+	}
+
+	// Rejected values keep the editor open, Escape cancels.
+	key(tcell.KeyF2, 0)
+	key(tcell.KeyCtrlU, 0)
+	key(tcell.KeyEnter, 0)
+	if !table.IsEditing() {
+		t.Errorf("failed to reject empty value")
+	}
+	key(tcell.KeyEscape, 0)
+	if table.IsEditing() || table.GetCell(2, 0).GetText() != "delta" {
+		t.Errorf("failed to cancel edit: expected delta, got %s", table.GetCell(2, 0).GetText())
+	}
+
+	// Fixed rows are not edited.
+	table.Select(0, 0)
+	key(tcell.KeyEnter, 0)
+	if table.IsEditing() {
+		t.Errorf("failed to protect fixed row")
+	}
+
+	// Check boxes toggle the cell.
+	table.SetColumnEditor(1, NewCheckBox())
+	table.SetCellSimple(1, 1, "false")
+	table.Select(1, 1)
+	key(tcell.KeyF2, 0)
+	key(tcell.KeyRune, ' ')
+	if table.IsEditing() || table.GetCell(1, 1).GetText() != "true" {
+		t.Errorf("failed to toggle check box: expected true, got %s", table.GetCell(1, 1).GetText())
+	}
+
+	// Typed text is stored escaped.
+	table.Select(2, 0)
+	key(tcell.KeyF2, 0)
+	key(tcell.KeyCtrlU, 0)
+	for _, r := range "[red]" {
+		key(tcell.KeyRune, r)
+	}
+	key(tcell.KeyEnter, 0)
+	if text := table.GetCell(2, 0).GetText(); text != "[red[]" {
+		t.Errorf("failed to escape edited text: expected [red[], got %s", text)
+	}
+
+	expected := "[2,0:alpha->delta 1,1:false->true 2,0:delta->[red]]"
+	if fmt.Sprint(edits) != expected {
+		t.Errorf("failed to report edits: expected %s, got %v", expected, edits)
+	}
+
+	// Read-only contents are not edited and pasted keys are passed on.
+	table.SetContent(&countingContent{rows: 3, columns: 2})
+	table.Select(1, 0)
+	key(tcell.KeyF2, 0)
+	if table.IsEditing() {
+		t.Errorf("failed to protect read-only content")
+	} else if table.PasteHandler() != nil {
+		t.Errorf("failed to pass on pasted keys while not editing")
+	}
+}
+
+func TestTableExport(t *testing.T) {
+	t.Parallel()
+
+	table := newFilterTestTable()
+	table.SetCellSimple(2, 0, "[red]al|pha")
+	table.GetCell(0, 1).SetAlign(AlignRight)
+	table.SetColumnFilterText(0, "al")
+
+	testCases := []struct {
+		format   TableExportFormat
+		options  TableExportOptions
+		expected string
+	}{
+		{TableExportCSV, TableExportOptions{}, "Name,Size\nbeta,4\nal|pha,5\ngamma,5\nalphabet,8\n"},
+		{TableExportTSV, TableExportOptions{FilteredRows: true}, "Name\tSize\nal|pha\t5\nalphabet\t8\n"},
+		{TableExportJSON, TableExportOptions{FilteredRows: true}, "[\n  {\"Name\": \"al|pha\", \"Size\": \"5\"},\n  {\"Name\": \"alphabet\", \"Size\": \"8\"}\n]\n"},
+		{TableExportMarkdown, TableExportOptions{FilteredRows: true}, "| Name | Size |\n| --- | ---: |\n| al\\|pha | 5 |\n| alphabet | 8 |\n"},
+	}
+	for _, c := range testCases {
+		var b strings.Builder
+		if err := table.Export(&b, c.format, c.options); err != nil {
+			t.Errorf("failed to export table in format %d: %s", c.format, err)
+		} else if b.String() != c.expected {
+			t.Errorf("failed to export table in format %d: expected %q, got %q", c.format, c.expected, b.String())
+		}
+	}
+
+	// Hidden columns are not exported.
+	table.SetColumnHidden(1, true)
+	var b strings.Builder
+	if err := table.Export(&b, TableExportCSV, TableExportOptions{VisibleColumns: true}); err != nil || b.String() != "Name\nbeta\nal|pha\ngamma\nalphabet\n" {
+		t.Errorf("failed to export visible columns: got %q (%v)", b.String(), err)
+	}
+
+	// Loaded cells are escaped.
+	if err := table.LoadCSV(strings.NewReader("a,[b]\nc\n"), ','); err != nil {
+		t.Fatalf("failed to load CSV: %s", err)
+	}
+	if table.GetRowCount() != 2 || table.GetColumnCount() != 2 {
+		t.Errorf("failed to load CSV: expected 2x2 cells, got %dx%d", table.GetRowCount(), table.GetColumnCount())
+	}
+	b.Reset()
+	table.SetColumnHidden(1, false)
+	table.ClearFilters()
+	if err := table.Export(&b, TableExportCSV, TableExportOptions{}); err != nil || b.String() != "a,[b]\nc,\n" {
+		t.Errorf("failed to round-trip CSV: got %q (%v)", b.String(), err)
+	}
+
+	// Repeated keys are numbered.
+	if err := table.LoadCSV(strings.NewReader("Name,Name,,2\na,b,c,d\n"), ','); err != nil {
+		t.Fatalf("failed to load CSV: %s", err)
+	}
+	table.SetFixed(1, 0)
+	b.Reset()
+	expected := "[\n  {\"Name\": \"a\", \"Name_2\": \"b\", \"3\": \"c\", \"2\": \"d\"}\n]\n"
+	if err := table.Export(&b, TableExportJSON, TableExportOptions{}); err != nil || b.String() != expected {
+		t.Errorf("failed to number repeated keys: expected %q, got %q (%v)", expected, b.String(), err)
+	}
+}
@@ -0,0 +1,481 @@
+package crtview
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// TableColumnLayout is the arrangement of the columns of a Table, as changed
+// by the user. It may be saved, e.g. encoded as JSON, and restored with
+// SetColumnLayout. Columns are the columns of the content.
+type TableColumnLayout struct {
+	// The columns in the order they are drawn, including hidden columns.
+	Order []int `json:"order,omitempty"`
+
+	// The columns which are hidden.
+	Hidden []int `json:"hidden,omitempty"`
+
+	// The widths of the columns whose width was set, by column.
+	Widths map[int]int `json:"widths,omitempty"`
+}
+
+// Column drag modes.
+const (
+	tableDragNone = iota
+	tableDragResize
+	tableDragReorder
+)
+
+// SetColumnWidth sets the width of a column, overriding the width of its
+// widest cell. Longer texts are truncated. Provide 0 to size the column to
+// its cells again.
+func (t *Table) SetColumnWidth(column, width int) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.setColumnWidth(column, width)
+	return t
+}
+
+// GetColumnWidth returns the width set for a column, or 0 if the column is
+// sized to its cells.
+func (t *Table) GetColumnWidth(column int) int {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.columnWidths[column]
+}
+
+// SetColumnHidden sets whether or not a column is hidden.
+func (t *Table) SetColumnHidden(column int, hidden bool) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.setColumnHidden(column, hidden)
+	t.layoutColumns()
+	return t
+}
+
+// IsColumnHidden returns whether or not a column is hidden.
+func (t *Table) IsColumnHidden(column int) bool {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.hiddenColumns[column]
+}
+
+// SetColumnOrder sets the order in which the columns are drawn. Columns which
+// are not in the list are drawn after the listed ones, in their original
+// order. Provide nil to restore the original order.
+func (t *Table) SetColumnOrder(order []int) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.columnOrder = append([]int(nil), order...)
+	t.layoutColumns()
+	return t
+}
+
+// GetColumnOrder returns all columns, including hidden columns, in the order
+// they are drawn.
+func (t *Table) GetColumnOrder() []int {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.fullColumnOrder()
+}
+
+// MoveColumn moves a column to the given position of the order returned by
+// GetColumnOrder.
+func (t *Table) MoveColumn(column, position int) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.moveColumn(column, position)
+	return t
+}
+
+// GetColumnLayout returns the order, visibility and widths of the columns.
+func (t *Table) GetColumnLayout() TableColumnLayout {
+	t.RLock()
+	defer t.RUnlock()
+
+	var layout TableColumnLayout
+	if t.columnOrder != nil {
+		layout.Order = t.fullColumnOrder()
+	}
+	for column := range t.hiddenColumns {
+		layout.Hidden = append(layout.Hidden, column)
+	}
+	sort.Ints(layout.Hidden)
+	if len(t.columnWidths) > 0 {
+		layout.Widths = make(map[int]int, len(t.columnWidths))
+		for column, width := range t.columnWidths {
+			layout.Widths[column] = width
+		}
+	}
+	return layout
+}
+
+// SetColumnLayout restores the order, visibility and widths of the columns,
+// as returned by GetColumnLayout.
+func (t *Table) SetColumnLayout(layout TableColumnLayout) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.columnOrder = append([]int(nil), layout.Order...)
+	t.hiddenColumns, t.columnWidths = nil, nil
+	for _, column := range layout.Hidden {
+		t.setColumnHidden(column, true)
+	}
+	for column, width := range layout.Widths {
+		t.setColumnWidth(column, width)
+	}
+	t.layoutColumns()
+	return t
+}
+
+// SetColumnsResizable sets a flag which determines whether the user may
+// resize columns by dragging the separator or border right of a column with
+// the mouse, or by pressing the GrowColumn and ShrinkColumn keys. This flag
+// is enabled by default.
+func (t *Table) SetColumnsResizable(resizable bool) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.columnsResizable = resizable
+	return t
+}
+
+// SetColumnsReorderable sets a flag which determines whether the user may
+// reorder columns by dragging them by a fixed row with the mouse, or by
+// pressing the MoveColumnLeft and MoveColumnRight keys. Fixed columns are not
+// moved. This flag is enabled by default.
+func (t *Table) SetColumnsReorderable(reorderable bool) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.columnsReorderable = reorderable
+	return t
+}
+
+// setColumnWidth sets the width of a column, 0 for automatic.
+func (t *Table) setColumnWidth(column, width int) {
+	if width <= 0 {
+		delete(t.columnWidths, column)
+		return
+	}
+	if t.columnWidths == nil {
+		t.columnWidths = make(map[int]int)
+	}
+	t.columnWidths[column] = width
+}
+
+// setColumnHidden sets whether or not a column is hidden.
+func (t *Table) setColumnHidden(column int, hidden bool) {
+	if !hidden {
+		delete(t.hiddenColumns, column)
+		return
+	}
+	if t.hiddenColumns == nil {
+		t.hiddenColumns = make(map[int]bool)
+	}
+	t.hiddenColumns[column] = true
+}
+
+// fullColumnOrder returns all columns of the content, including hidden
+// columns, in the order they are drawn.
+func (t *Table) fullColumnOrder() []int {
+	count := t.content.GetColumnCount()
+	seen := make([]bool, count)
+	order := make([]int, 0, count)
+	for _, column := range t.columnOrder {
+		if column >= 0 && column < count && !seen[column] {
+			seen[column] = true
+			order = append(order, column)
+		}
+	}
+	for column := 0; column < count; column++ {
+		if !seen[column] {
+			order = append(order, column)
+		}
+	}
+	return order
+}
+
+// layoutColumns determines the columns which are drawn. The selection stays
+// on the same column of the content if that column is still drawn.
+func (t *Table) layoutColumns() {
+	selected := t.contentColumn(t.selectedColumn)
+	if t.columnOrder == nil && len(t.hiddenColumns) == 0 {
+		t.columnIndex = nil
+		if selected >= 0 {
+			t.selectedColumn = selected
+		}
+		return
+	}
+
+	order := t.fullColumnOrder()
+	columns := make([]int, 0, len(order))
+	for _, column := range order {
+		if !t.hiddenColumns[column] {
+			columns = append(columns, column)
+		}
+	}
+	t.columnIndex = columns
+
+	if column := t.displayColumn(selected); column >= 0 {
+		t.selectedColumn = column
+	} else if t.selectedColumn >= len(columns) {
+		t.selectedColumn = len(columns) - 1
+	}
+	if t.selectedColumn < 0 {
+		t.selectedColumn = 0
+	}
+}
+
+// moveColumn moves a column of the content to the given position of the full
+// column order.
+func (t *Table) moveColumn(column, position int) {
+	order := t.fullColumnOrder()
+	from := -1
+	for index, c := range order {
+		if c == column {
+			from = index
+		}
+	}
+	if from < 0 || position < 0 || position >= len(order) || position == from {
+		return
+	}
+	order = append(order[:from], order[from+1:]...)
+	order = append(order[:position], append([]int{column}, order[position:]...)...)
+	t.columnOrder = order
+	t.layoutColumns()
+}
+
+// moveDisplayColumn moves the column drawn at index from to the position of
+// the column drawn at index to. Fixed columns are not moved.
+func (t *Table) moveDisplayColumn(from, to int) bool {
+	if from < t.fixedColumns || to < t.fixedColumns || from == to {
+		return false
+	}
+	column, target := t.contentColumn(from), t.contentColumn(to)
+	if column < 0 || target < 0 {
+		return false
+	}
+	for position, c := range t.fullColumnOrder() {
+		if c == target {
+			t.moveColumn(column, position)
+			return true
+		}
+	}
+	return false
+}
+
+// columnCount returns the number of columns as drawn.
+func (t *Table) columnCount() int {
+	if t.columnIndex == nil {
+		return t.content.GetColumnCount()
+	}
+	return len(t.columnIndex)
+}
+
+// contentColumn returns the column of the content drawn at the given column,
+// or -1 if there is none.
+func (t *Table) contentColumn(column int) int {
+	if t.columnIndex == nil {
+		return column
+	}
+	if column < 0 || column >= len(t.columnIndex) {
+		return -1
+	}
+	return t.columnIndex[column]
+}
+
+// displayColumn returns the column at which the given column of the content
+// is drawn, or -1 if it is hidden.
+func (t *Table) displayColumn(column int) int {
+	if t.columnIndex == nil || column < 0 {
+		return column
+	}
+	for index, contentColumn := range t.columnIndex {
+		if contentColumn == column {
+			return index
+		}
+	}
+	return -1
+}
+
+// currentColumn returns the column (as drawn) the keyboard shortcuts which
+// change the column layout apply to: the selected column if columns are
+// selectable, otherwise the leftmost column which is not fixed.
+func (t *Table) currentColumn() int {
+	if t.columnsSelectable {
+		return t.selectedColumn
+	}
+	if len(t.visibleColumnIndices) > t.fixedColumns {
+		return t.visibleColumnIndices[t.fixedColumns]
+	}
+	return t.fixedColumns
+}
+
+// resizeColumn changes the width of the column drawn at the given index by
+// the given amount.
+func (t *Table) resizeColumn(column, delta int) {
+	contentColumn := t.contentColumn(column)
+	if contentColumn < 0 {
+		return
+	}
+	width, ok := t.columnWidths[contentColumn]
+	if !ok {
+		for index, visible := range t.visibleColumnIndices {
+			if visible == column {
+				width = t.visibleColumnWidths[index]
+			}
+		}
+	}
+	if width += delta; width < 1 {
+		width = 1
+	}
+	t.setColumnWidth(contentColumn, width)
+}
+
+// columnTitle returns the title of a column of the content in the column
+// menu: the text of its cell in the last fixed row, if any.
+func (t *Table) columnTitle(column int) string {
+	if t.fixedRows > 0 {
+		if title := strings.TrimSpace(string(t.plainText(t.fixedRows-1, column))); title != "" {
+			return title
+		}
+	}
+	return fmt.Sprintf("Column %d", column+1)
+}
+
+// showColumnMenu shows a menu in which the user hides and shows columns at
+// the given screen position, or at the top left corner of the table if the
+// position is negative.
+func (t *Table) showColumnMenu(x, y int, setFocus func(p Primitive)) {
+	t.Lock()
+	order := t.fullColumnOrder()
+	titles := make([]string, len(order))
+	hidden := make([]bool, len(order))
+	for index, column := range order {
+		titles[index] = Escape(t.columnTitle(column))
+		hidden[index] = t.hiddenColumns[column]
+	}
+	t.Unlock()
+	if len(order) == 0 {
+		return
+	}
+
+	menu := t.columnMenu
+	menu.ClearContextMenu()
+	for index, column := range order {
+		column := column
+		menu.AddContextCheckItem(titles[index], 0, !hidden[index], func(int) {
+			t.Lock()
+			defer t.Unlock()
+
+			if !t.hiddenColumns[column] && t.columnCount() <= 1 {
+				return // Keep the last column.
+			}
+			t.setColumnHidden(column, !t.hiddenColumns[column])
+			t.layoutColumns()
+		})
+	}
+	menu.AddContextSeparator()
+	menu.AddContextItem("Show all columns", 0, func(int) {
+		t.Lock()
+		defer t.Unlock()
+
+		t.hiddenColumns = nil
+		t.layoutColumns()
+	})
+	menu.ShowContextMenu(0, x, y, setFocus)
+}
+
+// drawColumnMenu draws the column menu if it is open.
+func (t *Table) drawColumnMenu(screen tcell.Screen) {
+	if !t.columnMenu.ContextMenuVisible() {
+		return
+	}
+	t.columnMenu.l.RLock()
+	x, y := t.columnMenu.x, t.columnMenu.y
+	t.columnMenu.l.RUnlock()
+	if x < 0 || y < 0 {
+		x, y, _, _ = t.GetInnerRect()
+	}
+	t.columnMenu.draw(screen, x, y, -1)
+}
+
+// separatorAt returns the index of the visible column whose right separator
+// (or border) is at the given screen column, or -1 if there is none, and the
+// screen column at which the text of that column starts.
+func (t *Table) separatorAt(x int) (index, textX int) {
+	rectX, _, _, _ := t.GetInnerRect()
+	columnX := rectX - 1
+	if t.borders {
+		columnX = rectX
+	}
+	for index, width := range t.visibleColumnWidths {
+		textX = columnX + 1
+		columnX += width + 1
+		if x == columnX {
+			return index, textX
+		}
+	}
+	return -1, 0
+}
+
+// handleColumnDrag handles mouse events which resize and reorder columns. It
+// returns whether the event was handled.
+func (t *Table) handleColumnDrag(action MouseAction, x, y int) bool {
+	t.Lock()
+	defer t.Unlock()
+
+	switch action {
+	case MouseLeftDown:
+		if !t.InRect(x, y) {
+			return false
+		}
+		if index, textX := t.separatorAt(x); index >= 0 && t.columnsResizable {
+			t.dragMode, t.dragColumn, t.dragX = tableDragResize, t.visibleColumnIndices[index], textX
+			return true
+		}
+		_, rectY, _, _ := t.GetInnerRect()
+		headerHeight := t.fixedRows
+		if t.borders {
+			headerHeight = 2*t.fixedRows + 1
+		}
+		if row, column := t.cellAt(x, y); t.columnsReorderable && row >= 0 && row < t.fixedRows && y < rectY+headerHeight && column >= t.fixedColumns {
+			t.dragMode, t.dragColumn = tableDragReorder, column
+			return true
+		}
+	case MouseMove:
+		switch t.dragMode {
+		case tableDragResize:
+			width := x - t.dragX
+			if width < 1 {
+				width = 1
+			}
+			if column := t.contentColumn(t.dragColumn); column >= 0 {
+				t.setColumnWidth(column, width)
+			}
+			return true
+		case tableDragReorder:
+			if _, column := t.cellAt(x, y); column >= 0 && t.moveDisplayColumn(t.dragColumn, column) {
+				t.dragColumn = column
+			}
+			return true
+		}
+	case MouseLeftUp:
+		if t.dragMode != tableDragNone {
+			t.dragMode = tableDragNone
+			return true
+		}
+	}
+	return false
+}
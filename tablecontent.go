@@ -0,0 +1,193 @@
+package crtview
+
+import "sync"
+
+// TableContent provides the cells of a Table. Implement it to back a table by
+// a data source which is too large to be held in memory, such as the result of
+// a database query. The table only requests the cells it draws, unless
+// SetEvaluateAllRows is enabled.
+//
+// The methods are called with the table locked and must not call methods of
+// the table.
+type TableContent interface {
+	// GetCell returns the cell at the given position, or nil if there is no
+	// cell at that position.
+	GetCell(row, column int) *TableCell
+
+	// GetRowCount returns the number of rows.
+	GetRowCount() int
+
+	// GetColumnCount returns the (maximum) number of columns.
+	GetColumnCount() int
+}
+
+// EditableTableContent is a TableContent which may be modified through the
+// methods of Table, such as SetCell and InsertRow. The methods of Table which
+// modify a read-only TableContent have no effect.
+type EditableTableContent interface {
+	TableContent
+
+	// SetCell sets the cell at the given position, extending the content as
+	// necessary.
+	SetCell(row, column int, cell *TableCell)
+
+	// RemoveRow removes the row at the given position.
+	RemoveRow(row int)
+
+	// RemoveColumn removes the column at the given position.
+	RemoveColumn(column int)
+
+	// InsertRow inserts an empty row before the row at the given position.
+	InsertRow(row int)
+
+	// InsertColumn inserts an empty column before the column at the given
+	// position.
+	InsertColumn(column int)
+
+	// Clear removes all cells.
+	Clear()
+}
+
+// tableContentData is the default TableContent, which keeps all cells in
+// memory.
+type tableContentData struct {
+	// The cells of the table. Rows first, then columns.
+	cells [][]*TableCell
+
+	// The rightmost column in the data set.
+	lastColumn int
+
+	sync.RWMutex
+}
+
+// NewTableContent returns a new, empty TableContent which keeps all cells in
+// memory. It is the content of new tables.
+func NewTableContent() EditableTableContent {
+	return &tableContentData{lastColumn: -1}
+}
+
+// GetCell returns the cell at the given position, or nil if there is none.
+func (d *tableContentData) GetCell(row, column int) *TableCell {
+	d.RLock()
+	defer d.RUnlock()
+
+	if row < 0 || column < 0 || row >= len(d.cells) || column >= len(d.cells[row]) {
+		return nil
+	}
+	return d.cells[row][column]
+}
+
+// GetRowCount returns the number of rows.
+func (d *tableContentData) GetRowCount() int {
+	d.RLock()
+	defer d.RUnlock()
+
+	return len(d.cells)
+}
+
+// GetColumnCount returns the (maximum) number of columns.
+func (d *tableContentData) GetColumnCount() int {
+	d.RLock()
+	defer d.RUnlock()
+
+	if len(d.cells) == 0 {
+		return 0
+	}
+	return d.lastColumn + 1
+}
+
+// SetCell sets the cell at the given position. Setting cells in previously
+// unknown rows and columns extends the content.
+func (d *tableContentData) SetCell(row, column int, cell *TableCell) {
+	d.Lock()
+	defer d.Unlock()
+
+	if row >= len(d.cells) {
+		d.cells = append(d.cells, make([][]*TableCell, row-len(d.cells)+1)...)
+	}
+	rowLen := len(d.cells[row])
+	if column >= rowLen {
+		d.cells[row] = append(d.cells[row], make([]*TableCell, column-rowLen+1)...)
+		for c := rowLen; c < column; c++ {
+			d.cells[row][c] = &TableCell{}
+		}
+	}
+	d.cells[row][column] = cell
+	if column > d.lastColumn {
+		d.lastColumn = column
+	}
+}
+
+// RemoveRow removes the row at the given position.
+func (d *tableContentData) RemoveRow(row int) {
+	d.Lock()
+	defer d.Unlock()
+
+	if row < 0 || row >= len(d.cells) {
+		return
+	}
+	d.cells = append(d.cells[:row], d.cells[row+1:]...)
+}
+
+// RemoveColumn removes the column at the given position.
+func (d *tableContentData) RemoveColumn(column int) {
+	d.Lock()
+	defer d.Unlock()
+
+	for row := range d.cells {
+		if column < 0 || column >= len(d.cells[row]) {
+			continue
+		}
+		d.cells[row] = append(d.cells[row][:column], d.cells[row][column+1:]...)
+	}
+}
+
+// InsertRow inserts an empty row before the row at the given position.
+func (d *tableContentData) InsertRow(row int) {
+	d.Lock()
+	defer d.Unlock()
+
+	if row >= len(d.cells) {
+		return
+	}
+	d.cells = append(d.cells, nil)       // Extend by one.
+	copy(d.cells[row+1:], d.cells[row:]) // Shift down.
+	d.cells[row] = nil                   // New row is uninitialized.
+}
+
+// InsertColumn inserts an empty column before the column at the given
+// position.
+func (d *tableContentData) InsertColumn(column int) {
+	d.Lock()
+	defer d.Unlock()
+
+	for row := range d.cells {
+		if column >= len(d.cells[row]) {
+			continue
+		}
+		d.cells[row] = append(d.cells[row], nil)             // Extend by one.
+		copy(d.cells[row][column+1:], d.cells[row][column:]) // Shift to the right.
+		d.cells[row][column] = &TableCell{}                  // New element is an uninitialized table cell.
+	}
+}
+
+// Clear removes all cells.
+func (d *tableContentData) Clear() {
+	d.Lock()
+	defer d.Unlock()
+
+	d.cells = nil
+	d.lastColumn = -1
+}
+
+// reorderRows reorders the rows so that row order[i] becomes row i.
+func (d *tableContentData) reorderRows(order []int) {
+	d.Lock()
+	defer d.Unlock()
+
+	cells := make([][]*TableCell, len(d.cells))
+	for i, row := range order {
+		cells[i] = d.cells[row]
+	}
+	d.cells = cells
+}
@@ -0,0 +1,234 @@
+package crtview
+
+import (
+	"strconv"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// SetCellsEditable sets a flag which determines whether the user may edit the
+// cells of the table in place. Cells must be selectable individually (see
+// SetSelectable). Pressing Enter or F2, or double-clicking a cell, opens an
+// editor over the selected cell. Enter and Tab accept the new text, Escape
+// cancels the edit. Cells of fixed rows and columns, cells which are not
+// selectable and cells of a content which is not an EditableTableContent (see
+// SetContent) are not edited. The text is edited without color tags and
+// stored escaped (see Escape).
+//
+// While editing is enabled, Enter no longer invokes the handler set with
+// SetSelectedFunc().
+func (t *Table) SetCellsEditable(editable bool) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.cellsEditable = editable
+	return t
+}
+
+// SetColumnEditor sets the form item the cells of a column are edited in,
+// which is one of:
+//
+//   - *InputField: The text of the cell is edited. This is the default.
+//   - *DropDown: One of the options is selected. The option whose text equals
+//     the text of the cell is selected initially. Selecting an option accepts
+//     it.
+//   - *CheckBox: The cell is "true" or "false". Toggling the check box accepts
+//     the new value.
+//
+// The table replaces the finished function of the editor, the selected
+// function of a DropDown and the changed function of a CheckBox. Provide nil
+// to edit the column in an InputField again.
+func (t *Table) SetColumnEditor(column int, editor FormItem) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	switch editor.(type) {
+	case *InputField, *DropDown, *CheckBox:
+		if t.columnEditors == nil {
+			t.columnEditors = make(map[int]FormItem)
+		}
+		t.columnEditors[column] = editor
+	default:
+		delete(t.columnEditors, column)
+	}
+	return t
+}
+
+// SetCellValidateFunc sets a handler which is called when the user accepts
+// the new text of a cell. It receives the row and column of the cell and the
+// new text. If it returns false, the value is rejected and the editor stays
+// open.
+func (t *Table) SetCellValidateFunc(handler func(row, column int, text string) bool) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.cellValidate = handler
+	return t
+}
+
+// SetCellEditedFunc sets a handler which is called after the user changed the
+// text of a cell. It receives the row and column of the cell, its previous
+// text and its new text, both without color tags. The handler is called after
+// the new text was stored in the cell.
+func (t *Table) SetCellEditedFunc(handler func(row, column int, oldText, newText string)) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.cellEdited = handler
+	return t
+}
+
+// IsEditing returns whether the user is editing a cell.
+func (t *Table) IsEditing() bool {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.editor != nil
+}
+
+// startEdit opens the editor over the selected cell and returns it, or nil if
+// the cell may not be edited.
+func (t *Table) startEdit(setFocus func(p Primitive)) FormItem {
+	if !t.cellsEditable || !t.rowsSelectable || !t.columnsSelectable || t.editor != nil ||
+		t.selectedRow < t.displayFixedRows() || t.selectedRow >= t.rowCount() ||
+		t.selectedColumn < t.fixedColumns || t.selectedColumn >= t.columnCount() {
+		return nil
+	}
+	row, column := t.contentRow(t.selectedRow), t.contentColumn(t.selectedColumn)
+	content := t.editable()
+	if row < 0 || column < 0 || content == nil {
+		return nil
+	}
+	var text string
+	if cell := content.GetCell(row, column); cell != nil {
+		if cell.NotSelectable {
+			return nil
+		}
+		text = string(StripTags(cell.GetBytes(), true, true))
+	}
+
+	editor := t.columnEditors[column]
+	if editor == nil {
+		if t.inputEditor == nil {
+			t.inputEditor = NewInputField()
+		}
+		editor = t.inputEditor
+	}
+	editor.SetLabelWidth(0)
+	finished := func(key tcell.Key) {
+		t.finishEdit(key != tcell.KeyEscape, setFocus)
+	}
+	editor.SetFinishedFunc(finished)
+	switch editor := editor.(type) {
+	case *InputField:
+		editor.SetText(text)
+	case *DropDown:
+		current := -1
+		editor.RLock()
+		for index, option := range editor.options {
+			if option.text == text {
+				current = index
+				break
+			}
+		}
+		editor.RUnlock()
+		editor.SetSelectedFunc(nil)
+		editor.SetCurrentOption(current)
+		editor.SetSelectedFunc(func(int, *DropDownOption) {
+			finished(tcell.KeyEnter)
+		})
+	case *CheckBox:
+		checked, _ := strconv.ParseBool(text)
+		editor.SetChecked(checked)
+		editor.SetChangedFunc(func(bool) {
+			finished(tcell.KeyEnter)
+		})
+	}
+
+	t.editor, t.editRow, t.editColumn, t.editText = editor, row, column, text
+	return editor
+}
+
+// editorText returns the text of the cell being edited, as shown in the
+// editor.
+func (t *Table) editorText() string {
+	switch editor := t.editor.(type) {
+	case *InputField:
+		return editor.GetText()
+	case *DropDown:
+		if _, option := editor.GetCurrentOption(); option != nil {
+			return option.GetText()
+		}
+	case *CheckBox:
+		return strconv.FormatBool(editor.IsChecked())
+	}
+	return t.editText
+}
+
+// finishEdit closes the editor. If accept is true, the text of the editor is
+// validated and stored in the cell first, and the editor stays open if it is
+// rejected. The focus is returned to the table. The table must not be locked.
+func (t *Table) finishEdit(accept bool, setFocus func(p Primitive)) {
+	t.Lock()
+	editor := t.editor
+	if editor == nil {
+		t.Unlock()
+		return
+	}
+	row, column, oldText, newText := t.editRow, t.editColumn, t.editText, t.editorText()
+
+	if accept && t.cellValidate != nil {
+		validate := t.cellValidate
+		t.Unlock()
+		valid := validate(row, column, newText)
+		t.Lock()
+		if !valid {
+			t.Unlock()
+			return
+		}
+	}
+	t.editor = nil
+	var stored bool
+	if content := t.editable(); accept && newText != oldText && content != nil {
+		if cell := content.GetCell(row, column); cell != nil {
+			cell.SetText(Escape(newText))
+		} else {
+			content.SetCell(row, column, NewTableCell(Escape(newText)))
+		}
+		t.filterDirty = true
+		stored = true
+	}
+	edited := t.cellEdited
+	t.Unlock()
+
+	if setFocus != nil && editor.GetFocusable().HasFocus() {
+		setFocus(t)
+	}
+	if stored && edited != nil {
+		edited(row, column, oldText, newText)
+	}
+}
+
+// PasteHandler returns the handler for pasted text, which is the handler of
+// the editor of the cell being edited if it receives pasted text (see
+// Pastable). It returns nil while no cell is edited, so the pasted keys are
+// passed to the table one by one.
+func (t *Table) PasteHandler() func(text string, setFocus func(p Primitive)) {
+	t.RLock()
+	editor := t.editor
+	t.RUnlock()
+
+	if pastable, ok := editor.(Pastable); ok {
+		return pastable.PasteHandler()
+	}
+	return nil
+}
+
+// drawEditor draws the editor over the cell being edited, if it is visible.
+func (t *Table) drawEditor(screen tcell.Screen) {
+	if t.editor == nil || t.editWidth <= 0 {
+		return
+	}
+	t.editor.SetRect(t.editX, t.editY, t.editWidth, 1)
+	t.editor.Draw(screen)
+}
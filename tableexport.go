@@ -0,0 +1,269 @@
+package crtview
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// TableExportFormat is a format the contents of a table are exported in.
+type TableExportFormat int
+
+// Table export formats.
+const (
+	// Comma-separated values. All rows are exported, including fixed rows.
+	TableExportCSV TableExportFormat = iota
+
+	// Tab-separated values. All rows are exported, including fixed rows.
+	TableExportTSV
+
+	// A JSON array with an object per row below the fixed rows. The texts of
+	// the last fixed row are the keys of the objects, or the numbers of the
+	// columns (starting at 1) if there are no fixed rows or the text is
+	// empty. Repeated keys are numbered, e.g. "Name" and "Name_2".
+	TableExportJSON
+
+	// A GitHub Flavored Markdown table. The last fixed row is the header of
+	// the table, the rows below the fixed rows are its body.
+	TableExportMarkdown
+)
+
+// TableExportOptions are the options with which the contents of a table are
+// exported.
+type TableExportOptions struct {
+	// If true, only the rows which are not filtered out are exported.
+	// Otherwise all rows of the content are exported.
+	FilteredRows bool
+
+	// If true, only the columns which are not hidden are exported, in the
+	// order in which they are drawn. Otherwise all columns of the content are
+	// exported in their original order.
+	VisibleColumns bool
+}
+
+// Export writes the contents of the table to w in the given format. Color and
+// region tags are removed from the texts of the cells (see StripTags). Rows
+// are exported in their current order, i.e. sorted if the table was sorted.
+func (t *Table) Export(w io.Writer, format TableExportFormat, options TableExportOptions) error {
+	t.RLock()
+	rows, columns, header := t.exportCells(options)
+	t.RUnlock()
+
+	switch format {
+	case TableExportCSV, TableExportTSV:
+		writer := csv.NewWriter(w)
+		if format == TableExportTSV {
+			writer.Comma = '\t'
+		}
+		if err := writer.WriteAll(rows); err != nil {
+			return fmt.Errorf("failed to write table: %s", err)
+		}
+		return nil
+	case TableExportJSON:
+		return exportJSON(w, rows[header:], exportKeys(rows, header, true))
+	case TableExportMarkdown:
+		return exportMarkdown(w, rows[header:], exportKeys(rows, header, false), t.exportAlignments(columns))
+	}
+	return fmt.Errorf("unknown table export format %d", format)
+}
+
+// LoadCSV replaces the cells of the table with the records read from r, which
+// are separated by comma (e.g. ',' or '\t'). Records may have different
+// numbers of fields. Texts are escaped so that square brackets are not
+// interpreted as tags (see Escape). Tables whose content is not an
+// EditableTableContent are not changed.
+func (t *Table) LoadCSV(r io.Reader, comma rune) error {
+	reader := csv.NewReader(r)
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read table: %s", err)
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	content := t.editable()
+	if content == nil {
+		return nil
+	}
+	content.Clear()
+	for row, record := range records {
+		for column, text := range record {
+			content.SetCell(row, column, NewTableCell(Escape(text)))
+		}
+	}
+	t.selectedRows = nil
+	t.filterRows()
+	return nil
+}
+
+// exportCells returns the texts of the exported cells, the exported columns
+// of the content and the number of exported fixed rows.
+func (t *Table) exportCells(options TableExportOptions) (rows [][]string, columns []int, header int) {
+	if options.VisibleColumns {
+		for column := 0; column < t.columnCount(); column++ {
+			columns = append(columns, t.contentColumn(column))
+		}
+	} else {
+		for column := 0; column < t.content.GetColumnCount(); column++ {
+			columns = append(columns, column)
+		}
+	}
+
+	var contentRows []int
+	if options.FilteredRows {
+		for row := 0; row < t.rowCount(); row++ {
+			if contentRow := t.contentRow(row); contentRow >= 0 {
+				contentRows = append(contentRows, contentRow)
+			}
+		}
+	} else {
+		for row := 0; row < t.content.GetRowCount(); row++ {
+			contentRows = append(contentRows, row)
+		}
+	}
+
+	for _, row := range contentRows {
+		if row < t.fixedRows {
+			header++
+		}
+		texts := make([]string, len(columns))
+		for index, column := range columns {
+			texts[index] = string(t.plainText(row, column))
+		}
+		rows = append(rows, texts)
+	}
+	return rows, columns, header
+}
+
+// exportAlignments returns the alignments of the exported columns, which are
+// the alignments of the cells of the last fixed row.
+func (t *Table) exportAlignments(columns []int) []int {
+	t.RLock()
+	defer t.RUnlock()
+
+	alignments := make([]int, len(columns))
+	if t.fixedRows == 0 {
+		return alignments
+	}
+	for index, column := range columns {
+		if cell := t.content.GetCell(t.fixedRows-1, column); cell != nil {
+			alignments[index] = cell.Align
+		}
+	}
+	return alignments
+}
+
+// exportKeys returns the texts of the last of the given number of fixed rows,
+// as keys of the columns. If unique is true, empty keys are replaced with the
+// numbers of the columns and repeated keys are numbered, so that no two
+// columns have the same key.
+func exportKeys(rows [][]string, header int, unique bool) []string {
+	var keys []string
+	if header > 0 {
+		keys = append(keys, rows[header-1]...)
+	} else if len(rows) > 0 {
+		keys = make([]string, len(rows[0]))
+	}
+	if !unique {
+		return keys
+	}
+	used := make(map[string]bool)
+	for index, base := range keys {
+		if base == "" {
+			base = strconv.Itoa(index + 1)
+		}
+		key := base
+		for number := 2; used[key]; number++ {
+			key = fmt.Sprintf("%s_%d", base, number)
+		}
+		keys[index] = key
+		used[key] = true
+	}
+	return keys
+}
+
+// exportJSON writes rows as a JSON array of objects with the given keys, in
+// the order of the columns.
+func exportJSON(w io.Writer, rows [][]string, keys []string) error {
+	buffer := bufio.NewWriter(w)
+	buffer.WriteString("[")
+	for index, row := range rows {
+		if index > 0 {
+			buffer.WriteString(",")
+		}
+		buffer.WriteString("\n  {")
+		for column, text := range row {
+			key, err := json.Marshal(keys[column])
+			if err != nil {
+				return fmt.Errorf("failed to write table: %s", err)
+			}
+			value, err := json.Marshal(text)
+			if err != nil {
+				return fmt.Errorf("failed to write table: %s", err)
+			}
+			if column > 0 {
+				buffer.WriteString(", ")
+			}
+			buffer.Write(key)
+			buffer.WriteString(": ")
+			buffer.Write(value)
+		}
+		buffer.WriteString("}")
+	}
+	if len(rows) > 0 {
+		buffer.WriteString("\n")
+	}
+	buffer.WriteString("]\n")
+	if err := buffer.Flush(); err != nil {
+		return fmt.Errorf("failed to write table: %s", err)
+	}
+	return nil
+}
+
+// markdownEscaper escapes the texts of Markdown table cells.
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+// exportMarkdown writes rows as a Markdown table with the given header and
+// column alignments.
+func exportMarkdown(w io.Writer, rows [][]string, header []string, alignments []int) error {
+	buffer := bufio.NewWriter(w)
+	writeRow := func(texts []string) {
+		buffer.WriteString("|")
+		for _, text := range texts {
+			buffer.WriteString(" ")
+			buffer.WriteString(markdownEscaper.Replace(text))
+			buffer.WriteString(" |")
+		}
+		buffer.WriteString("\n")
+	}
+
+	writeRow(header)
+	buffer.WriteString("|")
+	for _, align := range alignments {
+		switch align {
+		case AlignCenter:
+			buffer.WriteString(" :---: |")
+		case AlignRight:
+			buffer.WriteString(" ---: |")
+		default:
+			buffer.WriteString(" --- |")
+		}
+	}
+	buffer.WriteString("\n")
+	for _, row := range rows {
+		writeRow(row)
+	}
+
+	if err := buffer.Flush(); err != nil {
+		return fmt.Errorf("failed to write table: %s", err)
+	}
+	return nil
+}
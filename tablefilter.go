@@ -0,0 +1,505 @@
+package crtview
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// SetColumnFilter sets a function which decides whether a row is shown,
+// based on its cell in the given column. Rows are hidden unless the filters of
+// all columns accept them. The function receives an uninitialized cell if the
+// row has no cell in the column. Fixed rows are never filtered. Provide nil to
+// remove the filter of the column.
+//
+// Rows are filtered again when the filters change or cells are set, inserted
+// or removed through the table. Call Refilter() after changing the content
+// otherwise, e.g. the text of a cell or the rows of a TableContent.
+func (t *Table) SetColumnFilter(column int, filter func(cell *TableCell) bool) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	if filter == nil {
+		delete(t.filters, column)
+	} else {
+		if t.filters == nil {
+			t.filters = make(map[int]func(cell *TableCell) bool)
+		}
+		t.filters[column] = filter
+	}
+	t.filterRows()
+	return t
+}
+
+// Refilter filters the rows of the content again. Rows are filtered when the
+// filters or the cells of the table change, or when the number of rows of the
+// content changes. Call this function when the content was changed otherwise.
+func (t *Table) Refilter() *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.filterRows()
+	return t
+}
+
+// SetColumnFilterText sets the text rows are filtered by in the given column.
+// Only rows whose cell in the column contains the text, ignoring case and
+// color tags, are shown. This is the text edited in the filter row (see
+// SetFilterRow). It applies in addition to a filter set with SetColumnFilter.
+// Provide an empty string to remove the text filter of the column.
+func (t *Table) SetColumnFilterText(column int, text string) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.setFilterText(column, text)
+	t.filterRows()
+	return t
+}
+
+// GetColumnFilterText returns the text rows are filtered by in the given
+// column.
+func (t *Table) GetColumnFilterText(column int) string {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.filterTexts[column]
+}
+
+// ClearFilters removes the filters and filter texts of all columns.
+func (t *Table) ClearFilters() *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.filters, t.filterTexts = nil, nil
+	t.filterRows()
+	return t
+}
+
+// SetFilterRow sets a flag which determines whether a row holding the filter
+// texts of the columns is shown below the fixed rows. The filter row does not
+// scroll and cannot be selected. Press the Filter key or click a column's
+// filter to edit it. While editing, Left, Right, Tab and Backtab switch
+// columns, Enter and Escape finish editing.
+func (t *Table) SetFilterRow(show bool) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.filterRow = show
+	if !show {
+		t.filterEditing = false
+	}
+	t.filterRows()
+	return t
+}
+
+// SetFilterRowColor sets the color of the text in the filter row.
+func (t *Table) SetFilterRowColor(color tcell.Color) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.filterColor = color
+	return t
+}
+
+// GetFilteredRowCount returns the number of rows which are shown, including
+// fixed rows but not the filter row.
+func (t *Table) GetFilteredRowCount() int {
+	t.RLock()
+	defer t.RUnlock()
+
+	if t.rowIndex == nil {
+		return t.content.GetRowCount()
+	}
+	count := len(t.rowIndex)
+	if t.filterRow {
+		count--
+	}
+	return count
+}
+
+// SetSearchHighlightColor sets the background color of cells which match the
+// search query.
+func (t *Table) SetSearchHighlightColor(color tcell.Color) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.searchColor = color
+	return t
+}
+
+// SetSearch sets the search query and selects the first matching cell at or
+// after the current selection. Matching cells contain the query, ignoring case
+// and color tags, and are highlighted. Provide an empty string to end the
+// search.
+func (t *Table) SetSearch(query string) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.searching = false
+	t.searchQuery = query
+	if query != "" {
+		t.searchSelect(t.selectedRow, t.selectedColumn, 1, false)
+	}
+	return t
+}
+
+// GetSearch returns the current search query.
+func (t *Table) GetSearch() string {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.searchQuery
+}
+
+// SearchNext selects the next cell which matches the search query, wrapping
+// around at the end of the table. It returns false if no cell matches.
+func (t *Table) SearchNext() bool {
+	t.Lock()
+	defer t.Unlock()
+
+	return t.searchSelect(t.selectedRow, t.selectedColumn, 1, true)
+}
+
+// SearchPrevious selects the previous cell which matches the search query,
+// wrapping around at the beginning of the table. It returns false if no cell
+// matches.
+func (t *Table) SearchPrevious() bool {
+	t.Lock()
+	defer t.Unlock()
+
+	return t.searchSelect(t.selectedRow, t.selectedColumn, -1, true)
+}
+
+// setFilterText sets the filter text of a column.
+func (t *Table) setFilterText(column int, text string) {
+	if text == "" {
+		delete(t.filterTexts, column)
+		return
+	}
+	if t.filterTexts == nil {
+		t.filterTexts = make(map[int]string)
+	}
+	t.filterTexts[column] = text
+}
+
+// filterRows determines the rows which are shown. The selection stays on the
+// same row of the content if that row is still shown.
+func (t *Table) filterRows() {
+	selected := t.contentRow(t.selectedRow)
+	t.filterDirty = false
+	t.filteredRows = t.content.GetRowCount()
+	if !t.filterRow && len(t.filters) == 0 && len(t.filterTexts) == 0 {
+		t.rowIndex, t.rowPositions = nil, nil
+		if selected >= 0 {
+			t.selectedRow = selected
+		}
+		return
+	}
+
+	texts := make(map[int]string, len(t.filterTexts))
+	for column, text := range t.filterTexts {
+		texts[column] = strings.ToLower(text)
+	}
+	accept := func(row int) bool {
+		for column, filter := range t.filters {
+			cell := t.content.GetCell(row, column)
+			if cell == nil {
+				cell = &TableCell{}
+			}
+			if !filter(cell) {
+				return false
+			}
+		}
+		for column, text := range texts {
+			if !strings.Contains(strings.ToLower(string(t.plainText(row, column))), text) {
+				return false
+			}
+		}
+		return true
+	}
+
+	rowCount := t.filteredRows
+	fixedRows := t.fixedRows
+	if fixedRows > rowCount {
+		fixedRows = rowCount
+	}
+	rows := make([]int, 0, rowCount+1)
+	for row := 0; row < fixedRows; row++ {
+		rows = append(rows, row)
+	}
+	if t.filterRow {
+		rows = append(rows, -1)
+	}
+	for row := fixedRows; row < rowCount; row++ {
+		if accept(row) {
+			rows = append(rows, row)
+		}
+	}
+	t.rowIndex = rows
+	t.rowPositions = make(map[int]int, len(rows))
+	for index, row := range rows {
+		if row >= 0 {
+			t.rowPositions[row] = index
+		}
+	}
+
+	if row := t.displayRow(selected); row >= 0 {
+		t.selectedRow = row
+	} else if t.selectedRow >= len(rows) {
+		t.selectedRow = len(rows) - 1
+	}
+	if t.selectedRow < 0 {
+		t.selectedRow = 0
+	}
+}
+
+// plainText returns the text of a cell without color and region tags.
+func (t *Table) plainText(row, column int) []byte {
+	cell := t.content.GetCell(row, column)
+	if cell == nil {
+		return nil
+	}
+	return StripTags(cell.Text, true, true)
+}
+
+// rowCount returns the number of rows as drawn, including the filter row.
+func (t *Table) rowCount() int {
+	if t.rowIndex == nil {
+		return t.content.GetRowCount()
+	}
+	return len(t.rowIndex)
+}
+
+// displayFixedRows returns the number of fixed rows as drawn, including the
+// filter row.
+func (t *Table) displayFixedRows() int {
+	if t.filterRow {
+		return t.fixedRows + 1
+	}
+	return t.fixedRows
+}
+
+// contentRow returns the row of the content drawn at the given row, or -1 for
+// the filter row and rows which are not drawn.
+func (t *Table) contentRow(row int) int {
+	if t.rowIndex == nil {
+		return row
+	}
+	if row < 0 || row >= len(t.rowIndex) {
+		return -1
+	}
+	return t.rowIndex[row]
+}
+
+// displayRow returns the row at which the given row of the content is drawn,
+// or -1 if it is filtered out.
+func (t *Table) displayRow(row int) int {
+	if t.rowIndex == nil || row < 0 {
+		return row
+	}
+	if index, ok := t.rowPositions[row]; ok {
+		return index
+	}
+	return -1
+}
+
+// displayCell returns the cell drawn at the given position, or nil if there is
+// none.
+func (t *Table) displayCell(row, column int) *TableCell {
+	column = t.contentColumn(column)
+	if column < 0 {
+		return nil
+	}
+	contentRow := t.contentRow(row)
+	if contentRow < 0 {
+		if t.rowIndex == nil || row < 0 || row >= len(t.rowIndex) || column >= t.content.GetColumnCount() {
+			return nil
+		}
+		return t.filterCell(column)
+	}
+	return t.content.GetCell(contentRow, column)
+}
+
+// filterCell returns the cell of the filter row in the given column.
+func (t *Table) filterCell(column int) *TableCell {
+	text := Escape(t.filterTexts[column])
+	attributes := tcell.AttrUnderline
+	if t.filterEditing && t.contentColumn(t.filterColumn) == column {
+		text += "_"
+		attributes |= tcell.AttrBold
+	}
+	return &TableCell{
+		Text:            []byte(text),
+		Color:           t.filterColor,
+		BackgroundColor: tcell.ColorDefault,
+		Attributes:      attributes,
+		NotSelectable:   true,
+	}
+}
+
+// searchMatch returns whether the cell contains the search query.
+func (t *Table) searchMatch(cell *TableCell) bool {
+	if t.searchQuery == "" || cell == nil || cell.NotSelectable {
+		return false
+	}
+	return bytes.Contains(bytes.ToLower(StripTags(cell.Text, true, true)), []byte(strings.ToLower(t.searchQuery)))
+}
+
+// searchSelect selects the first cell matching the search query, starting at
+// the given position and moving forward (step 1) or backward (step -1). The
+// position itself is skipped if skip is true. If only rows are selectable,
+// entire rows are searched. The table is scrolled to the match if nothing is
+// selectable. It returns whether a match was found.
+func (t *Table) searchSelect(row, column, step int, skip bool) bool {
+	if t.searchQuery == "" {
+		return false
+	}
+	fixedRows, rowCount := t.displayFixedRows(), t.rowCount()
+	lastColumn := t.columnCount() - 1
+	rows, columns := rowCount-fixedRows, lastColumn-t.fixedColumns+1
+	if rows <= 0 || columns <= 0 {
+		return false
+	}
+
+	rowwise := !t.columnsSelectable
+	units := columns
+	if rowwise {
+		units = 1
+	}
+	if row < fixedRows {
+		row = fixedRows
+	} else if row >= rowCount {
+		row = rowCount - 1
+	}
+	unit := 0
+	if !rowwise && column >= t.fixedColumns {
+		unit = column - t.fixedColumns
+		if unit >= units {
+			unit = units - 1
+		}
+	}
+
+	total := rows * units
+	position := (row-fixedRows)*units + unit
+	start := 0
+	if skip {
+		start = 1
+	}
+	for i := start; i <= total; i++ {
+		p := ((position+i*step)%total + total) % total
+		r, c := fixedRows+p/units, t.fixedColumns+p%units
+		if rowwise {
+			c = -1
+			for candidate := t.fixedColumns; candidate <= lastColumn; candidate++ {
+				if t.searchMatch(t.displayCell(r, candidate)) {
+					c = candidate
+					break
+				}
+			}
+			if c < 0 {
+				continue
+			}
+		} else if !t.searchMatch(t.displayCell(r, c)) {
+			continue
+		}
+
+		if t.rowsSelectable {
+			t.selectedRow = r
+		}
+		if t.columnsSelectable {
+			t.selectedColumn = c
+		}
+		if !t.rowsSelectable {
+			// Scroll the match into view.
+			visible := t.visibleRows - fixedRows
+			if r-fixedRows < t.rowOffset || r-fixedRows >= t.rowOffset+visible {
+				t.rowOffset = r - fixedRows
+				t.trackEnd = false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// handleFilterKey handles a key while the filter row is edited.
+func (t *Table) handleFilterKey(event *tcell.EventKey) {
+	lastColumn := t.columnCount() - 1
+	column := t.contentColumn(t.filterColumn)
+	if column < 0 {
+		t.filterEditing = false
+		return
+	}
+	text := t.filterTexts[column]
+	switch event.Key() {
+	case tcell.KeyEnter, tcell.KeyEscape:
+		t.filterEditing = false
+	case tcell.KeyLeft, tcell.KeyBacktab:
+		if t.filterColumn > 0 {
+			t.filterColumn--
+		}
+	case tcell.KeyRight, tcell.KeyTab:
+		if t.filterColumn < lastColumn {
+			t.filterColumn++
+		}
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if text != "" {
+			_, size := utf8.DecodeLastRuneInString(text)
+			t.setFilterText(column, text[:len(text)-size])
+			t.filterRows()
+		}
+	case tcell.KeyCtrlU:
+		t.setFilterText(column, "")
+		t.filterRows()
+	case tcell.KeyRune:
+		t.setFilterText(column, text+string(event.Rune()))
+		t.filterRows()
+	}
+}
+
+// handleSearchKey handles a key while the search query is typed.
+func (t *Table) handleSearchKey(event *tcell.EventKey) {
+	switch event.Key() {
+	case tcell.KeyEnter:
+		t.searching = false
+	case tcell.KeyEscape:
+		t.searching = false
+		t.searchQuery = ""
+		t.selectedRow, t.selectedColumn = t.searchRow, t.searchColumn
+		t.rowOffset = t.searchOffset
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if t.searchQuery == "" {
+			t.searching = false
+			return
+		}
+		_, size := utf8.DecodeLastRuneInString(t.searchQuery)
+		t.searchQuery = t.searchQuery[:len(t.searchQuery)-size]
+		t.selectedRow, t.selectedColumn = t.searchRow, t.searchColumn
+		t.searchSelect(t.searchRow, t.searchColumn, 1, false)
+	case tcell.KeyRune:
+		t.searchQuery += string(event.Rune())
+		t.searchSelect(t.searchRow, t.searchColumn, 1, false)
+	}
+}
+
+// drawSearch draws the search query being typed on the given line.
+func (t *Table) drawSearch(screen tcell.Screen, x, y, width int) {
+	if !t.searching || width <= 0 {
+		return
+	}
+	style := tcell.StyleDefault.Background(t.GetBackgroundColor()).Foreground(t.filterColor)
+	for i := 0; i < width; i++ {
+		screen.SetContent(x+i, y, ' ', nil, style)
+	}
+	PrintStyle(screen, EscapeBytes([]byte("/"+t.searchQuery)), x, y, width, AlignLeft, style)
+}
+
+// isFilterRow returns whether the given row as drawn is the filter row.
+func (t *Table) isFilterRow(row int) bool {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.filterRow && row >= 0 && t.contentRow(row) < 0
+}
@@ -0,0 +1,215 @@
+package crtview
+
+import (
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// SetMultiSelect sets a flag which determines whether more than one row may
+// be selected. Rows must be selectable (see SetSelectable). The user toggles
+// the current row with Space, selects ranges of rows with Shift and the arrow
+// keys or Shift-click, toggles rows with Ctrl-click and selects all rows with
+// Ctrl+A instead of moving to the first row. Selected rows are drawn in the
+// color set with SetSelectedRowsColor. Disabling multi-selection clears the
+// selected rows.
+func (t *Table) SetMultiSelect(multiSelect bool) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.multiSelect = multiSelect
+	if !multiSelect {
+		t.selectedRows = nil
+	}
+	return t
+}
+
+// SetSelectedRowsColor sets the text color of rows which are selected when
+// multi-selection is enabled.
+func (t *Table) SetSelectedRowsColor(color tcell.Color) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.selectedRowsColor = color
+	return t
+}
+
+// SetSelectedRowsChangedFunc sets a handler which is called whenever the user
+// changes the selected rows. The handler receives all selected rows in
+// ascending order.
+func (t *Table) SetSelectedRowsChangedFunc(handler func(rows []int)) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.selectedRowsChanged = handler
+	return t
+}
+
+// GetSelectedRows returns the rows which are selected when multi-selection is
+// enabled, in ascending order. Rows which are filtered out stay selected.
+func (t *Table) GetSelectedRows() []int {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.getSelectedRows()
+}
+
+// SetSelectedRows sets the rows which are selected when multi-selection is
+// enabled. Fixed rows are not selected.
+func (t *Table) SetSelectedRows(rows []int) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.selectedRows = nil
+	for _, row := range rows {
+		t.setRowSelected(row, true)
+	}
+	return t
+}
+
+// IsRowSelected returns whether a row is selected when multi-selection is
+// enabled.
+func (t *Table) IsRowSelected(row int) bool {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.selectedRows[row]
+}
+
+// getSelectedRows returns the selected rows in ascending order.
+func (t *Table) getSelectedRows() []int {
+	rows := make([]int, 0, len(t.selectedRows))
+	for row := range t.selectedRows {
+		rows = append(rows, row)
+	}
+	sort.Ints(rows)
+	return rows
+}
+
+// setRowSelected selects or deselects a row of the content.
+func (t *Table) setRowSelected(row int, selected bool) {
+	if !selected {
+		delete(t.selectedRows, row)
+		return
+	}
+	if row < t.fixedRows || row >= t.content.GetRowCount() {
+		return
+	}
+	if t.selectedRows == nil {
+		t.selectedRows = make(map[int]bool)
+	}
+	t.selectedRows[row] = true
+}
+
+// selectRowRange selects the rows as drawn between the selection anchor and
+// the current row, replacing the selected rows.
+func (t *Table) selectRowRange() {
+	from, to := t.displayRow(t.selectionAnchor), t.selectedRow
+	if from < 0 {
+		from = to
+	}
+	if from > to {
+		from, to = to, from
+	}
+	t.selectedRows = nil
+	for row := from; row <= to; row++ {
+		t.setRowSelected(t.contentRow(row), true)
+	}
+}
+
+// selectAllRows selects all rows which are drawn.
+func (t *Table) selectAllRows() {
+	for row := t.displayFixedRows(); row < t.rowCount(); row++ {
+		t.setRowSelected(t.contentRow(row), true)
+	}
+}
+
+// toggleRow toggles the selection of the current row and makes it the anchor
+// of ranges.
+func (t *Table) toggleRow() {
+	row := t.contentRow(t.selectedRow)
+	t.setRowSelected(row, !t.selectedRows[row])
+	t.selectionAnchor = row
+}
+
+// clickRow updates the selected rows after the current row was clicked with
+// the given modifier keys: Shift selects a range of rows, Ctrl toggles the row
+// and without modifiers the row becomes the anchor of ranges.
+func (t *Table) clickRow(modifiers tcell.ModMask) {
+	previous := t.selectedRowsSnapshot()
+	switch {
+	case modifiers&tcell.ModShift != 0:
+		t.selectRowRange()
+	case modifiers&tcell.ModCtrl != 0:
+		t.toggleRow()
+	default:
+		t.selectionAnchor = t.contentRow(t.selectedRow)
+	}
+	t.notifySelectedRowsChanged(previous)
+}
+
+// moveSelectedRows updates the selected rows after the rows of the content
+// were reordered so that row order[i] became row i.
+func (t *Table) moveSelectedRows(order []int) {
+	if len(t.selectedRows) == 0 {
+		return
+	}
+	selected := t.selectedRows
+	t.selectedRows = nil
+	for row, previous := range order {
+		if selected[previous] {
+			t.setRowSelected(row, true)
+		}
+	}
+}
+
+// shiftSelectedRows updates the selected rows after a row was inserted
+// (delta 1) or removed (delta -1) at the given row of the content.
+func (t *Table) shiftSelectedRows(at, delta int) {
+	if len(t.selectedRows) == 0 {
+		return
+	}
+	selected := t.selectedRows
+	t.selectedRows = make(map[int]bool, len(selected))
+	for row := range selected {
+		switch {
+		case row < at:
+			t.selectedRows[row] = true
+		case delta < 0 && row == at:
+			// The row was removed.
+		default:
+			t.selectedRows[row+delta] = true
+		}
+	}
+}
+
+// selectedRowsSnapshot returns a copy of the selected rows, to detect changes.
+func (t *Table) selectedRowsSnapshot() map[int]bool {
+	snapshot := make(map[int]bool, len(t.selectedRows))
+	for row := range t.selectedRows {
+		snapshot[row] = true
+	}
+	return snapshot
+}
+
+// notifySelectedRowsChanged calls the selected rows changed handler if the
+// selected rows differ from the given ones. The table must be locked.
+func (t *Table) notifySelectedRowsChanged(previous map[int]bool) {
+	if t.selectedRowsChanged == nil {
+		return
+	}
+	changed := len(previous) != len(t.selectedRows)
+	for row := range t.selectedRows {
+		if changed {
+			break
+		}
+		changed = !previous[row]
+	}
+	if !changed {
+		return
+	}
+	handler, rows := t.selectedRowsChanged, t.getSelectedRows()
+	t.Unlock()
+	handler(rows)
+	t.Lock()
+}
@@ -0,0 +1,365 @@
+package crtview
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TableSortKey is a column a table is sorted by.
+type TableSortKey struct {
+	// The index of the column.
+	Column int
+
+	// Whether or not the column is sorted in descending order.
+	Descending bool
+}
+
+// sortableTableContent is implemented by contents which sort their rows
+// themselves, such as the content of a TreeTable. compare compares two cells
+// of a column.
+type sortableTableContent interface {
+	TableContent
+	sortBy(keys []TableSortKey, compare func(column int, a, b *TableCell) int)
+}
+
+// SortBy sorts the table by the given columns. Rows which are equal in the
+// first column are ordered by the second column, and so on. Rows which are
+// equal in all columns keep their order. Fixed rows are not sorted. Tables
+// whose content is not an EditableTableContent are not sorted, except for the
+// table of a TreeTable, which sorts sibling nodes.
+//
+// The columns are compared with the comparator set with SetColumnComparator,
+// or else with the sorting function set with SetSortFunc, or else by their
+// text, case-sensitive.
+func (t *Table) SortBy(keys ...TableSortKey) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.sortBy(keys)
+	return t
+}
+
+// GetSortKeys returns the columns the table was last sorted by.
+func (t *Table) GetSortKeys() []TableSortKey {
+	t.RLock()
+	defer t.RUnlock()
+
+	return append([]TableSortKey(nil), t.sortKeys...)
+}
+
+// SetColumnComparator sets the function which compares the cells of a column
+// when sorting. It receives the text of the cells without color and region
+// tags and returns a negative number if a sorts before b, a positive number if
+// a sorts after b and zero if they are equal. Built-in comparators are
+// CompareText, CompareNatural, CompareNumeric, CompareHumanSize and
+// CompareDate. Provide nil to remove the comparator of the column.
+func (t *Table) SetColumnComparator(column int, compare func(a, b string) int) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	if compare == nil {
+		delete(t.comparators, column)
+		return t
+	}
+	if t.comparators == nil {
+		t.comparators = make(map[int]func(a, b string) int)
+	}
+	t.comparators[column] = compare
+	return t
+}
+
+// SetSortIndicators sets a flag which determines whether the last fixed row
+// shows the columns the table is sorted by, with ▲ for ascending and ▼ for
+// descending order. If the table is sorted by more than one column, the
+// indicators are followed by the priority of the column. This flag is enabled
+// by default.
+func (t *Table) SetSortIndicators(show bool) *Table {
+	t.Lock()
+	defer t.Unlock()
+
+	t.sortIndicators = show
+	return t
+}
+
+// sortBy sorts the table by the given columns. Invalid keys are ignored.
+func (t *Table) sortBy(keys []TableSortKey) {
+	rowCount, columnCount := t.content.GetRowCount(), t.content.GetColumnCount()
+	sortable, _ := t.content.(sortableTableContent)
+	if rowCount == 0 || (t.editable() == nil && sortable == nil) {
+		return
+	}
+	var valid []TableSortKey
+	for _, key := range keys {
+		if key.Column >= 0 && key.Column < columnCount {
+			valid = append(valid, key)
+		}
+	}
+	if len(valid) == 0 {
+		return
+	}
+	t.sortKeys = valid
+	if sortable != nil {
+		sortable.sortBy(valid, t.compareCells)
+		t.filterRows()
+		return
+	}
+
+	order := make([]int, rowCount)
+	for i := range order {
+		order[i] = i
+	}
+	if t.fixedRows < rowCount {
+		rows := order[t.fixedRows:]
+		sort.SliceStable(rows, func(i, j int) bool {
+			for _, key := range valid {
+				c := t.compareRows(key.Column, rows[i], rows[j])
+				if key.Descending {
+					c = -c
+				}
+				if c != 0 {
+					return c < 0
+				}
+			}
+			return false
+		})
+	}
+	t.reorderRows(order)
+	t.moveSelectedRows(order)
+	t.filterRows()
+}
+
+// compareRows compares the cells of two rows in the given column.
+func (t *Table) compareRows(column, i, j int) int {
+	if _, ok := t.comparators[column]; !ok && t.sortFunc != nil {
+		if t.sortFunc(column, i, j) {
+			return -1
+		} else if t.sortFunc(column, j, i) {
+			return 1
+		}
+		return 0
+	}
+	return t.compareCells(column, t.content.GetCell(i, column), t.content.GetCell(j, column))
+}
+
+// compareCells compares two cells of the given column with the comparator of
+// the column, or else by their text. Either cell may be nil.
+func (t *Table) compareCells(column int, a, b *TableCell) int {
+	var textA, textB []byte
+	if a != nil {
+		textA = a.GetBytes()
+	}
+	if b != nil {
+		textB = b.GetBytes()
+	}
+	if compare, ok := t.comparators[column]; ok {
+		return compare(string(StripTags(textA, true, true)), string(StripTags(textB, true, true)))
+	}
+	return bytes.Compare(textA, textB)
+}
+
+// sortClick sorts the table after a fixed row was clicked in the given column.
+// A column which is clicked again is sorted in reverse order. If add is true,
+// the column becomes the next sort key instead of replacing the sort keys.
+func (t *Table) sortClick(column int, add bool) {
+	keys := append([]TableSortKey(nil), t.sortKeys...)
+	index := -1
+	for i, key := range keys {
+		if key.Column == column {
+			index = i
+		}
+	}
+	switch {
+	case add && index >= 0:
+		keys[index].Descending = !keys[index].Descending
+	case add:
+		keys = append(keys, TableSortKey{Column: column})
+	case index >= 0 && len(keys) == 1:
+		keys[0].Descending = !keys[0].Descending
+	default:
+		keys = []TableSortKey{{Column: column}}
+	}
+	t.sortBy(keys)
+}
+
+// sortIndicator returns the sort indicator drawn after the cell at the given
+// row and column (as drawn), or an empty string.
+func (t *Table) sortIndicator(row, column int) string {
+	if !t.sortIndicators || row != t.fixedRows-1 {
+		return ""
+	}
+	column = t.contentColumn(column)
+	for index, key := range t.sortKeys {
+		if key.Column != column {
+			continue
+		}
+		indicator := " ▲"
+		if key.Descending {
+			indicator = " ▼"
+		}
+		if len(t.sortKeys) > 1 {
+			indicator += strconv.Itoa(index + 1)
+		}
+		return indicator
+	}
+	return ""
+}
+
+// CompareText compares two strings, case-sensitive.
+func CompareText(a, b string) int {
+	return strings.Compare(a, b)
+}
+
+// CompareNatural compares two strings, ignoring case, where sequences of
+// digits are compared by their numeric value, e.g. "file2" sorts before
+// "file10".
+func CompareNatural(a, b string) int {
+	for a != "" && b != "" {
+		ra, _ := utf8.DecodeRuneInString(a)
+		rb, _ := utf8.DecodeRuneInString(b)
+		if isDigit(ra) && isDigit(rb) {
+			na, nb := digitPrefix(a), digitPrefix(b)
+			ta, tb := strings.TrimLeft(na, "0"), strings.TrimLeft(nb, "0")
+			if len(ta) != len(tb) {
+				return len(ta) - len(tb)
+			}
+			if c := strings.Compare(ta, tb); c != 0 {
+				return c
+			}
+			a, b = a[len(na):], b[len(nb):]
+			continue
+		}
+		if la, lb := unicode.ToLower(ra), unicode.ToLower(rb); la != lb {
+			return int(la) - int(lb)
+		}
+		a, b = a[utf8.RuneLen(ra):], b[utf8.RuneLen(rb):]
+	}
+	if a != "" || b != "" {
+		return len(a) - len(b)
+	}
+	return 0
+}
+
+// CompareNumeric compares two strings by their numeric value. Thousands
+// separators (",") and surrounding space are ignored. Strings which are not
+// numbers sort after numbers and are compared by CompareNatural.
+func CompareNumeric(a, b string) int {
+	return compareParsed(a, b, parseNumber)
+}
+
+// CompareHumanSize compares two sizes such as "512", "1.5G", "10 MB" or
+// "3KiB". The units K, M, G, T, P and E are powers of 1024. Strings which are
+// not sizes sort after sizes and are compared by CompareNatural.
+func CompareHumanSize(a, b string) int {
+	return compareParsed(a, b, parseHumanSize)
+}
+
+// tableDateLayouts are the layouts of the dates CompareDate recognizes.
+var tableDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+	"02.01.2006 15:04",
+	"02.01.2006",
+	"01/02/2006",
+	"Jan 2, 2006",
+	"2 Jan 2006",
+	"Jan 2 2006",
+	time.RFC1123,
+	time.RFC1123Z,
+	time.ANSIC,
+	time.Kitchen,
+}
+
+// CompareDate compares two dates or times, such as "2006-01-02",
+// "2006-01-02 15:04:05", "02.01.2006", "Jan 2, 2006" or RFC 3339 timestamps.
+// Strings which are not dates sort after dates and are compared by
+// CompareNatural.
+func CompareDate(a, b string) int {
+	return compareParsed(a, b, parseDate)
+}
+
+// compareParsed compares two strings by the values parsed from them. Strings
+// which cannot be parsed sort last.
+func compareParsed(a, b string, parse func(string) (float64, bool)) int {
+	va, oka := parse(a)
+	vb, okb := parse(b)
+	switch {
+	case oka && okb:
+		if va < vb {
+			return -1
+		} else if va > vb {
+			return 1
+		}
+		return 0
+	case oka:
+		return -1
+	case okb:
+		return 1
+	}
+	return CompareNatural(a, b)
+}
+
+// parseNumber parses a number, ignoring thousands separators.
+func parseNumber(s string) (float64, bool) {
+	s = strings.Replace(strings.TrimSpace(s), ",", "", -1)
+	v, err := strconv.ParseFloat(s, 64)
+	return v, err == nil
+}
+
+// parseHumanSize parses a size with an optional unit.
+func parseHumanSize(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	end := 0
+	for end < len(s) && (isDigit(rune(s[end])) || s[end] == '.' || s[end] == ',' || (end == 0 && (s[end] == '-' || s[end] == '+'))) {
+		end++
+	}
+	v, ok := parseNumber(s[:end])
+	if !ok {
+		return 0, false
+	}
+
+	unit := strings.ToUpper(strings.TrimSpace(s[end:]))
+	unit = strings.TrimSuffix(unit, "B")
+	unit = strings.TrimSuffix(unit, "I")
+	if unit == "" {
+		return v, true
+	}
+	exponent := strings.Index("KMGTPE", unit)
+	if len(unit) != 1 || exponent < 0 {
+		return 0, false
+	}
+	return v * math.Pow(1024, float64(exponent+1)), true
+}
+
+// parseDate parses a date in one of the tableDateLayouts.
+func parseDate(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range tableDateLayouts {
+		if d, err := time.Parse(layout, s); err == nil {
+			return float64(d.UnixNano()), true
+		}
+	}
+	return 0, false
+}
+
+// digitPrefix returns the leading ASCII digits of a string.
+func digitPrefix(s string) string {
+	end := 0
+	for end < len(s) && isDigit(rune(s[end])) {
+		end++
+	}
+	return s[:end]
+}
+
+// isDigit returns whether a rune is an ASCII digit.
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
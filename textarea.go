@@ -0,0 +1,1172 @@
+package crtview
+
+import (
+	"bytes"
+	"math"
+	"regexp"
+	"sync"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+var (
+	regexRightWord = regexp.MustCompile(`(\w*|\W)$`)
+	regexLeftWord  = regexp.MustCompile(`^(\W|\w*)`)
+)
+
+// textAreaRow is a row of text shown in a TextArea, given by byte indices into
+// the text. Rows never include line breaks.
+type textAreaRow struct {
+	start, end int
+}
+
+// TextArea is a multi-line text editor which may be used as a form item. Long
+// lines are wrapped (see SetWrap()) and the text scrolls vertically, with a
+// scroll bar if there is more text than fits into the field. Use
+// SetChangedFunc() to listen for changes.
+//
+// The following keys can be used for navigation and editing:
+//
+//   - Left arrow: Move left by one character.
+//   - Right arrow: Move right by one character.
+//   - Up arrow: Move up by one row.
+//   - Down arrow: Move down by one row.
+//   - Page up, page down: Move up or down by one page.
+//   - Home, Ctrl-A, Alt-a: Move to the beginning of the line.
+//   - End, Ctrl-E, Alt-e: Move to the end of the line.
+//   - Ctrl-Home, Ctrl-End: Move to the beginning or the end of the text.
+//   - Alt-left, Alt-b: Move left by one word.
+//   - Alt-right, Alt-f: Move right by one word.
+//   - Shift and any of the movement keys above: Select text.
+//   - Enter: Insert a line break.
+//   - Backspace: Delete the selected text or the character before the cursor.
+//   - Delete: Delete the selected text or the character after the cursor.
+//   - Ctrl-K: Delete from the cursor to the end of the line, or the line break
+//     if the cursor is at the end of the line.
+//   - Ctrl-W: Delete the last word before the cursor.
+//   - Ctrl-U: Delete the entire line.
+//   - Ctrl-Z: Undo the last edit.
+//   - Ctrl-Y: Redo the last undone edit.
+//
+// Text may also be selected by dragging the mouse over it. Typed and pasted
+// text replaces the selected text. The selected text may be copied to the
+// clipboard of the application (see Application.SetClipboard). Pasted text is
+// inserted at once if bracketed paste mode is enabled (see
+// Application.EnableBracketedPaste).
+type TextArea struct {
+	*Box
+
+	// The text that was entered.
+	text []byte
+
+	// The text to be displayed before the input area.
+	label []byte
+
+	// The text to be displayed in the input area when "text" is empty.
+	placeholder []byte
+
+	// The label color.
+	labelColor tcell.Color
+
+	// The label color when focused.
+	labelColorFocused tcell.Color
+
+	// The background color of the input area.
+	fieldBackgroundColor tcell.Color
+
+	// The background color of the input area when focused.
+	fieldBackgroundColorFocused tcell.Color
+
+	// The text color of the input area.
+	fieldTextColor tcell.Color
+
+	// The text color of the input area when focused.
+	fieldTextColorFocused tcell.Color
+
+	// The text color of the placeholder.
+	placeholderTextColor tcell.Color
+
+	// The text color of the placeholder when focused.
+	placeholderTextColorFocused tcell.Color
+
+	// The text color of selected text.
+	selectedTextColor tcell.Color
+
+	// The background color of selected text.
+	selectedBackgroundColor tcell.Color
+
+	// Visibility of the scroll bar.
+	scrollBarVisibility ScrollBarVisibility
+
+	// The scroll bar color.
+	scrollBarColor tcell.Color
+
+	// The screen width of the label area. A value of 0 means use the width of
+	// the label text.
+	labelWidth int
+
+	// The screen width of the input area. A value of 0 means extend as much as
+	// possible.
+	fieldWidth int
+
+	// The number of rows of the input area when the text area is a form item.
+	fieldHeight int
+
+	// If set to true, lines longer than the input area are wrapped. Otherwise
+	// the text scrolls horizontally.
+	wrap bool
+
+	// If set to true and if wrap is also true, lines are wrapped at spaces
+	// where possible.
+	wordWrap bool
+
+	// The cursor position as a byte index into the text string.
+	cursorPos int
+
+	// The byte index at which the selection starts, the cursor being at its
+	// other end, or -1 if no text is selected.
+	selectionStart int
+
+	// The screen column the cursor moves to when it is moved up or down, or -1
+	// to use the current column.
+	column int
+
+	// The rows of the text, as laid out for the current text width.
+	rows []textAreaRow
+
+	// The screen width available to the text as determined during the last
+	// call to Draw(). A value of 0 means there is no limit.
+	textWidth int
+
+	// The number of rows shown as determined during the last call to Draw().
+	pageHeight int
+
+	// The position of the input area as determined during the last call to
+	// Draw().
+	fieldX, fieldY int
+
+	// The number of rows skipped ahead while drawing.
+	rowOffset int
+
+	// The number of screen columns skipped ahead while drawing lines which are
+	// not wrapped.
+	columnOffset int
+
+	// If set to true, the text scrolls to the cursor when drawn.
+	trackCursor bool
+
+	// Whether text is being selected with the mouse.
+	dragging bool
+
+	// The edits which may be undone and redone.
+	edits editHistory
+
+	// An optional function which is called when the input has changed.
+	changed func(text string)
+
+	// An optional function which is called when the user indicated that they
+	// are done entering text. The key which was pressed is provided (tab,
+	// shift-tab, or escape).
+	done func(tcell.Key)
+
+	// A callback function set by the Form class and called when the user leaves
+	// this form item.
+	finished func(tcell.Key)
+
+	sync.RWMutex
+
+	*FormItemBaseMixin
+}
+
+// NewTextArea returns a new text area.
+func NewTextArea() *TextArea {
+	return &TextArea{
+		Box:                         NewBox(),
+		labelColor:                  Styles.SecondaryTextColor,
+		fieldBackgroundColor:        Styles.ContrastBackgroundColor,
+		fieldTextColor:              Styles.PrimaryTextColor,
+		placeholderTextColor:        Styles.ContrastSecondaryTextColor,
+		selectedTextColor:           Styles.PrimitiveBackgroundColor,
+		selectedBackgroundColor:     Styles.PrimaryTextColor,
+		scrollBarVisibility:         ScrollBarAuto,
+		scrollBarColor:              Styles.ScrollBarColor,
+		labelColorFocused:           ColorUnset,
+		fieldBackgroundColorFocused: ColorUnset,
+		fieldTextColorFocused:       ColorUnset,
+		placeholderTextColorFocused: ColorUnset,
+		fieldHeight:                 5,
+		wrap:                        true,
+		wordWrap:                    true,
+		selectionStart:              -1,
+		column:                      -1,
+		trackCursor:                 true,
+		FormItemBaseMixin:           &FormItemBaseMixin{},
+	}
+}
+
+// SetText sets the current text of the text area and moves the cursor to the
+// end of the text. Edits made before cannot be undone anymore.
+func (t *TextArea) SetText(text string) {
+	t.Lock()
+
+	t.text = []byte(text)
+	t.cursorPos = len(text)
+	t.selectionStart = -1
+	t.column = -1
+	t.edits.clear()
+	t.trackCursor = true
+	if t.changed != nil {
+		t.Unlock()
+		t.changed(text)
+	} else {
+		t.Unlock()
+	}
+}
+
+// GetText returns the current text of the text area.
+func (t *TextArea) GetText() string {
+	t.RLock()
+	defer t.RUnlock()
+
+	return string(t.text)
+}
+
+// SetLabel sets the text to be displayed before the input area.
+func (t *TextArea) SetLabel(label string) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.label = []byte(label)
+}
+
+// GetLabel returns the text to be displayed before the input area.
+func (t *TextArea) GetLabel() string {
+	t.RLock()
+	defer t.RUnlock()
+
+	return string(t.label)
+}
+
+// SetLabelWidth sets the screen width of the label. A value of 0 will cause the
+// primitive to use the width of the label string.
+func (t *TextArea) SetLabelWidth(width int) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.labelWidth = width
+}
+
+// SetPlaceholder sets the text to be displayed when the text is empty.
+func (t *TextArea) SetPlaceholder(text string) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.placeholder = []byte(text)
+}
+
+// SetLabelColor sets the color of the label.
+func (t *TextArea) SetLabelColor(color tcell.Color) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.labelColor = color
+}
+
+// SetLabelColorFocused sets the color of the label when focused.
+func (t *TextArea) SetLabelColorFocused(color tcell.Color) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.labelColorFocused = color
+}
+
+// SetFieldBackgroundColor sets the background color of the input area.
+func (t *TextArea) SetFieldBackgroundColor(color tcell.Color) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.fieldBackgroundColor = color
+}
+
+// SetFieldBackgroundColorFocused sets the background color of the input area
+// when focused.
+func (t *TextArea) SetFieldBackgroundColorFocused(color tcell.Color) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.fieldBackgroundColorFocused = color
+}
+
+// SetFieldTextColor sets the text color of the input area.
+func (t *TextArea) SetFieldTextColor(color tcell.Color) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.fieldTextColor = color
+}
+
+// SetFieldTextColorFocused sets the text color of the input area when focused.
+func (t *TextArea) SetFieldTextColorFocused(color tcell.Color) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.fieldTextColorFocused = color
+}
+
+// SetPlaceholderTextColor sets the text color of placeholder text.
+func (t *TextArea) SetPlaceholderTextColor(color tcell.Color) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.placeholderTextColor = color
+}
+
+// SetPlaceholderTextColorFocused sets the text color of placeholder text when
+// focused.
+func (t *TextArea) SetPlaceholderTextColorFocused(color tcell.Color) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.placeholderTextColorFocused = color
+}
+
+// SetSelectedTextColor sets the text color of selected text.
+func (t *TextArea) SetSelectedTextColor(color tcell.Color) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.selectedTextColor = color
+}
+
+// SetSelectedBackgroundColor sets the background color of selected text.
+func (t *TextArea) SetSelectedBackgroundColor(color tcell.Color) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.selectedBackgroundColor = color
+}
+
+// SetScrollBarVisibility specifies the display of the scroll bar.
+func (t *TextArea) SetScrollBarVisibility(visibility ScrollBarVisibility) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.scrollBarVisibility = visibility
+}
+
+// SetScrollBarColor sets the color of the scroll bar.
+func (t *TextArea) SetScrollBarColor(color tcell.Color) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.scrollBarColor = color
+}
+
+// SetFieldWidth sets the screen width of the input area. A value of 0 means
+// extend as much as possible.
+func (t *TextArea) SetFieldWidth(width int) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.fieldWidth = width
+}
+
+// GetFieldWidth returns this primitive's field width.
+func (t *TextArea) GetFieldWidth() int {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.fieldWidth
+}
+
+// SetFieldHeight sets the number of rows of the input area when the text area
+// is placed in a form. The default is 5.
+func (t *TextArea) SetFieldHeight(height int) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.fieldHeight = height
+}
+
+// GetFieldHeight returns the height of the field.
+func (t *TextArea) GetFieldHeight() int {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.fieldHeight
+}
+
+// SetWrap sets the flag that, if true, leads to lines that are longer than the
+// available width being wrapped onto the next row. If false, the text scrolls
+// horizontally to keep the cursor visible. The default is true.
+func (t *TextArea) SetWrap(wrap bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.wrap = wrap
+}
+
+// SetWordWrap sets the flag that, if true and if the "wrap" flag is also true
+// (see SetWrap()), wraps lines at spaces where possible. The default is true.
+func (t *TextArea) SetWordWrap(wrapOnWords bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.wordWrap = wrapOnWords
+}
+
+// GetCursorPosition returns the cursor position as a byte index into the text.
+func (t *TextArea) GetCursorPosition() int {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.cursorPos
+}
+
+// SetCursorPosition sets the cursor position as a byte index into the text and
+// clears the selection.
+func (t *TextArea) SetCursorPosition(cursorPos int) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.cursorPos = t.clamp(cursorPos)
+	t.selectionStart = -1
+	t.column = -1
+	t.edits.typing = false
+	t.trackCursor = true
+}
+
+// Select selects the text between the given byte indices and moves the cursor
+// to the end of the selection.
+func (t *TextArea) Select(start, end int) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.selectionStart, t.cursorPos = t.clamp(start), t.clamp(end)
+	t.column = -1
+	t.edits.typing = false
+	t.trackCursor = true
+}
+
+// GetSelection returns the byte indices of the beginning and the end of the
+// selected text. Both are equal to the cursor position if no text is selected.
+func (t *TextArea) GetSelection() (start, end int) {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.selection()
+}
+
+// GetSelectedText returns the selected text.
+func (t *TextArea) GetSelectedText() string {
+	t.RLock()
+	defer t.RUnlock()
+
+	start, end := t.selection()
+	return string(t.text[start:end])
+}
+
+// SetChangedFunc sets a handler which is called whenever the text of the text
+// area has changed. It receives the current text (after the change).
+func (t *TextArea) SetChangedFunc(handler func(text string)) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.changed = handler
+}
+
+// SetDoneFunc sets a handler which is called when the user is done entering
+// text. The callback function is provided with the key that was pressed, which
+// is one of the following:
+//
+//   - KeyEscape: Abort text input.
+//   - KeyTab: Move to the next field.
+//   - KeyBacktab: Move to the previous field.
+func (t *TextArea) SetDoneFunc(handler func(key tcell.Key)) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.done = handler
+}
+
+// SetFinishedFunc sets a callback invoked when the user leaves this form item.
+func (t *TextArea) SetFinishedFunc(handler func(key tcell.Key)) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.finished = handler
+}
+
+// clamp returns the given byte index, limited to the text.
+func (t *TextArea) clamp(pos int) int {
+	if pos < 0 {
+		return 0
+	} else if pos > len(t.text) {
+		return len(t.text)
+	}
+	return pos
+}
+
+// selection returns the byte indices of the beginning and the end of the
+// selected text, or the cursor position twice if no text is selected.
+func (t *TextArea) selection() (start, end int) {
+	if t.selectionStart < 0 {
+		return t.cursorPos, t.cursorPos
+	} else if t.selectionStart < t.cursorPos {
+		return t.selectionStart, t.cursorPos
+	}
+	return t.cursorPos, t.selectionStart
+}
+
+// lineStart returns the byte index of the beginning of the line containing the
+// given byte index.
+func (t *TextArea) lineStart(pos int) int {
+	return bytes.LastIndexByte(t.text[:pos], '\n') + 1
+}
+
+// lineEnd returns the byte index of the end of the line containing the given
+// byte index, excluding the line break.
+func (t *TextArea) lineEnd(pos int) int {
+	if end := bytes.IndexByte(t.text[pos:], '\n'); end >= 0 {
+		return pos + end
+	}
+	return len(t.text)
+}
+
+// layout splits the text into the rows shown for the current text width.
+func (t *TextArea) layout() {
+	width := t.textWidth
+	if !t.wrap || width <= 0 {
+		width = math.MaxInt32
+	}
+
+	t.rows = t.rows[:0]
+	for start := 0; ; {
+		end := t.lineEnd(start)
+		t.wrapLine(start, end, width)
+		if end == len(t.text) {
+			break
+		}
+		start = end + 1
+	}
+}
+
+// wrapLine adds the rows of the line between the given byte indices which is
+// wrapped at the given width. Spaces at the end of a row may exceed the width.
+func (t *TextArea) wrapLine(start, end, width int) {
+	rowStart, rowWidth := start, 0
+	breakPos, breakWidth := -1, 0
+	iterateString(string(t.text[start:end]), func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth int) bool {
+		pos := start + textPos
+		if rowWidth+screenWidth > width && pos > rowStart {
+			if main == ' ' {
+				// Break after the space.
+				t.rows = append(t.rows, textAreaRow{rowStart, pos + textWidth})
+				rowStart, rowWidth, breakPos = pos+textWidth, 0, -1
+				return false
+			} else if t.wordWrap && breakPos > rowStart {
+				// Break after the last space.
+				t.rows = append(t.rows, textAreaRow{rowStart, breakPos})
+				rowStart, rowWidth = breakPos, rowWidth-breakWidth
+			} else {
+				t.rows = append(t.rows, textAreaRow{rowStart, pos})
+				rowStart, rowWidth = pos, 0
+			}
+			breakPos = -1
+		}
+		rowWidth += screenWidth
+		if main == ' ' {
+			breakPos, breakWidth = pos+textWidth, rowWidth
+		}
+		return false
+	})
+	t.rows = append(t.rows, textAreaRow{rowStart, end})
+}
+
+// cursorRow returns the index of the row containing the cursor. A cursor at
+// the end of a wrapped row is shown at the beginning of the next row.
+func (t *TextArea) cursorRow() int {
+	for index, row := range t.rows {
+		if t.cursorPos >= row.start && (t.cursorPos < row.end || index == len(t.rows)-1 || t.rows[index+1].start > t.cursorPos) {
+			return index
+		}
+	}
+	return len(t.rows) - 1
+}
+
+// columnAt returns the screen column of the given byte index in the given row.
+func (t *TextArea) columnAt(row textAreaRow, pos int) int {
+	return runewidth.StringWidth(string(t.text[row.start:pos]))
+}
+
+// positionAt returns the byte index of the character at the given screen
+// column in the given row.
+func (t *TextArea) positionAt(row textAreaRow, column int) int {
+	pos := row.end
+	iterateString(string(t.text[row.start:row.end]), func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth int) bool {
+		if column < screenPos+screenWidth {
+			pos = row.start + textPos
+			return true
+		}
+		return false
+	})
+	return pos
+}
+
+// screenPosition returns the byte index of the character at the given screen
+// position, which is moved into the input area if it is outside.
+func (t *TextArea) screenPosition(x, y int) int {
+	row := y - t.fieldY + t.rowOffset
+	if row >= len(t.rows) {
+		row = len(t.rows) - 1
+	}
+	if row < 0 {
+		row = 0
+	}
+	column := x - t.fieldX + t.columnOffset
+	if column < 0 {
+		column = 0
+	}
+	return t.positionAt(t.rows[row], column)
+}
+
+// replace replaces the text between the given byte indices, moves the cursor
+// to the end of the new text and records the edit so it may be undone. Edits
+// which type characters are undone together with the characters typed right
+// before them.
+func (t *TextArea) replace(start, end int, text []byte, typing bool) {
+	t.edits.record(textEdit{text: t.text, cursorPos: t.cursorPos}, typing)
+
+	newText := make([]byte, 0, len(t.text)-(end-start)+len(text))
+	newText = append(newText, t.text[:start]...)
+	newText = append(newText, text...)
+	t.text = append(newText, t.text[end:]...)
+	t.cursorPos = start + len(text)
+	t.selectionStart = -1
+}
+
+// undo restores the text before the last edit.
+func (t *TextArea) undo() {
+	if edit, ok := t.edits.undo(textEdit{text: t.text, cursorPos: t.cursorPos}); ok {
+		t.text, t.cursorPos = edit.text, edit.cursorPos
+		t.selectionStart = -1
+	}
+}
+
+// redo restores the text before the last undo.
+func (t *TextArea) redo() {
+	if edit, ok := t.edits.redo(textEdit{text: t.text, cursorPos: t.cursorPos}); ok {
+		t.text, t.cursorPos = edit.text, edit.cursorPos
+		t.selectionStart = -1
+	}
+}
+
+// Draw draws this primitive onto the screen.
+func (t *TextArea) Draw(screen tcell.Screen) {
+	if !t.IsVisible() {
+		return
+	}
+
+	t.Box.Draw(screen)
+
+	t.Lock()
+	defer t.Unlock()
+
+	// Select colors
+	labelColor := t.labelColor
+	fieldBackgroundColor := t.fieldBackgroundColor
+	fieldTextColor := t.fieldTextColor
+	focused := t.GetFocusable().HasFocus()
+	if focused {
+		if t.labelColorFocused != ColorUnset {
+			labelColor = t.labelColorFocused
+		}
+		if t.fieldBackgroundColorFocused != ColorUnset {
+			fieldBackgroundColor = t.fieldBackgroundColorFocused
+		}
+		if t.fieldTextColorFocused != ColorUnset {
+			fieldTextColor = t.fieldTextColorFocused
+		}
+	}
+
+	// Prepare
+	x, y, width, height := t.GetInnerRect()
+	rightLimit := x + width
+	if height < 1 || rightLimit <= x {
+		return
+	}
+
+	// Draw label.
+	if t.labelWidth > 0 {
+		labelWidth := t.labelWidth
+		if labelWidth > rightLimit-x {
+			labelWidth = rightLimit - x
+		}
+		Print(screen, t.label, x, y, labelWidth, AlignLeft, labelColor)
+		x += labelWidth
+	} else {
+		_, drawnWidth := Print(screen, t.label, x, y, rightLimit-x, AlignLeft, labelColor)
+		x += drawnWidth
+	}
+
+	// Draw input area.
+	t.fieldX, t.fieldY = x, y
+	fieldWidth := t.fieldWidth
+	if fieldWidth == 0 {
+		fieldWidth = math.MaxInt32
+	}
+	if rightLimit-x < fieldWidth {
+		fieldWidth = rightLimit - x
+	}
+	if fieldWidth <= 0 {
+		return
+	}
+	fieldStyle := tcell.StyleDefault.Background(fieldBackgroundColor).Foreground(fieldTextColor)
+	for row := 0; row < height; row++ {
+		for index := 0; index < fieldWidth; index++ {
+			screen.SetContent(x+index, y+row, ' ', nil, fieldStyle)
+		}
+	}
+
+	// Lay out the text, leaving space for the scroll bar if needed.
+	t.textWidth = fieldWidth
+	if t.scrollBarVisibility == ScrollBarAlways {
+		t.textWidth--
+	}
+	t.layout()
+	if t.scrollBarVisibility == ScrollBarAuto && len(t.rows) > height && fieldWidth > 1 {
+		t.textWidth--
+		t.layout()
+	}
+	t.pageHeight = height
+
+	// Scroll to the cursor.
+	cursorRow := t.cursorRow()
+	cursorColumn := t.columnAt(t.rows[cursorRow], t.cursorPos)
+	if t.trackCursor {
+		if cursorRow < t.rowOffset {
+			t.rowOffset = cursorRow
+		} else if cursorRow >= t.rowOffset+height {
+			t.rowOffset = cursorRow - height + 1
+		}
+		if !t.wrap && t.textWidth > 0 {
+			if cursorColumn < t.columnOffset {
+				t.columnOffset = cursorColumn
+			} else if cursorColumn >= t.columnOffset+t.textWidth {
+				t.columnOffset = cursorColumn - t.textWidth + 1
+			}
+		}
+	}
+	if t.rowOffset > len(t.rows)-height {
+		t.rowOffset = len(t.rows) - height
+	}
+	if t.rowOffset < 0 {
+		t.rowOffset = 0
+	}
+	if t.wrap {
+		t.columnOffset = 0
+	}
+
+	// Draw placeholder text.
+	if len(t.text) == 0 && len(t.placeholder) > 0 {
+		placeholderTextColor := t.placeholderTextColor
+		if focused && t.placeholderTextColorFocused != ColorUnset {
+			placeholderTextColor = t.placeholderTextColorFocused
+		}
+		Print(screen, EscapeBytes(t.placeholder), x, y, t.textWidth, AlignLeft, placeholderTextColor)
+	}
+
+	// Draw text.
+	selectedStyle := tcell.StyleDefault.Background(t.selectedBackgroundColor).Foreground(t.selectedTextColor)
+	selectionStart, selectionEnd := t.selection()
+	for printed := 0; printed < height && t.rowOffset+printed < len(t.rows); printed++ {
+		row := t.rows[t.rowOffset+printed]
+		lastColumn := -t.columnOffset
+		iterateString(string(t.text[row.start:row.end]), func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth int) bool {
+			column := screenPos - t.columnOffset
+			lastColumn = column + screenWidth
+			if column < 0 {
+				return false
+			} else if column+screenWidth > t.textWidth {
+				return true
+			}
+			style := fieldStyle
+			if pos := row.start + textPos; pos >= selectionStart && pos < selectionEnd {
+				style = selectedStyle
+			}
+			screen.SetContent(x+column, y+printed, main, comb, style)
+			return false
+		})
+
+		// Show selected line breaks.
+		if row.end < len(t.text) && t.text[row.end] == '\n' && row.end >= selectionStart && row.end < selectionEnd && lastColumn >= 0 && lastColumn < t.textWidth {
+			screen.SetContent(x+lastColumn, y+printed, ' ', nil, selectedStyle)
+		}
+	}
+
+	// Draw scroll bar.
+	cursor := 0
+	if maxOffset := len(t.rows) - height; maxOffset > 0 {
+		cursor = t.rowOffset * (len(t.rows) - 1) / maxOffset
+	}
+	for printed := 0; printed < height && t.textWidth < fieldWidth; printed++ {
+		RenderScrollBar(screen, t.scrollBarVisibility, x+t.textWidth, y+printed, height, len(t.rows), cursor, printed, focused, t.scrollBarColor)
+	}
+
+	// Set cursor.
+	if focused {
+		column := cursorColumn - t.columnOffset
+		if column >= t.textWidth {
+			column = t.textWidth - 1
+		}
+		if row := cursorRow - t.rowOffset; row >= 0 && row < height && column >= 0 {
+			screen.ShowCursor(x+column, y+row)
+		}
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (t *TextArea) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return t.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		t.Lock()
+
+		// Trigger changed events.
+		currentText := t.text
+		defer func() {
+			t.Lock()
+			newText := t.text
+			changed := t.changed
+			t.Unlock()
+
+			if !bytes.Equal(newText, currentText) && changed != nil {
+				changed(string(newText))
+			}
+		}()
+
+		t.layout()
+		t.trackCursor = true
+		column := t.column
+		t.column = -1
+
+		// Movement functions. Movements with the Shift key select text.
+		selecting := event.Modifiers()&tcell.ModShift > 0
+		move := func(movement func()) {
+			if !selecting {
+				t.selectionStart = -1
+			} else if t.selectionStart < 0 {
+				t.selectionStart = t.cursorPos
+			}
+			movement()
+			if t.selectionStart == t.cursorPos {
+				t.selectionStart = -1
+			}
+			t.edits.typing = false
+		}
+		home := func() { t.cursorPos = t.lineStart(t.cursorPos) }
+		end := func() { t.cursorPos = t.lineEnd(t.cursorPos) }
+		moveLeft := func() {
+			iterateStringReverse(string(t.text[:t.cursorPos]), func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth int) bool {
+				t.cursorPos -= textWidth
+				return true
+			})
+		}
+		moveRight := func() {
+			iterateString(string(t.text[t.cursorPos:]), func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth int) bool {
+				t.cursorPos += textWidth
+				return true
+			})
+		}
+		moveWordLeft := func() {
+			t.cursorPos = len(regexRightWord.ReplaceAll(t.text[:t.cursorPos], nil))
+		}
+		moveWordRight := func() {
+			t.cursorPos = len(t.text) - len(regexLeftWord.ReplaceAll(t.text[t.cursorPos:], nil))
+		}
+		moveRows := func(rows int) func() {
+			return func() {
+				cursorRow := t.cursorRow()
+				if column < 0 {
+					column = t.columnAt(t.rows[cursorRow], t.cursorPos)
+				}
+				t.column = column
+				if row := cursorRow + rows; row < 0 {
+					t.cursorPos = 0
+				} else if row >= len(t.rows) {
+					t.cursorPos = len(t.text)
+				} else {
+					t.cursorPos = t.positionAt(t.rows[row], column)
+				}
+			}
+		}
+		page := t.pageHeight
+		if page < 1 {
+			page = 1
+		}
+
+		// Editing functions. Edits replace the selected text, if any.
+		insert := func(text []byte, typing bool) {
+			start, end := t.selection()
+			t.replace(start, end, text, typing)
+		}
+		deleteSelection := func() bool {
+			start, end := t.selection()
+			if start == end {
+				return false
+			}
+			t.replace(start, end, nil, false)
+			return true
+		}
+		deleteRange := func(start, end int) {
+			t.selectionStart = -1
+			if start < end {
+				t.replace(start, end, nil, false)
+			}
+		}
+
+		// Finish up.
+		finish := func(key tcell.Key) {
+			if t.done != nil {
+				t.done(key)
+			}
+			if t.finished != nil {
+				t.finished(key)
+			}
+		}
+
+		// Process key event.
+		switch key := event.Key(); key {
+		case tcell.KeyRune: // Regular character.
+			if event.Modifiers()&tcell.ModAlt > 0 {
+				// We accept some Alt- key combinations.
+				switch event.Rune() {
+				case 'a': // Home.
+					move(home)
+				case 'e': // End.
+					move(end)
+				case 'b': // Move word left.
+					move(moveWordLeft)
+				case 'f': // Move word right.
+					move(moveWordRight)
+				default:
+					insert([]byte(string(event.Rune())), !unicode.IsSpace(event.Rune()))
+				}
+			} else {
+				// Other keys are simply accepted as regular characters.
+				insert([]byte(string(event.Rune())), !unicode.IsSpace(event.Rune()))
+			}
+		case tcell.KeyEnter: // Insert a line break.
+			insert([]byte("\n"), false)
+		case tcell.KeyCtrlU: // Delete the line.
+			deleteRange(t.lineStart(t.cursorPos), t.lineEnd(t.cursorPos))
+		case tcell.KeyCtrlK: // Delete until the end of the line.
+			end := t.lineEnd(t.cursorPos)
+			if end == t.cursorPos && end < len(t.text) {
+				end++ // Join the next line.
+			}
+			deleteRange(t.cursorPos, end)
+		case tcell.KeyCtrlW: // Delete last word.
+			deleteRange(len(regexRightWord.ReplaceAll(t.text[:t.cursorPos], nil)), t.cursorPos)
+		case tcell.KeyCtrlZ:
+			t.undo()
+		case tcell.KeyCtrlY:
+			t.redo()
+		case tcell.KeyBackspace, tcell.KeyBackspace2: // Delete character before the cursor.
+			if !deleteSelection() {
+				end := t.cursorPos
+				moveLeft()
+				start := t.cursorPos
+				t.cursorPos = end
+				deleteRange(start, end)
+			}
+		case tcell.KeyDelete: // Delete character after the cursor.
+			if !deleteSelection() {
+				start := t.cursorPos
+				moveRight()
+				end := t.cursorPos
+				t.cursorPos = start
+				deleteRange(start, end)
+			}
+		case tcell.KeyLeft:
+			if event.Modifiers()&tcell.ModAlt > 0 {
+				move(moveWordLeft)
+			} else {
+				move(moveLeft)
+			}
+		case tcell.KeyRight:
+			if event.Modifiers()&tcell.ModAlt > 0 {
+				move(moveWordRight)
+			} else {
+				move(moveRight)
+			}
+		case tcell.KeyUp:
+			move(moveRows(-1))
+		case tcell.KeyDown:
+			move(moveRows(1))
+		case tcell.KeyPgUp:
+			move(moveRows(-page))
+		case tcell.KeyPgDn:
+			move(moveRows(page))
+		case tcell.KeyHome, tcell.KeyCtrlA:
+			if event.Modifiers()&tcell.ModCtrl > 0 && key == tcell.KeyHome {
+				move(func() { t.cursorPos = 0 })
+			} else {
+				move(home)
+			}
+		case tcell.KeyEnd, tcell.KeyCtrlE:
+			if event.Modifiers()&tcell.ModCtrl > 0 && key == tcell.KeyEnd {
+				move(func() { t.cursorPos = len(t.text) })
+			} else {
+				move(end)
+			}
+		case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab: // We're done.
+			t.selectionStart = -1
+			t.edits.typing = false
+			t.Unlock()
+			finish(key)
+			return
+		}
+
+		t.Unlock()
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (t *TextArea) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return t.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+
+		// Process mouse event.
+		switch action {
+		case MouseLeftDown:
+			if !t.InRect(x, y) {
+				return false, nil
+			}
+			// Place the cursor and start selecting text.
+			t.Lock()
+			t.layout()
+			t.cursorPos = t.screenPosition(x, y)
+			t.selectionStart = t.cursorPos
+			t.column = -1
+			t.edits.typing = false
+			t.trackCursor = true
+			t.dragging = true
+			t.Unlock()
+			setFocus(t)
+			return true, t
+		case MouseLeftClick:
+			if t.InRect(x, y) {
+				setFocus(t)
+				return true, nil
+			}
+			return false, nil
+		}
+
+		t.Lock()
+		defer t.Unlock()
+
+		switch action {
+		case MouseMove:
+			if !t.dragging || event.Buttons()&tcell.ButtonPrimary == 0 {
+				return false, nil
+			}
+			t.layout()
+			t.cursorPos = t.screenPosition(x, y)
+			t.trackCursor = true
+			return true, t
+		case MouseLeftUp:
+			if !t.dragging {
+				return false, nil
+			}
+			t.dragging = false
+			if t.selectionStart == t.cursorPos {
+				t.selectionStart = -1
+			}
+			return true, nil
+		case MouseScrollUp:
+			if t.InRect(x, y) {
+				t.trackCursor = false
+				if t.rowOffset > 0 {
+					t.rowOffset--
+				}
+				return true, nil
+			}
+		case MouseScrollDown:
+			if t.InRect(x, y) {
+				t.trackCursor = false
+				t.rowOffset++
+				return true, nil
+			}
+		}
+
+		return false, nil
+	})
+}
+
+// PasteHandler returns the handler for pasted text. The text replaces the
+// selected text, or is inserted at the cursor, and is undone at once.
+func (t *TextArea) PasteHandler() func(text string, setFocus func(p Primitive)) {
+	return func(text string, setFocus func(p Primitive)) {
+		t.Lock()
+		paste := pasteLines(text)
+		start, end := t.selection()
+		if len(paste) == 0 && start == end {
+			t.Unlock()
+			return
+		}
+		t.replace(start, end, paste, false)
+		t.column = -1
+		t.trackCursor = true
+		newText, changed := t.text, t.changed
+		t.Unlock()
+
+		if changed != nil {
+			changed(string(newText))
+		}
+	}
+}
+
+// CopyHandler returns the handler which copies or cuts the selected text.
+func (t *TextArea) CopyHandler() func(cut bool) (text string, ok bool) {
+	return func(cut bool) (text string, ok bool) {
+		t.Lock()
+		start, end := t.selection()
+		if start == end {
+			t.Unlock()
+			return "", false
+		}
+		text = string(t.text[start:end])
+		if !cut {
+			t.Unlock()
+			return text, true
+		}
+		t.replace(start, end, nil, false)
+		t.column = -1
+		t.trackCursor = true
+		newText, changed := t.text, t.changed
+		t.Unlock()
+
+		if changed != nil {
+			changed(string(newText))
+		}
+		return text, true
+	}
+}
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme. Colors which were set explicitly are kept.
+func (t *TextArea) ApplyTheme(previous, theme *Theme) {
+	t.Box.ApplyTheme(previous, theme)
+
+	t.Lock()
+	defer t.Unlock()
+
+	themeColor(&t.labelColor, previous.SecondaryTextColor, theme.SecondaryTextColor)
+	themeColor(&t.fieldBackgroundColor, previous.ContrastBackgroundColor, theme.ContrastBackgroundColor)
+	themeColor(&t.fieldTextColor, previous.PrimaryTextColor, theme.PrimaryTextColor)
+	themeColor(&t.placeholderTextColor, previous.ContrastSecondaryTextColor, theme.ContrastSecondaryTextColor)
+	themeColor(&t.selectedTextColor, previous.PrimitiveBackgroundColor, theme.PrimitiveBackgroundColor)
+	themeColor(&t.selectedBackgroundColor, previous.PrimaryTextColor, theme.PrimaryTextColor)
+	themeColor(&t.scrollBarColor, previous.ScrollBarColor, theme.ScrollBarColor)
+}
@@ -0,0 +1,125 @@
+package crtview
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestTextArea(t *testing.T) {
+	t.Parallel()
+
+	a := NewTextArea()
+	a.SetRect(0, 0, 10, 3)
+
+	app, err := newTestApp(a)
+	if err != nil {
+		t.Fatalf("failed to initialize Application: %s", err)
+	}
+
+	key := func(k tcell.Key, r rune, mod tcell.ModMask) {
+		a.InputHandler()(tcell.NewEventKey(k, r, mod), nil)
+	}
+	typeText := func(text string) {
+		for _, r := range text {
+			if r == '\n' {
+				key(tcell.KeyEnter, 0, tcell.ModNone)
+			} else {
+				key(tcell.KeyRune, r, tcell.ModNone)
+			}
+		}
+	}
+
+	// Type and wrap text.
+	var changed string
+	a.SetChangedFunc(func(text string) {
+		changed = text
+	})
+	typeText("hello big world\nbye")
+	if text := a.GetText(); text != "hello big world\nbye" || changed != text {
+		t.Fatalf("failed to type text: expected hello big world\\nbye, got %q (changed %q)", text, changed)
+	}
+	a.SetScrollBarVisibility(ScrollBarNever)
+	a.Draw(app.screen)
+	for y, expected := range []string{"hello big", "world", "bye"} {
+		if line := screenLine(app.screen, y); line != expected {
+			t.Errorf("failed to wrap line %d: expected %q, got %q", y, expected, line)
+		}
+	}
+
+	// Move across rows, keeping the column.
+	key(tcell.KeyUp, 0, tcell.ModNone)
+	if pos := a.GetCursorPosition(); pos != 13 {
+		t.Errorf("failed to move up: expected 13, got %d", pos)
+	}
+	key(tcell.KeyUp, 0, tcell.ModNone)
+	if pos := a.GetCursorPosition(); pos != 3 {
+		t.Errorf("failed to move up: expected 3, got %d", pos)
+	}
+	key(tcell.KeyDown, 0, tcell.ModNone)
+	key(tcell.KeyDown, 0, tcell.ModNone)
+	if pos := a.GetCursorPosition(); pos != 19 {
+		t.Errorf("failed to move down: expected 19, got %d", pos)
+	}
+
+	// Select and replace text.
+	key(tcell.KeyHome, 0, tcell.ModNone)
+	key(tcell.KeyRight, 0, tcell.ModShift)
+	key(tcell.KeyRight, 0, tcell.ModShift)
+	if selected := a.GetSelectedText(); selected != "by" {
+		t.Errorf("failed to select text: expected by, got %s", selected)
+	}
+	typeText("se")
+	if text := a.GetText(); text != "hello big world\nsee" {
+		t.Errorf("failed to replace selection: expected hello big world\\nsee, got %q", text)
+	}
+
+	// Undo and redo.
+	key(tcell.KeyCtrlZ, 0, tcell.ModCtrl)
+	if text := a.GetText(); text != "hello big world\nbye" {
+		t.Errorf("failed to undo: expected hello big world\\nbye, got %q", text)
+	}
+	key(tcell.KeyCtrlY, 0, tcell.ModCtrl)
+	if text := a.GetText(); text != "hello big world\nsee" {
+		t.Errorf("failed to redo: expected hello big world\\nsee, got %q", text)
+	}
+
+	// Delete words and lines.
+	key(tcell.KeyUp, 0, tcell.ModNone)
+	key(tcell.KeyEnd, 0, tcell.ModNone)
+	key(tcell.KeyCtrlW, 0, tcell.ModCtrl)
+	if text := a.GetText(); text != "hello big \nsee" {
+		t.Errorf("failed to delete word: expected hello big \\nsee, got %q", text)
+	}
+	key(tcell.KeyCtrlK, 0, tcell.ModCtrl)
+	if text := a.GetText(); text != "hello big see" {
+		t.Errorf("failed to join lines: expected hello big see, got %q", text)
+	}
+	key(tcell.KeyCtrlU, 0, tcell.ModCtrl)
+	if text := a.GetText(); text != "" {
+		t.Errorf("failed to delete line: expected empty text, got %q", text)
+	}
+
+	// Scroll to the cursor.
+	a.SetScrollBarVisibility(ScrollBarAuto)
+	typeText("1\n2\n3\n4\n5")
+	a.Draw(app.screen)
+	for y, expected := range []string{"3", "4", "5"} {
+		if line := screenLine(app.screen, y); line[:1] != expected {
+			t.Errorf("failed to scroll line %d: expected %s, got %q", y, expected, line)
+		}
+	}
+	if mainc, _, _, _ := app.screen.GetContent(9, 2); mainc != ' ' {
+		t.Errorf("failed to draw scroll bar handle: got %c", mainc)
+	} else if mainc, _, _, _ = app.screen.GetContent(9, 0); mainc != '▒' {
+		t.Errorf("failed to draw scroll bar: got %c", mainc)
+	}
+
+	// Select text with the mouse.
+	a.MouseHandler()(MouseLeftDown, tcell.NewEventMouse(0, 0, tcell.Button1, tcell.ModNone), func(p Primitive) {})
+	a.MouseHandler()(MouseMove, tcell.NewEventMouse(1, 1, tcell.Button1, tcell.ModNone), func(p Primitive) {})
+	a.MouseHandler()(MouseLeftUp, tcell.NewEventMouse(1, 1, tcell.ButtonNone, tcell.ModNone), func(p Primitive) {})
+	if selected := a.GetSelectedText(); selected != "3\n4" {
+		t.Errorf("failed to select text with the mouse: expected 3\\n4, got %q", selected)
+	}
+}
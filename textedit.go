@@ -0,0 +1,192 @@
+package crtview
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// editHistorySize is the number of edits of an InputField or a TextArea which
+// may be undone.
+const editHistorySize = 100
+
+// killRingSize is the number of killed texts an InputField keeps.
+const killRingSize = 16
+
+// textEdit is a state of an edited text which is restored when an edit is
+// undone or redone.
+type textEdit struct {
+	text      []byte
+	cursorPos int
+}
+
+// editHistory records the states of the text of an InputField or a TextArea
+// so that edits may be undone and redone.
+type editHistory struct {
+	// The states of the text which are restored when edits are undone or
+	// redone, the most recent last. At most editHistorySize states are kept.
+	undoStack, redoStack []textEdit
+
+	// Whether the last edit typed a character. Characters typed in a row are
+	// undone together.
+	typing bool
+}
+
+// record records the state of the text before an edit. Edits which type
+// characters are undone together with the characters typed right before them.
+// Edits which were undone cannot be redone anymore.
+func (h *editHistory) record(state textEdit, typing bool) {
+	if !typing || !h.typing {
+		h.undoStack = pushEdit(h.undoStack, state)
+	}
+	h.redoStack = nil
+	h.typing = typing
+}
+
+// undo returns the state of the text before the last edit and records the
+// given current state so it may be restored by redo. It returns false if there
+// is no edit to undo.
+func (h *editHistory) undo(current textEdit) (textEdit, bool) {
+	if len(h.undoStack) == 0 {
+		return textEdit{}, false
+	}
+	state := h.undoStack[len(h.undoStack)-1]
+	h.undoStack = h.undoStack[:len(h.undoStack)-1]
+	h.redoStack = pushEdit(h.redoStack, current)
+	h.typing = false
+	return state, true
+}
+
+// redo returns the state of the text before the last undo and records the
+// given current state so it may be restored by undo. It returns false if there
+// is no undone edit.
+func (h *editHistory) redo(current textEdit) (textEdit, bool) {
+	if len(h.redoStack) == 0 {
+		return textEdit{}, false
+	}
+	state := h.redoStack[len(h.redoStack)-1]
+	h.redoStack = h.redoStack[:len(h.redoStack)-1]
+	h.undoStack = pushEdit(h.undoStack, current)
+	h.typing = false
+	return state, true
+}
+
+// clear removes all recorded states.
+func (h *editHistory) clear() {
+	h.undoStack, h.redoStack = nil, nil
+	h.typing = false
+}
+
+// pushEdit adds a state to a stack of states, removing the oldest state if the
+// stack has more than editHistorySize states.
+func pushEdit(stack []textEdit, state textEdit) []textEdit {
+	stack = append(stack, state)
+	if len(stack) > editHistorySize {
+		stack = stack[1:]
+	}
+	return stack
+}
+
+// killRing keeps the text killed in an InputField, which may be yanked.
+type killRing struct {
+	// The killed texts, the most recent last.
+	entries [][]byte
+
+	// Whether the last key killed text. Text killed by consecutive keys is
+	// kept as one entry.
+	killing bool
+
+	// The index of the entry which was yanked by the last key and the byte
+	// index at which it was inserted, or -1 if the last key did not yank text.
+	yankIndex, yankStart int
+}
+
+// newKillRing returns a new, empty kill ring.
+func newKillRing() killRing {
+	return killRing{yankStart: -1}
+}
+
+// startKey is called before a key is handled. It returns whether the previous
+// key killed text and the byte index at which it yanked text, or -1.
+func (r *killRing) startKey() (killing bool, yankStart int) {
+	killing, yankStart = r.killing, r.yankStart
+	r.killing, r.yankStart = false, -1
+	return
+}
+
+// add adds killed text to the ring. If joined is true, the text is added to
+// the most recent entry instead, in front of it if before is true.
+func (r *killRing) add(text []byte, joined, before bool) {
+	killed := append([]byte(nil), text...)
+	if last := len(r.entries) - 1; joined && last >= 0 {
+		if before {
+			r.entries[last] = append(killed, r.entries[last]...)
+		} else {
+			r.entries[last] = append(r.entries[last], killed...)
+		}
+	} else {
+		r.entries = append(r.entries, killed)
+		if len(r.entries) > killRingSize {
+			r.entries = r.entries[1:]
+		}
+	}
+	r.killing = true
+}
+
+// previous returns the index of the entry killed before the entry which was
+// yanked last, wrapping around to the most recent entry.
+func (r *killRing) previous() int {
+	if r.yankIndex > 0 {
+		return r.yankIndex - 1
+	}
+	return len(r.entries) - 1
+}
+
+// isWordRune returns whether a rune is part of a word, as defined by readline.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// wordLeft returns the byte index of the beginning of the word before the
+// given byte index.
+func wordLeft(text []byte, pos int) int {
+	for inWord := false; pos > 0; {
+		r, size := utf8.DecodeLastRune(text[:pos])
+		if isWordRune(r) {
+			inWord = true
+		} else if inWord {
+			break
+		}
+		pos -= size
+	}
+	return pos
+}
+
+// wordRight returns the byte index of the end of the word after the given byte
+// index.
+func wordRight(text []byte, pos int) int {
+	for inWord := false; pos < len(text); {
+		r, size := utf8.DecodeRune(text[pos:])
+		if isWordRune(r) {
+			inWord = true
+		} else if inWord {
+			break
+		}
+		pos += size
+	}
+	return pos
+}
+
+// spaceWordLeft returns the byte index after the space which precedes the
+// word before the given byte index. Words are delimited by spaces only.
+func spaceWordLeft(text []byte, pos int) int {
+	for inWord := false; pos > 0; {
+		r, size := utf8.DecodeLastRune(text[:pos])
+		if !unicode.IsSpace(r) {
+			inWord = true
+		} else if inWord {
+			break
+		}
+		pos -= size
+	}
+	return pos
+}
@@ -0,0 +1,39 @@
+package crtview
+
+import "testing"
+
+func TestEditHistory(t *testing.T) {
+	t.Parallel()
+
+	var h editHistory
+	for n := 0; n < editHistorySize+50; n++ {
+		h.record(textEdit{cursorPos: n}, false)
+	}
+	var undone int
+	for {
+		state, ok := h.undo(textEdit{})
+		if !ok {
+			break
+		}
+		if expected := editHistorySize + 49 - undone; state.cursorPos != expected {
+			t.Fatalf("failed to undo edit: expected cursor position %d, got %d", expected, state.cursorPos)
+		}
+		undone++
+	}
+	if undone != editHistorySize {
+		t.Errorf("failed to limit undo stack: expected %d edits, got %d", editHistorySize, undone)
+	}
+
+	// Typed characters are undone together.
+	h.clear()
+	h.record(textEdit{cursorPos: 0}, true)
+	h.record(textEdit{cursorPos: 1}, true)
+	h.record(textEdit{cursorPos: 2}, false)
+	if state, _ := h.undo(textEdit{cursorPos: 3}); state.cursorPos != 2 {
+		t.Errorf("failed to undo edit: expected cursor position 2, got %d", state.cursorPos)
+	} else if state, _ = h.undo(textEdit{cursorPos: 2}); state.cursorPos != 0 {
+		t.Errorf("failed to undo typed characters: expected cursor position 0, got %d", state.cursorPos)
+	} else if state, _ = h.redo(textEdit{cursorPos: 0}); state.cursorPos != 2 {
+		t.Errorf("failed to redo typed characters: expected cursor position 2, got %d", state.cursorPos)
+	}
+}
@@ -205,6 +205,15 @@ type TextView struct {
 	// TODO: handle it in terminal way in a future
 	skipCRet bool
 
+	// Whether the user selected text. The selection starts at selectionStart
+	// and ends before selectionEnd, which may be before selectionStart.
+	hasSelection   bool
+	selectionStart textViewPosition
+	selectionEnd   textViewPosition
+
+	// Whether the user is selecting text with the mouse.
+	dragging bool
+
 	sync.RWMutex
 }
 
@@ -440,6 +449,7 @@ func (t *TextView) clipBuffer() {
 	lenbuf := len(t.buffer)
 	if lenbuf > t.maxLines {
 		t.buffer = t.buffer[lenbuf-t.maxLines:]
+		t.hasSelection = false
 	}
 }
 
@@ -515,6 +525,7 @@ func (t *TextView) Clear() {
 func (t *TextView) clear() {
 	t.buffer = nil
 	t.recentBytes = nil
+	t.hasSelection = false
 	if t.reindex {
 		t.index = nil
 	}
@@ -1149,6 +1160,7 @@ func (t *TextView) Draw(screen tcell.Screen) {
 
 		// Print the line.
 		if y+line-t.lineOffset >= 0 {
+			start := t.indexStart(index)
 			var colorPos, regionPos, escapePos, tagOffset, skipped int
 			iterateString(string(strippedText), func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth int) bool {
 				// Process tags.
@@ -1216,6 +1228,11 @@ func (t *TextView) Draw(screen tcell.Screen) {
 					style = style.Background(fg).Foreground(bg)
 				}
 
+				// Do we select this character?
+				if t.isSelected(textViewPosition{line: start.line, pos: start.pos + textPos}) {
+					style = style.Reverse(true)
+				}
+
 				// Skip to the right.
 				if !t.wrap && skipped < skip {
 					skipped += screenWidth
@@ -1259,6 +1276,7 @@ func (t *TextView) Draw(screen tcell.Screen) {
 		} else {
 			t.buffer = t.buffer[t.index[t.lineOffset].Line:]
 		}
+		t.hasSelection = false
 		t.index = nil
 		t.lineOffset = 0
 	}
@@ -1267,9 +1285,19 @@ func (t *TextView) Draw(screen tcell.Screen) {
 // InputHandler returns the handler for this primitive.
 func (t *TextView) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return t.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		keys := t.GetKeys()
+
 		key := event.Key()
 
-		if HitShortcut(event, Keys.Cancel, Keys.Select, Keys.Select2, Keys.MovePreviousField, Keys.MoveNextField) {
+		t.Lock()
+		if t.hasSelection && HitShortcut(event, keys.Cancel) {
+			t.hasSelection = false
+			t.Unlock()
+			return
+		}
+		t.Unlock()
+
+		if HitShortcut(event, keys.Cancel, keys.Select, keys.Select2, keys.MovePreviousField, keys.MoveNextField) {
 			if t.done != nil {
 				t.done(key)
 			}
@@ -1283,26 +1311,30 @@ func (t *TextView) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 			return
 		}
 
-		if HitShortcut(event, Keys.MoveFirst, Keys.MoveFirst2) {
+		if t.moveSelection(event, keys) {
+			return
+		}
+
+		if HitShortcut(event, keys.MoveFirst, keys.MoveFirst2) {
 			t.trackEnd = false
 			t.lineOffset = 0
 			t.columnOffset = 0
-		} else if HitShortcut(event, Keys.MoveLast, Keys.MoveLast2) {
+		} else if HitShortcut(event, keys.MoveLast, keys.MoveLast2) {
 			t.trackEnd = true
 			t.columnOffset = 0
-		} else if HitShortcut(event, Keys.MoveUp, Keys.MoveUp2) {
+		} else if HitShortcut(event, keys.MoveUp, keys.MoveUp2) {
 			t.trackEnd = false
 			t.lineOffset--
-		} else if HitShortcut(event, Keys.MoveDown, Keys.MoveDown2) {
+		} else if HitShortcut(event, keys.MoveDown, keys.MoveDown2) {
 			t.lineOffset++
-		} else if HitShortcut(event, Keys.MoveLeft, Keys.MoveLeft2) {
+		} else if HitShortcut(event, keys.MoveLeft, keys.MoveLeft2) {
 			t.columnOffset--
-		} else if HitShortcut(event, Keys.MoveRight, Keys.MoveRight2) {
+		} else if HitShortcut(event, keys.MoveRight, keys.MoveRight2) {
 			t.columnOffset++
-		} else if HitShortcut(event, Keys.MovePreviousPage) {
+		} else if HitShortcut(event, keys.MovePreviousPage) {
 			t.trackEnd = false
 			t.lineOffset -= t.pageSize
-		} else if HitShortcut(event, Keys.MoveNextPage) {
+		} else if HitShortcut(event, keys.MoveNextPage) {
 			t.lineOffset += t.pageSize
 		}
 	})
@@ -1311,6 +1343,13 @@ func (t *TextView) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 // MouseHandler returns the mouse handler for this primitive.
 func (t *TextView) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 	return t.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		t.Lock()
+		consumed, capture = t.handleSelectionMouse(action, event)
+		t.Unlock()
+		if consumed {
+			return
+		}
+
 		x, y := event.Position()
 		if !t.InRect(x, y) {
 			return false, nil
@@ -1345,3 +1384,15 @@ func (t *TextView) MouseHandler() func(action MouseAction, event *tcell.EventMou
 		return
 	})
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme. Colors which were set explicitly are kept.
+func (t *TextView) ApplyTheme(previous, theme *Theme) {
+	t.Box.ApplyTheme(previous, theme)
+
+	t.Lock()
+	defer t.Unlock()
+
+	themeColor(&t.textColor, previous.PrimaryTextColor, theme.PrimaryTextColor)
+	themeColor(&t.scrollBarColor, previous.ScrollBarColor, theme.ScrollBarColor)
+}
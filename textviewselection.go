@@ -0,0 +1,302 @@
+package crtview
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+// textViewPosition is a position in the text of a text view.
+type textViewPosition struct {
+	// The index into the "buffer" variable.
+	line int
+
+	// The byte index into the buffer line, without tags.
+	pos int
+}
+
+// before returns whether the position is before another position.
+func (p textViewPosition) before(other textViewPosition) bool {
+	return p.line < other.line || p.line == other.line && p.pos < other.pos
+}
+
+// GetSelectedText returns the text the user selected, without tags, or an
+// empty string if no text is selected.
+//
+// The user selects text by dragging the mouse over it, or with the SelectUp,
+// SelectDown, SelectLeft, SelectRight, SelectFirst, SelectLast,
+// SelectPreviousPage and SelectNextPage shortcuts (see Keys), which start at
+// the top left corner of the text view. Cancel clears the selection. Selected
+// text is drawn inverted and may be copied to the clipboard of the application
+// (see Application.SetClipboard).
+//
+// Text views have no select-all key, as SelectAll is bound to the same key as
+// MoveFirst. Without a selection, SelectLast selects all text.
+func (t *TextView) GetSelectedText() string {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.selectedText()
+}
+
+// ClearSelection clears the selection of the user.
+func (t *TextView) ClearSelection() *TextView {
+	t.Lock()
+	defer t.Unlock()
+
+	t.hasSelection = false
+	return t
+}
+
+// CopyHandler returns the handler which copies the selected text. The text of
+// a text view is never cut.
+func (t *TextView) CopyHandler() func(cut bool) (text string, ok bool) {
+	return func(cut bool) (text string, ok bool) {
+		t.RLock()
+		defer t.RUnlock()
+
+		if cut || !t.hasSelection || t.selectionStart == t.selectionEnd {
+			return "", false
+		}
+		return t.selectedText(), true
+	}
+}
+
+// strippedLine returns a buffer line without tags.
+func (t *TextView) strippedLine(line []byte) []byte {
+	_, _, _, _, _, stripped, _ := decomposeText(line, t.dynamicColors, t.regions)
+	return stripped
+}
+
+// indexStart returns the position of the first character of a line of the
+// index.
+func (t *TextView) indexStart(index *textViewIndex) textViewPosition {
+	return textViewPosition{line: index.Line, pos: len(t.strippedLine(t.buffer[index.Line][:index.Pos]))}
+}
+
+// selection returns the beginning and the end of the selection, in order.
+func (t *TextView) selection() (from, to textViewPosition) {
+	if t.selectionEnd.before(t.selectionStart) {
+		return t.selectionEnd, t.selectionStart
+	}
+	return t.selectionStart, t.selectionEnd
+}
+
+// isSelected returns whether the character at the given position is selected.
+func (t *TextView) isSelected(position textViewPosition) bool {
+	if !t.hasSelection {
+		return false
+	}
+	from, to := t.selection()
+	return !position.before(from) && position.before(to)
+}
+
+// selectedText returns the selected text.
+func (t *TextView) selectedText() string {
+	if !t.hasSelection {
+		return ""
+	}
+	from, to := t.selection()
+	var lines []string
+	for line := from.line; line <= to.line && line < len(t.buffer); line++ {
+		text := t.strippedLine(t.buffer[line])
+		start, end := 0, len(text)
+		if line == from.line && from.pos < end {
+			start = from.pos
+		}
+		if line == to.line && to.pos < end {
+			end = to.pos
+		}
+		if start > end {
+			start = end
+		}
+		lines = append(lines, string(text[start:end]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indexRow returns the index of the line of the index which contains the given
+// position.
+func (t *TextView) indexRow(position textViewPosition) int {
+	row := 0
+	for index, line := range t.index {
+		if line.Line > position.line {
+			break
+		} else if line.Line == position.line && t.indexStart(line).pos > position.pos {
+			break
+		}
+		row = index
+	}
+	return row
+}
+
+// linePosition returns the horizontal screen position of the first character
+// of a line of the index, relative to the text view.
+func (t *TextView) linePosition(index *textViewIndex) int {
+	if t.align == AlignRight {
+		return t.lastWidth - index.Width - t.columnOffset
+	} else if t.align == AlignCenter {
+		return (t.lastWidth-index.Width)/2 - t.columnOffset
+	}
+	return -t.columnOffset
+}
+
+// positionAtColumn returns the position of the character at the given screen
+// column of a line of the index.
+func (t *TextView) positionAtColumn(row, column int) textViewPosition {
+	index := t.index[row]
+	position := t.indexStart(index)
+	text := t.strippedLine(t.buffer[index.Line][index.Pos:index.NextPos])
+	pos := len(text)
+	iterateString(string(text), func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth int) bool {
+		if column < screenPos+screenWidth {
+			pos = textPos
+			return true
+		}
+		return false
+	})
+	position.pos += pos
+	return position
+}
+
+// positionAt returns the position of the character at the given screen
+// position, which is moved into the text view if it is outside. It returns
+// false if there is no text.
+func (t *TextView) positionAt(x, y int) (textViewPosition, bool) {
+	if len(t.index) == 0 {
+		return textViewPosition{}, false
+	}
+	rectX, rectY, _, _ := t.GetInnerRect()
+	row := y - rectY + t.lineOffset
+	if row < 0 {
+		row = 0
+	} else if row >= len(t.index) {
+		row = len(t.index) - 1
+	}
+	return t.positionAtColumn(row, x-rectX-t.linePosition(t.index[row])), true
+}
+
+// moveSelection moves the end of the selection, starting a selection at the
+// top left corner of the text view if there is none, and scrolls to it. It
+// returns whether the selection was moved.
+func (t *TextView) moveSelection(event *tcell.EventKey, keys *Key) bool {
+	if !HitShortcut(event, keys.SelectUp, keys.SelectDown, keys.SelectLeft, keys.SelectRight, keys.SelectFirst, keys.SelectLast, keys.SelectPreviousPage, keys.SelectNextPage) {
+		return false
+	}
+	t.reindexBuffer(t.lastWidth)
+	if len(t.index) == 0 {
+		return true
+	}
+	if !t.hasSelection {
+		offset := t.lineOffset
+		if offset < 0 || offset >= len(t.index) {
+			offset = 0
+		}
+		t.selectionStart = t.indexStart(t.index[offset])
+		t.selectionEnd = t.selectionStart
+		t.hasSelection = true
+	}
+
+	// Move the end of the selection.
+	end := t.selectionEnd
+	moveRows := func(rows int) {
+		row := t.indexRow(end)
+		index := t.index[row]
+		column := runewidth.StringWidth(string(t.strippedLine(t.buffer[index.Line])[t.indexStart(index).pos:end.pos]))
+		if row += rows; row < 0 {
+			row = 0
+		} else if row >= len(t.index) {
+			row = len(t.index) - 1
+		}
+		end = t.positionAtColumn(row, column)
+	}
+	if HitShortcut(event, keys.SelectUp) {
+		moveRows(-1)
+	} else if HitShortcut(event, keys.SelectDown) {
+		moveRows(1)
+	} else if HitShortcut(event, keys.SelectPreviousPage) {
+		moveRows(-t.pageSize)
+	} else if HitShortcut(event, keys.SelectNextPage) {
+		moveRows(t.pageSize)
+	} else if HitShortcut(event, keys.SelectFirst) {
+		end = textViewPosition{}
+	} else if HitShortcut(event, keys.SelectLast) {
+		end = textViewPosition{line: len(t.buffer) - 1, pos: len(t.strippedLine(t.buffer[len(t.buffer)-1]))}
+	} else if HitShortcut(event, keys.SelectLeft) {
+		if end.pos > 0 {
+			iterateStringReverse(string(t.strippedLine(t.buffer[end.line])[:end.pos]), func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth int) bool {
+				end.pos = textPos
+				return true
+			})
+		} else if end.line > 0 {
+			end.line--
+			end.pos = len(t.strippedLine(t.buffer[end.line]))
+		}
+	} else if HitShortcut(event, keys.SelectRight) {
+		if text := t.strippedLine(t.buffer[end.line]); end.pos < len(text) {
+			iterateString(string(text[end.pos:]), func(main rune, comb []rune, textPos, textWidth, screenPos, screenWidth int) bool {
+				end.pos += textWidth
+				return true
+			})
+		} else if end.line < len(t.buffer)-1 {
+			end = textViewPosition{line: end.line + 1}
+		}
+	}
+	t.selectionEnd = end
+
+	// Scroll to the end of the selection.
+	if row := t.indexRow(end); row < t.lineOffset {
+		t.trackEnd = false
+		t.lineOffset = row
+	} else if row >= t.lineOffset+t.pageSize {
+		t.lineOffset = row - t.pageSize + 1
+	}
+	return true
+}
+
+// handleSelectionMouse selects text by dragging the mouse over it. It returns
+// whether the event was consumed and the primitive which captures the mouse.
+// The text view must be locked.
+func (t *TextView) handleSelectionMouse(action MouseAction, event *tcell.EventMouse) (consumed bool, capture Primitive) {
+	x, y := event.Position()
+	switch action {
+	case MouseLeftDown:
+		t.hasSelection = false
+		position, ok := t.positionAt(x, y)
+		if !ok || !t.InRect(x, y) {
+			return false, nil
+		}
+		t.selectionStart, t.selectionEnd = position, position
+		t.hasSelection, t.dragging = true, true
+		return true, t
+	case MouseMove:
+		if !t.dragging || event.Buttons()&tcell.ButtonPrimary == 0 {
+			return false, nil
+		}
+
+		// Scroll when dragging beyond the text view.
+		_, rectY, _, height := t.GetInnerRect()
+		if y < rectY && t.lineOffset > 0 {
+			t.trackEnd = false
+			t.lineOffset--
+		} else if y >= rectY+height && t.lineOffset+height < len(t.index) {
+			t.lineOffset++
+		}
+
+		if position, ok := t.positionAt(x, y); ok {
+			t.selectionEnd = position
+		}
+		return true, t
+	case MouseLeftUp:
+		if !t.dragging {
+			return false, nil
+		}
+		t.dragging = false
+		if t.selectionStart == t.selectionEnd {
+			t.hasSelection = false
+		}
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,563 @@
+package crtview
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// ThemeFormat is a file format a theme may be saved in.
+type ThemeFormat int
+
+// Available theme formats.
+const (
+	ThemeTOML ThemeFormat = iota
+	ThemeJSON
+	ThemeYAML
+)
+
+var (
+	themeColorType = reflect.TypeOf(tcell.Color(0))
+	themeRuneType  = reflect.TypeOf(rune(0))
+)
+
+// themeValue is a scalar value read from a theme file.
+type themeValue struct {
+	text   string
+	quoted bool
+}
+
+// LoadTheme reads a theme from a JSON, TOML or YAML document. The format is
+// detected automatically. Keys are the names of the fields of Theme, border
+// runes are read from a "Borders" table (TOML), mapping (YAML) or object
+// (JSON) with the field names of BorderSet:
+//
+//	TitleColor = "yellow"
+//	BorderColor = "#00ff00"
+//	PrimitiveBackgroundColor = 17
+//
+//	[Borders]
+//	Horizontal = "═"
+//
+// Colors may be specified by name, as "#rrggbb" or as an index of the
+// 256-color palette. "default" leaves the terminal's color unchanged. Runes
+// may be specified as a single character, as "U+2550" or as an unquoted code
+// point.
+//
+// Fields which are not present in the document keep the values of Styles and
+// Borders. An error listing the valid field names is returned when an
+// unknown key is encountered.
+//
+// The returned theme is not applied. Assign it to Styles (and its border runes
+// to Borders) before creating primitives to use it.
+func LoadTheme(r io.Reader) (*Theme, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]interface{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		values, err = parseThemeJSON(trimmed)
+	} else {
+		values, err = parseThemeLines(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	theme := Styles
+	theme.Borders = Borders
+	if err := setThemeFields(reflect.ValueOf(&theme).Elem(), values, ""); err != nil {
+		return nil, err
+	}
+	return &theme, nil
+}
+
+// SaveTheme writes a theme in the given format. The output may be read with
+// LoadTheme.
+func SaveTheme(w io.Writer, theme *Theme, format ThemeFormat) error {
+	entries := themeEntries(reflect.ValueOf(theme).Elem())
+
+	b := bufio.NewWriter(w)
+	switch format {
+	case ThemeTOML:
+		writeThemeTOML(b, entries)
+	case ThemeJSON:
+		writeThemeJSON(b, entries)
+	case ThemeYAML:
+		writeThemeYAML(b, entries)
+	default:
+		return fmt.Errorf("unknown theme format %d", format)
+	}
+	return b.Flush()
+}
+
+// setThemeFields sets the fields of a struct from parsed theme values.
+func setThemeFields(v reflect.Value, values map[string]interface{}, path string) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	t := v.Type()
+	for _, key := range keys {
+		f, ok := t.FieldByName(key)
+		if !ok || f.PkgPath != "" {
+			names := make([]string, 0, t.NumField())
+			for i := 0; i < t.NumField(); i++ {
+				if t.Field(i).PkgPath == "" {
+					names = append(names, t.Field(i).Name)
+				}
+			}
+			return fmt.Errorf("unknown theme field %q, valid fields are: %s", path+key, strings.Join(names, ", "))
+		}
+
+		field := v.FieldByIndex(f.Index)
+		switch value := values[key].(type) {
+		case map[string]interface{}:
+			if field.Kind() != reflect.Struct {
+				return fmt.Errorf("theme field %q must be a value, not a section", path+key)
+			}
+			if err := setThemeFields(field, value, path+key+"."); err != nil {
+				return err
+			}
+		case themeValue:
+			if field.Kind() == reflect.Struct {
+				return fmt.Errorf("theme field %q must be a section", path+key)
+			}
+			if err := setThemeValue(field, value); err != nil {
+				return fmt.Errorf("invalid value for theme field %q: %s", path+key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// setThemeValue sets a single field from a parsed theme value.
+func setThemeValue(field reflect.Value, value themeValue) error {
+	switch field.Type() {
+	case themeColorType:
+		color, err := parseThemeColor(value.text)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(color))
+		return nil
+	case themeRuneType:
+		r, err := parseThemeRune(value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(r))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.Int:
+		i, err := strconv.Atoi(value.text)
+		if err != nil {
+			return fmt.Errorf("expected an integer, got %q", value.text)
+		}
+		field.SetInt(int64(i))
+	case reflect.String:
+		field.SetString(value.text)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
+
+// parseThemeColor parses a color name, a "#rrggbb" value or a palette index.
+func parseThemeColor(text string) (tcell.Color, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" || text == "default" {
+		return tcell.ColorDefault, nil
+	}
+
+	if index, err := strconv.Atoi(text); err == nil {
+		if index < 0 || index > 255 {
+			return tcell.ColorDefault, fmt.Errorf("palette index %d out of range 0-255", index)
+		}
+		return tcell.PaletteColor(index), nil
+	}
+
+	color := tcell.GetColor(text)
+	if color == tcell.ColorDefault {
+		return tcell.ColorDefault, fmt.Errorf("unknown color %q", text)
+	}
+	return color, nil
+}
+
+// parseThemeRune parses a single character, a "U+XXXX" value or an unquoted
+// code point.
+func parseThemeRune(value themeValue) (rune, error) {
+	text := value.text
+	if !value.quoted {
+		if i, err := strconv.Atoi(text); err == nil {
+			return rune(i), nil
+		}
+	}
+	if len(text) > 2 && (text[:2] == "U+" || text[:2] == "u+") {
+		if i, err := strconv.ParseInt(text[2:], 16, 32); err == nil {
+			return rune(i), nil
+		}
+	}
+	if utf8.RuneCountInString(text) == 1 {
+		r, _ := utf8.DecodeRuneInString(text)
+		return r, nil
+	}
+	return 0, fmt.Errorf("expected a single character, got %q", text)
+}
+
+// parseThemeJSON parses a JSON theme document.
+func parseThemeJSON(data []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var raw map[string]interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to parse theme: %s", err)
+	}
+	return convertThemeJSON(raw)
+}
+
+// convertThemeJSON converts decoded JSON values into theme values.
+func convertThemeJSON(raw map[string]interface{}) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(raw))
+	for key, value := range raw {
+		switch value := value.(type) {
+		case string:
+			values[key] = themeValue{text: value, quoted: true}
+		case json.Number:
+			values[key] = themeValue{text: value.String()}
+		case map[string]interface{}:
+			section, err := convertThemeJSON(value)
+			if err != nil {
+				return nil, err
+			}
+			values[key] = section
+		default:
+			return nil, fmt.Errorf("invalid value for theme field %q: expected a string or a number", key)
+		}
+	}
+	return values, nil
+}
+
+// parseThemeLines parses a TOML or YAML theme document. Only flat key/value
+// pairs and a single level of tables (TOML) or mappings (YAML) are supported.
+func parseThemeLines(data []byte) (map[string]interface{}, error) {
+	var (
+		values  = make(map[string]interface{})
+		current = values
+		table   bool
+		yaml    bool
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := stripThemeComment(scanner.Text())
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+
+		// TOML table.
+		if trimmed[0] == '[' {
+			if trimmed[len(trimmed)-1] != ']' {
+				return nil, fmt.Errorf("failed to parse theme: line %d: invalid table header", lineNumber)
+			}
+			name := unquoteThemeKey(strings.TrimSpace(trimmed[1 : len(trimmed)-1]))
+			if _, ok := values[name]; ok {
+				return nil, fmt.Errorf("failed to parse theme: line %d: duplicate key %q", lineNumber, name)
+			}
+			current = make(map[string]interface{})
+			values[name] = current
+			table = true
+			continue
+		}
+
+		// Unindented lines end YAML mappings.
+		if yaml && !unicode.IsSpace(rune(line[0])) {
+			current = values
+			yaml = false
+		}
+
+		separator := indexThemeSeparator(trimmed)
+		if separator < 0 {
+			return nil, fmt.Errorf("failed to parse theme: line %d: expected key and value", lineNumber)
+		}
+		key := unquoteThemeKey(strings.TrimSpace(trimmed[:separator]))
+		text := strings.TrimSpace(trimmed[separator+1:])
+		if _, ok := current[key]; ok {
+			return nil, fmt.Errorf("failed to parse theme: line %d: duplicate key %q", lineNumber, key)
+		}
+
+		// YAML mapping.
+		if text == "" && trimmed[separator] == ':' && !table {
+			current = make(map[string]interface{})
+			values[key] = current
+			yaml = true
+			continue
+		}
+
+		value, err := parseThemeScalar(text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse theme: line %d: %s", lineNumber, err)
+		}
+		current[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// parseThemeScalar parses a quoted or bare TOML or YAML value.
+func parseThemeScalar(text string) (themeValue, error) {
+	if text == "" {
+		return themeValue{}, fmt.Errorf("missing value")
+	}
+
+	switch text[0] {
+	case '"':
+		s, err := strconv.Unquote(text)
+		if err != nil {
+			return themeValue{}, fmt.Errorf("invalid string %s", text)
+		}
+		return themeValue{text: s, quoted: true}, nil
+	case '\'':
+		if len(text) < 2 || text[len(text)-1] != '\'' {
+			return themeValue{}, fmt.Errorf("invalid string %s", text)
+		}
+		return themeValue{text: strings.Replace(text[1:len(text)-1], "''", "'", -1), quoted: true}, nil
+	}
+	return themeValue{text: text}, nil
+}
+
+// unquoteThemeKey removes the quotes around a key, if any.
+func unquoteThemeKey(key string) string {
+	if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') && key[len(key)-1] == key[0] {
+		return key[1 : len(key)-1]
+	}
+	return key
+}
+
+// indexThemeSeparator returns the index of the first '=' or ':' outside of
+// quotes, or -1 if there is none.
+func indexThemeSeparator(line string) int {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '=' || c == ':':
+			return i
+		}
+	}
+	return -1
+}
+
+// stripThemeComment removes a '#' comment outside of quotes.
+func stripThemeComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// themeEntry is a field of a theme prepared for writing.
+type themeEntry struct {
+	key     string
+	value   string
+	section []themeEntry
+}
+
+// themeEntries returns the encoded fields of a struct in declaration order.
+func themeEntries(v reflect.Value) []themeEntry {
+	t := v.Type()
+	entries := make([]themeEntry, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath != "" {
+			continue
+		}
+
+		field := v.Field(i)
+		entry := themeEntry{key: t.Field(i).Name}
+		switch {
+		case field.Type() == themeColorType:
+			entry.value = formatThemeColor(field.Interface().(tcell.Color))
+		case field.Type() == themeRuneType:
+			entry.value = quoteThemeString(string(field.Interface().(rune)))
+		case field.Kind() == reflect.Int:
+			entry.value = strconv.Itoa(int(field.Int()))
+		case field.Kind() == reflect.String:
+			entry.value = quoteThemeString(field.String())
+		case field.Kind() == reflect.Struct:
+			entry.section = themeEntries(field)
+		default:
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// formatThemeColor encodes a color as "#rrggbb", as a palette index or as
+// "default".
+func formatThemeColor(color tcell.Color) string {
+	if color == tcell.ColorDefault || !color.Valid() {
+		return `"default"`
+	} else if color.IsRGB() {
+		return fmt.Sprintf(`"#%06x"`, color.Hex())
+	}
+	return strconv.Itoa(int(color - tcell.ColorValid))
+}
+
+// quoteThemeString returns a double-quoted string which is valid in TOML,
+// JSON and YAML.
+func quoteThemeString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case !unicode.IsPrint(r) && r <= 0xFFFF:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		case !unicode.IsPrint(r):
+			fmt.Fprintf(&b, `\U%08x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func writeThemeTOML(w io.Writer, entries []themeEntry) {
+	// Tables must follow all top-level keys.
+	for _, entry := range entries {
+		if entry.section == nil {
+			fmt.Fprintf(w, "%s = %s\n", entry.key, entry.value)
+		}
+	}
+	for _, entry := range entries {
+		if entry.section == nil {
+			continue
+		}
+		fmt.Fprintf(w, "\n[%s]\n", entry.key)
+		for _, sectionEntry := range entry.section {
+			fmt.Fprintf(w, "%s = %s\n", sectionEntry.key, sectionEntry.value)
+		}
+	}
+}
+
+func writeThemeJSON(w io.Writer, entries []themeEntry) {
+	var write func(entries []themeEntry, indent string)
+	write = func(entries []themeEntry, indent string) {
+		fmt.Fprint(w, "{\n")
+		for i, entry := range entries {
+			fmt.Fprintf(w, "%s  %q: ", indent, entry.key)
+			if entry.section != nil {
+				write(entry.section, indent+"  ")
+			} else {
+				fmt.Fprint(w, entry.value)
+			}
+			if i < len(entries)-1 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprint(w, "\n")
+		}
+		fmt.Fprintf(w, "%s}", indent)
+	}
+	write(entries, "")
+	fmt.Fprint(w, "\n")
+}
+
+func writeThemeYAML(w io.Writer, entries []themeEntry) {
+	for _, entry := range entries {
+		if entry.section == nil {
+			fmt.Fprintf(w, "%s: %s\n", entry.key, entry.value)
+			continue
+		}
+		fmt.Fprintf(w, "%s:\n", entry.key)
+		for _, sectionEntry := range entry.section {
+			fmt.Fprintf(w, "  %s: %s\n", sectionEntry.key, sectionEntry.value)
+		}
+	}
+}
+
+// Themable is implemented by primitives which can apply a theme after they
+// were created. See Application.SetTheme.
+type Themable interface {
+	// ApplyTheme replaces the colors of the previous theme with the colors of
+	// the new theme. Colors which differ from the previous theme were set
+	// explicitly and are kept. Containers apply the theme to the primitives
+	// they contain.
+	ApplyTheme(previous, theme *Theme)
+}
+
+// ApplyTheme applies a theme to a primitive if it implements Themable. See
+// Themable.ApplyTheme.
+func ApplyTheme(p Primitive, previous, theme *Theme) {
+	if t, ok := p.(Themable); ok && !isNilPrimitive(p) {
+		t.ApplyTheme(previous, theme)
+	}
+}
+
+// isNilPrimitive returns whether a primitive is nil or a typed nil pointer.
+func isNilPrimitive(p Primitive) bool {
+	if p == nil {
+		return true
+	}
+	v := reflect.ValueOf(p)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// themeColor replaces a color with the new theme's color if it matches the
+// previous theme's color.
+func themeColor(color *tcell.Color, previous, theme tcell.Color) {
+	if *color == previous {
+		*color = theme
+	}
+}
+
+// themeRune replaces a rune with the new theme's rune if it matches the
+// previous theme's rune.
+func themeRune(r *rune, previous, theme rune) {
+	if *r == previous {
+		*r = theme
+	}
+}
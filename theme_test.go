@@ -0,0 +1,167 @@
+package crtview
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+var themeTestDocuments = map[string]string{
+	"TOML": `
+# Site colors
+TitleColor = "yellow"
+BorderColor = "#00ff00" # Green
+PrimitiveBackgroundColor = 17
+DropDownAbbreviationChars = '~'
+
+[Borders]
+Horizontal = "═"
+Vertical = "U+2551"
+`,
+	"YAML": `
+# Site colors
+TitleColor: yellow
+BorderColor: "#00ff00" # Green
+PrimitiveBackgroundColor: 17
+DropDownAbbreviationChars: '~'
+Borders:
+  Horizontal: "═"
+  Vertical: U+2551
+`,
+	"JSON": `{
+	"TitleColor": "yellow",
+	"BorderColor": "#00ff00",
+	"PrimitiveBackgroundColor": 17,
+	"DropDownAbbreviationChars": "~",
+	"Borders": {
+		"Horizontal": "═",
+		"Vertical": "U+2551"
+	}
+}`,
+}
+
+func TestLoadTheme(t *testing.T) {
+	t.Parallel()
+
+	for format, document := range themeTestDocuments {
+		format, document := format, document // Capture
+
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+
+			theme, err := LoadTheme(strings.NewReader(document))
+			if err != nil {
+				t.Fatalf("failed to load theme: %s", err)
+			}
+
+			if theme.TitleColor != tcell.ColorYellow {
+				t.Errorf("failed to load color name: expected %v, got %v", tcell.ColorYellow, theme.TitleColor)
+			}
+			if theme.BorderColor != tcell.NewHexColor(0x00ff00) {
+				t.Errorf("failed to load hex color: expected #00ff00, got %06x", theme.BorderColor.Hex())
+			}
+			if theme.PrimitiveBackgroundColor != tcell.PaletteColor(17) {
+				t.Errorf("failed to load palette color: expected 17, got %v", theme.PrimitiveBackgroundColor)
+			}
+			if theme.DropDownAbbreviationChars != "~" {
+				t.Errorf("failed to load string: expected ~, got %s", theme.DropDownAbbreviationChars)
+			}
+			if theme.Borders.Horizontal != '═' || theme.Borders.Vertical != '║' {
+				t.Errorf("failed to load borders: expected ═ and ║, got %c and %c", theme.Borders.Horizontal, theme.Borders.Vertical)
+			}
+
+			// Fields which are not present keep their values.
+			if theme.SecondaryTextColor != Styles.SecondaryTextColor {
+				t.Errorf("failed to keep default color: expected %v, got %v", Styles.SecondaryTextColor, theme.SecondaryTextColor)
+			}
+			if theme.Borders.TopLeft != Borders.TopLeft {
+				t.Errorf("failed to keep default border: expected %c, got %c", Borders.TopLeft, theme.Borders.TopLeft)
+			}
+		})
+	}
+}
+
+func TestLoadThemeErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range []struct {
+		document string
+		expected string
+	}{
+		{`TitleColour = "red"`, `unknown theme field "TitleColour", valid fields are: TitleColor, BorderColor`},
+		{"[Borders]\nHorizontl = \"-\"", `unknown theme field "Borders.Horizontl", valid fields are: Horizontal, Vertical`},
+		{`TitleColor = "notacolor"`, `unknown color "notacolor"`},
+		{`TitleColor = 256`, `palette index 256 out of range`},
+		{`WindowMinWidth = "wide"`, `expected an integer`},
+		{`TitleColor`, `line 1: expected key and value`},
+	} {
+		_, err := LoadTheme(strings.NewReader(c.document))
+		if err == nil {
+			t.Errorf("failed to reject %q: expected error", c.document)
+		} else if !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("unexpected error for %q: expected %q, got %q", c.document, c.expected, err)
+		}
+	}
+}
+
+func TestSaveTheme(t *testing.T) {
+	t.Parallel()
+
+	theme := Styles
+	theme.TitleColor = tcell.ColorDefault
+	theme.ShadowColor = tcell.PaletteColor(236)
+	theme.DropDownAbbreviationChars = `"\`
+	theme.Borders.Horizontal = '#'
+
+	for _, format := range []ThemeFormat{ThemeTOML, ThemeJSON, ThemeYAML} {
+		var b bytes.Buffer
+		if err := SaveTheme(&b, &theme, format); err != nil {
+			t.Fatalf("failed to save theme in format %d: %s", format, err)
+		}
+
+		loaded, err := LoadTheme(&b)
+		if err != nil {
+			t.Fatalf("failed to load theme saved in format %d: %s", format, err)
+		}
+		if *loaded != theme {
+			t.Errorf("failed to round-trip theme in format %d: expected %+v, got %+v", format, theme, *loaded)
+		}
+	}
+}
+
+func TestApplyTheme(t *testing.T) {
+	t.Parallel()
+
+	previous, theme := &DarkTheme, &LightTheme
+
+	themed := NewBox()
+	overridden := NewBox()
+	overridden.SetBackgroundColor(tcell.ColorRed)
+
+	table := NewTable()
+	table.SetCell(0, 0, &TableCell{Color: previous.PrimaryTextColor})
+	table.SetCell(0, 1, &TableCell{Color: tcell.ColorRed})
+
+	flex := NewFlex()
+	flex.AddItem(themed, 0, 1, false)
+	flex.AddItem(overridden, 0, 1, false)
+	flex.AddItem(table, 0, 1, false)
+	flex.AddItem(nil, 0, 1, false)
+
+	ApplyTheme(flex, previous, theme)
+
+	if bg := themed.GetBackgroundColor(); bg != theme.PrimitiveBackgroundColor {
+		t.Errorf("failed to apply theme: expected background %v, got %v", theme.PrimitiveBackgroundColor, bg)
+	}
+	if bg := overridden.GetBackgroundColor(); bg != tcell.ColorRed {
+		t.Errorf("failed to keep explicit color: expected background %v, got %v", tcell.ColorRed, bg)
+	}
+	if c := table.GetCell(0, 0).Color; c != theme.PrimaryTextColor {
+		t.Errorf("failed to apply theme to table cell: expected %v, got %v", theme.PrimaryTextColor, c)
+	}
+	if c := table.GetCell(0, 1).Color; c != tcell.ColorRed {
+		t.Errorf("failed to keep explicit table cell color: expected %v, got %v", tcell.ColorRed, c)
+	}
+}
@@ -0,0 +1,702 @@
+package crtview
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// SetCells sets the cells shown right of the node when it is displayed in a
+// TreeTable, one per column starting at the second column.
+func (n *TreeNode) SetCells(cells ...*TableCell) {
+	n.Lock()
+	defer n.Unlock()
+
+	n.cells = cells
+	n.changes++
+}
+
+// SetCellsSimple calls SetCells() with cells with the given texts.
+func (n *TreeNode) SetCellsSimple(texts ...string) {
+	cells := make([]*TableCell, len(texts))
+	for i, text := range texts {
+		cells[i] = NewTableCell(text)
+	}
+	n.SetCells(cells...)
+}
+
+// GetCells returns the cells shown right of the node in a TreeTable.
+func (n *TreeNode) GetCells() []*TableCell {
+	n.RLock()
+	defer n.RUnlock()
+
+	return n.cells
+}
+
+// TreeTable displays a tree of nodes (see TreeNode) in a table. The first
+// column shows the tree structure and the texts of the nodes, further columns
+// show the cells set with TreeNode.SetCells(). Column headers set with
+// SetHeaders() are drawn in a fixed row.
+//
+// The tree is navigated like a TreeView. In addition, the following keys
+// expand and collapse nodes:
+//
+//   - l, right arrow: Expand the current node, or move to its first child.
+//   - h, left arrow: Collapse the current node, or move to its parent.
+//   - Space, double-click: Expand or collapse the current node.
+//
+// Clicking a column header sorts the child nodes of each node by that column.
+// The table which draws the tree is returned by GetTable(). It may be used to
+// set borders, colors, column comparators and the column layout, but its
+// content, input and mouse capture functions and selection handlers must not
+// be replaced.
+type TreeTable struct {
+	*Box
+
+	// The table which draws the tree.
+	table *Table
+
+	// The root node.
+	root *TreeNode
+
+	// The currently selected node or nil if no node is selected.
+	currentNode *TreeNode
+
+	// The level of the nodes which are shown on the top level.
+	topLevel int
+
+	// The cells of the header row, if any.
+	headers []*TableCell
+
+	// If set to true, the tree structure is drawn using lines.
+	graphics bool
+
+	// The color of the lines.
+	graphicsColor tcell.Color
+
+	// Strings drawn before the nodes, based on their level.
+	prefixes [][]byte
+
+	// The visible nodes, top-down, the cells of the first column showing them
+	// and the number of columns, as set by refresh().
+	nodes       []*TreeNode
+	treeCells   []*TableCell
+	columnCount int
+
+	// The nodes which were traversed when the visible nodes were determined
+	// and their number of changes at that time.
+	traversed        []*TreeNode
+	traversedChanges []uint64
+
+	// An optional function called when the focused tree item changes.
+	changed func(node *TreeNode)
+
+	// An optional function called when a tree item is selected.
+	selected func(node *TreeNode)
+
+	sync.RWMutex
+}
+
+// NewTreeTable returns a new tree table.
+func NewTreeTable() *TreeTable {
+	t := &TreeTable{
+		Box:           NewBox(),
+		table:         NewTable(),
+		graphics:      true,
+		graphicsColor: Styles.GraphicsColor,
+	}
+	t.focus = t
+
+	t.table.SetContent(&treeTableContent{tree: t})
+	t.table.SetSelectable(true, false)
+	t.table.SetSelectionChangedFunc(t.selectionChanged)
+	t.table.SetSelectedFunc(t.nodeSelected)
+	return t
+}
+
+// GetTable returns the table which draws the tree.
+func (t *TreeTable) GetTable() *Table {
+	return t.table
+}
+
+// SetRoot sets the root node of the tree.
+func (t *TreeTable) SetRoot(root *TreeNode) {
+	t.Lock()
+	t.root = root
+	t.Unlock()
+
+	t.refresh()
+}
+
+// GetRoot returns the root node of the tree. If no such node was previously
+// set, nil is returned.
+func (t *TreeTable) GetRoot() *TreeNode {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.root
+}
+
+// SetCurrentNode selects a node. If the node is not visible, its closest
+// visible ancestor is selected.
+//
+// This function does NOT trigger the "changed" callback.
+func (t *TreeTable) SetCurrentNode(node *TreeNode) {
+	t.Lock()
+	t.currentNode = node
+	t.Unlock()
+
+	t.refresh()
+}
+
+// GetCurrentNode returns the currently selected node or nil of no node is
+// currently selected.
+func (t *TreeTable) GetCurrentNode() *TreeNode {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.currentNode
+}
+
+// SetHeaders sets the texts of the column headers, which are drawn in a fixed
+// row above the nodes. Provide no texts to remove the header row.
+func (t *TreeTable) SetHeaders(headers ...string) {
+	t.Lock()
+	t.headers = make([]*TableCell, len(headers))
+	for i, header := range headers {
+		t.headers[i] = NewTableCell(header).
+			SetTextColor(Styles.SecondaryTextColor).
+			SetSelectable(false)
+	}
+	fixedRows := t.headerRows()
+	t.Unlock()
+
+	t.table.SetFixed(fixedRows, 0)
+	t.refresh()
+}
+
+// SetTopLevel sets the first tree level that is visible with 0 referring to the
+// root, 1 to the root's child nodes, and so on. Nodes above the top level are
+// not displayed.
+func (t *TreeTable) SetTopLevel(topLevel int) {
+	t.Lock()
+	t.topLevel = topLevel
+	t.Unlock()
+
+	t.refresh()
+}
+
+// SetPrefixes defines the strings drawn before the nodes' texts. This is a
+// slice of strings where each element corresponds to a node's hierarchy level,
+// i.e. 0 for the root, 1 for the root's children, and so on (levels will
+// cycle).
+func (t *TreeTable) SetPrefixes(prefixes []string) {
+	t.Lock()
+	t.prefixes = make([][]byte, len(prefixes))
+	for i := range prefixes {
+		t.prefixes[i] = []byte(prefixes[i])
+	}
+	t.Unlock()
+
+	t.refresh()
+}
+
+// SetGraphics sets a flag which determines whether or not line graphics are
+// drawn to illustrate the tree's hierarchy. Without graphics, nodes are
+// indented by two spaces per level.
+func (t *TreeTable) SetGraphics(showGraphics bool) {
+	t.Lock()
+	t.graphics = showGraphics
+	t.Unlock()
+
+	t.refresh()
+}
+
+// SetGraphicsColor sets the colors of the lines used to draw the tree structure.
+func (t *TreeTable) SetGraphicsColor(color tcell.Color) {
+	t.Lock()
+	t.graphicsColor = color
+	t.Unlock()
+
+	t.refresh()
+}
+
+// SetChangedFunc sets the function which is called when the user navigates to
+// a new tree node.
+func (t *TreeTable) SetChangedFunc(handler func(node *TreeNode)) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.changed = handler
+}
+
+// SetSelectedFunc sets the function which is called when the user selects a
+// node by pressing Enter on it.
+func (t *TreeTable) SetSelectedFunc(handler func(node *TreeNode)) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.selected = handler
+}
+
+// GetRowCount returns the number of visible nodes.
+func (t *TreeTable) GetRowCount() int {
+	t.RLock()
+	defer t.RUnlock()
+
+	return len(t.nodes)
+}
+
+// headerRows returns the number of header rows.
+func (t *TreeTable) headerRows() int {
+	if len(t.headers) == 0 {
+		return 0
+	}
+	return 1
+}
+
+// nodeAt returns the node shown in the given row of the table, or nil.
+func (t *TreeTable) nodeAt(row int) *TreeNode {
+	row -= t.headerRows()
+	if row < 0 || row >= len(t.nodes) {
+		return nil
+	}
+	return t.nodes[row]
+}
+
+// refresh determines the visible nodes and selects the row of the current
+// node, or of its closest visible ancestor. The tree table and its table must
+// not be locked.
+func (t *TreeTable) refresh() {
+	t.Lock()
+	t.buildNodes()
+
+	// Find the row of the current node.
+	row := -1
+	for node := t.currentNode; node != nil && row < 0; node = node.parent {
+		for index, visible := range t.nodes {
+			if visible == node && visible.selectable {
+				row, t.currentNode = index+t.headerRows(), visible
+				break
+			}
+		}
+	}
+	if row < 0 {
+		t.currentNode = nil
+		for index, node := range t.nodes {
+			if node.selectable {
+				row, t.currentNode = index+t.headerRows(), node
+				break
+			}
+		}
+	}
+	t.Unlock()
+
+	if selected, _ := t.table.GetSelection(); row >= 0 && selected != row {
+		t.table.Select(row, 0)
+	}
+}
+
+// buildNodes determines the visible nodes and the number of columns.
+func (t *TreeTable) buildNodes() {
+	t.nodes, t.treeCells = nil, nil
+	t.traversed, t.traversedChanges = nil, nil
+	t.columnCount = len(t.headers)
+	if t.root != nil {
+		t.addNode(t.root, nil, 0, nil, true)
+	}
+}
+
+// addNode adds a node and its visible descendants to the visible nodes. The
+// graphics are the lines drawn left of the node's children, and last is
+// whether the node is the last child of its parent.
+func (t *TreeTable) addNode(node, parent *TreeNode, level int, graphics []byte, last bool) {
+	node.Lock()
+	node.parent, node.level = parent, level
+	children := node.children
+	text, color, indent := node.text, node.color, node.indent
+	selectable, expanded := node.selectable, node.expanded
+	if len(node.cells)+1 > t.columnCount {
+		t.columnCount = len(node.cells) + 1
+	}
+	t.traversed = append(t.traversed, node)
+	t.traversedChanges = append(t.traversedChanges, node.changes)
+	node.Unlock()
+
+	var childGraphics []byte
+	if level >= t.topLevel {
+		var lines []byte
+		if t.graphics && level > t.topLevel {
+			lines = append(lines, graphics...)
+			if last {
+				lines = append(lines, []byte(string(Borders.BottomLeft)+string(Borders.Horizontal))...)
+				childGraphics = append(append(childGraphics, graphics...), "  "...)
+			} else {
+				lines = append(lines, []byte(string(Borders.LeftT)+string(Borders.Horizontal))...)
+				childGraphics = append(append(childGraphics, graphics...), []byte(string(Borders.Vertical)+" ")...)
+			}
+		} else if level > t.topLevel {
+			lines = append(lines, graphics...)
+			childGraphics = append(append(childGraphics, graphics...), "  "...)
+		}
+
+		var buffer bytes.Buffer
+		if len(lines) > 0 {
+			if hex := t.graphicsColor.Hex(); t.graphics && hex >= 0 {
+				fmt.Fprintf(&buffer, "[#%06x]%s[-]", hex, lines)
+			} else {
+				buffer.Write(lines)
+			}
+		}
+		buffer.Write(bytes.Repeat([]byte(" "), indent))
+		if len(t.prefixes) > 0 {
+			buffer.Write(t.prefixes[(level-t.topLevel)%len(t.prefixes)])
+		}
+		buffer.WriteString(text)
+
+		cell := NewTableCell(buffer.String())
+		cell.Color = color
+		cell.NotSelectable = !selectable
+		t.nodes = append(t.nodes, node)
+		t.treeCells = append(t.treeCells, cell)
+	}
+
+	if expanded || level < t.topLevel {
+		for index, child := range children {
+			t.addNode(child, node, level+1, childGraphics, index == len(children)-1)
+		}
+	}
+}
+
+// hasChildren returns whether a node has child nodes or loads them when it is
+// expanded.
+func hasChildren(node *TreeNode) bool {
+	node.RLock()
+	defer node.RUnlock()
+
+	return len(node.children) > 0 || (node.load != nil && !node.loaded)
+}
+
+// selectionChanged is called when the table selects another row.
+func (t *TreeTable) selectionChanged(row, column int) {
+	t.Lock()
+	node := t.nodeAt(row)
+	if node == nil || node == t.currentNode {
+		t.Unlock()
+		return
+	}
+	t.currentNode = node
+	changed := t.changed
+	t.Unlock()
+
+	if changed != nil {
+		changed(node)
+	}
+	if node.focused != nil {
+		node.focused()
+	}
+}
+
+// nodeSelected is called when the user selects a row of the table.
+func (t *TreeTable) nodeSelected(row, column int) {
+	t.RLock()
+	node, selected := t.nodeAt(row), t.selected
+	t.RUnlock()
+	if node == nil {
+		return
+	}
+
+	if selected != nil {
+		selected(node)
+	}
+	if node.focused != nil {
+		node.focused()
+	}
+	if node.selected != nil {
+		node.selected()
+	}
+}
+
+// nodesChanged returns whether one of the nodes traversed when the visible
+// nodes were determined has changed since.
+func (t *TreeTable) nodesChanged() bool {
+	t.RLock()
+	defer t.RUnlock()
+
+	for index, node := range t.traversed {
+		node.RLock()
+		changes := node.changes
+		node.RUnlock()
+		if changes != t.traversedChanges[index] {
+			return true
+		}
+	}
+	return false
+}
+
+// Draw draws this primitive onto the screen.
+func (t *TreeTable) Draw(screen tcell.Screen) {
+	if !t.IsVisible() {
+		return
+	}
+
+	t.Box.Draw(screen)
+
+	// Determine the visible nodes again if nodes have changed.
+	if t.nodesChanged() {
+		t.refresh()
+	}
+
+	t.table.SetRect(t.GetInnerRect())
+	t.table.Draw(screen)
+}
+
+// Focus is called when this primitive receives focus.
+func (t *TreeTable) Focus(delegate func(p Primitive)) {
+	t.Box.Focus(delegate)
+	t.table.Focus(delegate)
+}
+
+// Blur is called when this primitive loses focus.
+func (t *TreeTable) Blur() {
+	t.table.Blur()
+	t.Box.Blur()
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (t *TreeTable) HasFocus() bool {
+	return t.table.HasFocus()
+}
+
+// tableFocus returns a function which sets the focus to the tree table instead
+// of its table.
+func (t *TreeTable) tableFocus(setFocus func(p Primitive)) func(p Primitive) {
+	if setFocus == nil {
+		return nil
+	}
+	return func(p Primitive) {
+		if p == t.table {
+			p = t
+		}
+		setFocus(p)
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (t *TreeTable) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return t.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		keys := t.GetKeys()
+
+		t.RLock()
+		node := t.currentNode
+		t.RUnlock()
+
+		if node == nil || t.table.IsEditing() {
+			t.table.InputHandler()(event, t.tableFocus(setFocus))
+			return
+		}
+
+		if HitShortcut(event, keys.MoveRight, keys.MoveRight2) {
+			if !node.IsExpanded() && hasChildren(node) {
+				node.Expand()
+			} else if children := node.GetChildren(); node.IsExpanded() && len(children) > 0 {
+				t.SetCurrentNode(children[0])
+				t.notifyChanged(node)
+			}
+		} else if HitShortcut(event, keys.MoveLeft, keys.MoveLeft2) {
+			t.RLock()
+			parent := node.parent
+			topLevel := t.topLevel
+			t.RUnlock()
+			if node.IsExpanded() && hasChildren(node) {
+				node.Collapse()
+			} else if parent != nil && node.level > topLevel {
+				t.SetCurrentNode(parent)
+				t.notifyChanged(node)
+			}
+		} else if HitShortcut(event, keys.Select2) {
+			node.SetExpanded(!node.IsExpanded())
+		} else {
+			t.table.InputHandler()(event, t.tableFocus(setFocus))
+			return
+		}
+
+		t.refresh()
+	})
+}
+
+// notifyChanged calls the changed handler if the current node is not the
+// given node.
+func (t *TreeTable) notifyChanged(previous *TreeNode) {
+	t.RLock()
+	node, changed := t.currentNode, t.changed
+	t.RUnlock()
+	if node == nil || node == previous {
+		return
+	}
+
+	if changed != nil {
+		changed(node)
+	}
+	if node.focused != nil {
+		node.focused()
+	}
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (t *TreeTable) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return t.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if action == MouseLeftDoubleClick && t.InRect(event.Position()) && !t.table.IsEditing() {
+			t.table.RLock()
+			row, _ := t.table.cellAt(event.Position())
+			if row >= 0 {
+				row = t.table.contentRow(row)
+			}
+			t.table.RUnlock()
+
+			t.RLock()
+			node := t.nodeAt(row)
+			t.RUnlock()
+			if node != nil {
+				node.SetExpanded(!node.IsExpanded())
+				t.refresh()
+				return true, nil
+			}
+		}
+
+		consumed, capture = t.table.MouseHandler()(action, event, t.tableFocus(setFocus))
+		if capture == t.table {
+			capture = t
+		}
+		return
+	})
+}
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme, including the colors of the headers, the table and all nodes.
+// Colors which were set explicitly are kept.
+func (t *TreeTable) ApplyTheme(previous, theme *Theme) {
+	t.Box.ApplyTheme(previous, theme)
+
+	t.Lock()
+	themeColor(&t.graphicsColor, previous.GraphicsColor, theme.GraphicsColor)
+	for _, header := range t.headers {
+		themeColor(&header.Color, previous.SecondaryTextColor, theme.SecondaryTextColor)
+	}
+	root := t.root
+	t.Unlock()
+
+	t.table.ApplyTheme(previous, theme)
+	if root != nil {
+		root.Walk(func(node, parent *TreeNode) bool {
+			themeColor(&node.color, previous.PrimaryTextColor, theme.PrimaryTextColor)
+			for _, cell := range node.cells {
+				themeColor(&cell.Color, previous.PrimaryTextColor, theme.PrimaryTextColor)
+			}
+			return true
+		})
+	}
+	t.refresh()
+}
+
+// treeTableContent is the content of the table of a TreeTable.
+type treeTableContent struct {
+	tree *TreeTable
+}
+
+// GetCell returns the header cell, the cell showing a node or a cell of the
+// node.
+func (c *treeTableContent) GetCell(row, column int) *TableCell {
+	t := c.tree
+	t.RLock()
+	defer t.RUnlock()
+
+	if row < t.headerRows() {
+		if column < len(t.headers) {
+			return t.headers[column]
+		}
+		return nil
+	}
+	row -= t.headerRows()
+	if row >= len(t.nodes) || column < 0 {
+		return nil
+	} else if column == 0 {
+		return t.treeCells[row]
+	}
+	cells := t.nodes[row].GetCells()
+	if column-1 < len(cells) {
+		return cells[column-1]
+	}
+	return nil
+}
+
+// GetRowCount returns the number of header rows and visible nodes.
+func (c *treeTableContent) GetRowCount() int {
+	t := c.tree
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.headerRows() + len(t.nodes)
+}
+
+// GetColumnCount returns the number of columns.
+func (c *treeTableContent) GetColumnCount() int {
+	t := c.tree
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.columnCount
+}
+
+// sortBy sorts the child nodes of each node.
+func (c *treeTableContent) sortBy(keys []TableSortKey, compare func(column int, a, b *TableCell) int) {
+	t := c.tree
+	t.RLock()
+	root := t.root
+	t.RUnlock()
+	if root == nil {
+		return
+	}
+
+	cellAt := func(node *TreeNode, column int) *TableCell {
+		if column == 0 {
+			return NewTableCell(node.GetText())
+		}
+		cells := node.GetCells()
+		if column-1 < len(cells) {
+			return cells[column-1]
+		}
+		return nil
+	}
+	var sortChildren func(node *TreeNode)
+	sortChildren = func(node *TreeNode) {
+		node.Lock()
+		sort.SliceStable(node.children, func(i, j int) bool {
+			for _, key := range keys {
+				c := compare(key.Column, cellAt(node.children[i], key.Column), cellAt(node.children[j], key.Column))
+				if key.Descending {
+					c = -c
+				}
+				if c != 0 {
+					return c < 0
+				}
+			}
+			return false
+		})
+		node.changes++
+		children := node.children
+		node.Unlock()
+
+		for _, child := range children {
+			sortChildren(child)
+		}
+	}
+	sortChildren(root)
+
+	t.Lock()
+	t.buildNodes()
+	t.Unlock()
+}
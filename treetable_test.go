@@ -0,0 +1,125 @@
+package crtview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestTreeTable(t *testing.T) {
+	t.Parallel()
+
+	root := NewTreeNode("root")
+	b := NewTreeNode("b")
+	b.SetCellsSimple("2", "x")
+	a := NewTreeNode("a")
+	a.SetCellsSimple("1", "y")
+	child := NewTreeNode("a1")
+	child.SetCellsSimple("3", "z")
+	a.AddChild(child)
+	a.Collapse()
+	root.AddChild(b)
+	root.AddChild(a)
+
+	tr := NewTreeTable()
+	tr.SetHeaders("Name", "Size", "Kind")
+	tr.SetRoot(root)
+	tr.SetTopLevel(1)
+
+	app, err := newTestApp(tr)
+	if err != nil {
+		t.Fatalf("failed to initialize Application: %s", err)
+	}
+	tr.Draw(app.screen)
+
+	if tr.GetRowCount() != 2 {
+		t.Fatalf("failed to draw TreeTable: incorrect row count: expected 2, got %d", tr.GetRowCount())
+	} else if line := screenLine(app.screen, 0); !strings.HasPrefix(line, "Name Size Kind") {
+		t.Errorf("failed to draw headers: got %q", line)
+	} else if line := screenLine(app.screen, 1); !strings.HasPrefix(line, "  b  2    x") {
+		t.Errorf("failed to draw node cells: got %q", line)
+	} else if tr.GetCurrentNode() != b {
+		t.Errorf("failed to select first node: expected b, got %v", tr.GetCurrentNode())
+	}
+
+	var changed, selected *TreeNode
+	tr.SetChangedFunc(func(node *TreeNode) {
+		changed = node
+	})
+	tr.SetSelectedFunc(func(node *TreeNode) {
+		selected = node
+	})
+
+	handler := tr.InputHandler()
+	handler(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone), nil)
+	if tr.GetCurrentNode() != a || changed != a {
+		t.Fatalf("failed to move down: expected node a, got %v", tr.GetCurrentNode())
+	}
+
+	// Expand and move to the child.
+	handler(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone), nil)
+	if !a.IsExpanded() || tr.GetRowCount() != 3 {
+		t.Fatalf("failed to expand node: expected 3 rows, got %d", tr.GetRowCount())
+	}
+	handler(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone), nil)
+	if tr.GetCurrentNode() != child || changed != child {
+		t.Fatalf("failed to move to child: expected node a1, got %v", tr.GetCurrentNode())
+	}
+	tr.Draw(app.screen)
+	if line := screenLine(app.screen, 3); !strings.Contains(line, "a1") || !strings.Contains(line, "z") {
+		t.Errorf("failed to draw child node: got %q", line)
+	}
+
+	handler(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), nil)
+	if selected != child {
+		t.Errorf("failed to select node: expected a1, got %v", selected)
+	}
+
+	// Move to the parent and collapse it.
+	handler(tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModNone), nil)
+	if tr.GetCurrentNode() != a {
+		t.Fatalf("failed to move to parent: expected node a, got %v", tr.GetCurrentNode())
+	}
+	handler(tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModNone), nil)
+	if a.IsExpanded() || tr.GetRowCount() != 2 {
+		t.Errorf("failed to collapse node: expected 2 rows, got %d", tr.GetRowCount())
+	}
+	handler(tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone), nil)
+	if !a.IsExpanded() {
+		t.Errorf("failed to toggle node: expected expanded node")
+	}
+
+	// Sort siblings by size, descending.
+	tr.GetTable().SortBy(TableSortKey{Column: 1, Descending: true})
+	tr.Draw(app.screen)
+	if children := root.GetChildren(); children[0] != b || children[1] != a {
+		t.Errorf("failed to sort nodes: expected b before a")
+	}
+	tr.GetTable().SortBy(TableSortKey{Column: 1})
+	tr.Draw(app.screen)
+	if children := root.GetChildren(); children[0] != a || children[1] != b {
+		t.Errorf("failed to sort nodes: expected a before b")
+	} else if line := screenLine(app.screen, 2); !strings.HasPrefix(line, "└─  a1") {
+		t.Errorf("failed to keep children below their parent: got %q", line)
+	} else if tr.GetCurrentNode() != a {
+		t.Errorf("failed to keep current node: expected a, got %v", tr.GetCurrentNode())
+	}
+
+	// Rows are built again when nodes change.
+	a.AddChild(NewTreeNode("a2"))
+	tr.Draw(app.screen)
+	if count := tr.GetRowCount(); count != 4 {
+		t.Errorf("failed to show added node: expected 4 rows, got %d", count)
+	} else if line := screenLine(app.screen, 3); !strings.HasPrefix(line, "└─  a2") {
+		t.Errorf("failed to draw added node: got %q", line)
+	}
+
+	// Changes of nodes of other trees are ignored.
+	cell := tr.GetTable().GetCell(3, 0)
+	NewTreeNode("other").AddChild(NewTreeNode("x"))
+	tr.Draw(app.screen)
+	if tr.GetTable().GetCell(3, 0) != cell {
+		t.Errorf("failed to ignore other tree: expected rows not to be built again")
+	}
+}
@@ -1,6 +1,7 @@
 package crtview
 
 import (
+	"regexp"
 	"sync"
 
 	"github.com/gdamore/tcell/v2"
@@ -40,12 +41,27 @@ type TreeNode struct {
 	// The additional horizontal indent of this node's text.
 	indent int
 
+	// The cells shown right of the node in a TreeTable.
+	cells []*TableCell
+
+	// Whether or not this node is checked in a checkable tree view.
+	checked bool
+
 	// An optional function which is called when the user focuses this node.
 	focused func()
 
 	// An optional function which is called when the user selects this node.
 	selected func()
 
+	// An optional function which loads the child nodes when this node is
+	// expanded for the first time, and whether it was called.
+	load   func(node *TreeNode)
+	loaded bool
+
+	// The number of changes of this node which change how it is displayed. A
+	// TreeTable builds its rows again when one of its nodes has changed.
+	changes uint64
+
 	// Temporary member variables.
 	parent    *TreeNode // The parent node (nil for the root).
 	level     int       // The hierarchy level (0 for the root, 1 for its children, and so on).
@@ -116,12 +132,20 @@ func (n *TreeNode) GetReference() interface{} {
 	return n.reference
 }
 
-// SetChildren sets this node's child nodes.
+// SetChildren sets this node's child nodes. If this node is checked, its new
+// child nodes are checked as well.
 func (n *TreeNode) SetChildren(childNodes []*TreeNode) {
 	n.Lock()
-	defer n.Unlock()
-
 	n.children = childNodes
+	n.changes++
+	checked := n.checked
+	n.Unlock()
+
+	if checked {
+		for _, child := range childNodes {
+			child.SetChecked(true)
+		}
+	}
 }
 
 // GetText returns this node's text.
@@ -146,14 +170,21 @@ func (n *TreeNode) ClearChildren() {
 	defer n.Unlock()
 
 	n.children = nil
+	n.changes++
 }
 
-// AddChild adds a new child node to this node.
+// AddChild adds a new child node to this node. If this node is checked, the
+// child node is checked as well.
 func (n *TreeNode) AddChild(node *TreeNode) {
 	n.Lock()
-	defer n.Unlock()
-
 	n.children = append(n.children, node)
+	n.changes++
+	checked := n.checked
+	n.Unlock()
+
+	if checked {
+		node.SetChecked(true)
+	}
 }
 
 // SetSelectable sets a flag indicating whether this node can be focused and
@@ -163,6 +194,7 @@ func (n *TreeNode) SetSelectable(selectable bool) {
 	defer n.Unlock()
 
 	n.selectable = selectable
+	n.changes++
 }
 
 // SetFocusedFunc sets the function which is called when the user navigates to
@@ -185,20 +217,94 @@ func (n *TreeNode) SetSelectedFunc(handler func()) {
 	n.selected = handler
 }
 
-// SetExpanded sets whether or not this node's child nodes should be displayed.
-func (n *TreeNode) SetExpanded(expanded bool) {
+// SetLoadFunc sets a function which loads the child nodes of this node when it
+// is expanded for the first time with Expand or SetExpanded. The node is
+// collapsed, so that its children are loaded on demand.
+//
+// Until the function sets the child nodes with SetChildren, the node has a
+// single, non-selectable child showing that the children are being loaded.
+// To load the children in the background, set them from a goroutine with
+// Application.QueueUpdateDraw:
+//
+//	node.SetLoadFunc(func(node *crtview.TreeNode) {
+//		go func() {
+//			children := fetchChildren(node.GetReference())
+//			app.QueueUpdateDraw(func() {
+//				node.SetChildren(children)
+//			})
+//		}()
+//	})
+func (n *TreeNode) SetLoadFunc(load func(node *TreeNode)) {
 	n.Lock()
 	defer n.Unlock()
 
+	n.load = load
+	n.loaded = false
+	n.expanded = false
+	n.changes++
+}
+
+// Reload removes the child nodes of a node with a load function and loads
+// them again if the node is expanded, or when it is expanded the next time.
+func (n *TreeNode) Reload() {
+	n.Lock()
+	if n.load == nil {
+		n.Unlock()
+		return
+	}
+	n.loaded = false
+	n.children = nil
+	load := n.startLoad()
+	n.changes++
+	n.Unlock()
+
+	if load != nil {
+		load(n)
+	}
+}
+
+// IsLoaded returns whether the load function of the node was called. Nodes
+// without a load function are always loaded.
+func (n *TreeNode) IsLoaded() bool {
+	n.RLock()
+	defer n.RUnlock()
+
+	return n.load == nil || n.loaded
+}
+
+// startLoad shows the loading placeholder and returns the load function if
+// the expanded node has not been loaded yet. The caller must call the
+// returned function after unlocking the node.
+func (n *TreeNode) startLoad() func(node *TreeNode) {
+	if !n.expanded || n.load == nil || n.loaded {
+		return nil
+	}
+	n.loaded = true
+
+	placeholder := NewTreeNode("loading…")
+	placeholder.selectable = false
+	placeholder.color = Styles.TertiaryTextColor
+	n.children = []*TreeNode{placeholder}
+
+	return n.load
+}
+
+// SetExpanded sets whether or not this node's child nodes should be displayed.
+func (n *TreeNode) SetExpanded(expanded bool) {
+	n.Lock()
 	n.expanded = expanded
+	load := n.startLoad()
+	n.changes++
+	n.Unlock()
+
+	if load != nil {
+		load(n)
+	}
 }
 
 // Expand makes the child nodes of this node appear.
 func (n *TreeNode) Expand() {
-	n.Lock()
-	defer n.Unlock()
-
-	n.expanded = true
+	n.SetExpanded(true)
 }
 
 // Collapse makes the child nodes of this node disappear.
@@ -207,22 +313,24 @@ func (n *TreeNode) Collapse() {
 	defer n.Unlock()
 
 	n.expanded = false
+	n.changes++
 }
 
-// ExpandAll expands this node and all descendent nodes.
+// ExpandAll expands this node and all descendent nodes. Nodes with a load
+// function are loaded as with Expand.
 func (n *TreeNode) ExpandAll() {
-	n.Walk(func(node, parent *TreeNode) bool {
-		node.expanded = true
-		return true
-	})
+	n.SetExpanded(true)
+	for _, child := range n.GetChildren() {
+		child.ExpandAll()
+	}
 }
 
 // CollapseAll collapses this node and all descendent nodes.
 func (n *TreeNode) CollapseAll() {
-	n.Walk(func(node, parent *TreeNode) bool {
-		n.expanded = false
-		return true
-	})
+	n.Collapse()
+	for _, child := range n.GetChildren() {
+		child.CollapseAll()
+	}
 }
 
 // IsExpanded returns whether the child nodes of this node are visible.
@@ -239,6 +347,7 @@ func (n *TreeNode) SetText(text string) {
 	defer n.Unlock()
 
 	n.text = text
+	n.changes++
 }
 
 // GetColor returns the node's color.
@@ -255,6 +364,7 @@ func (n *TreeNode) SetColor(color tcell.Color) {
 	defer n.Unlock()
 
 	n.color = color
+	n.changes++
 }
 
 // SetIndent sets an additional indentation for this node's text. A value of 0
@@ -265,6 +375,7 @@ func (n *TreeNode) SetIndent(indent int) {
 	defer n.Unlock()
 
 	n.indent = indent
+	n.changes++
 }
 
 // TreeView displays tree structures. A tree consists of nodes (TreeNode
@@ -285,6 +396,16 @@ func (n *TreeNode) SetIndent(indent int) {
 //   - G, end: Move (the selection) to the bottom.
 //   - Ctrl-F, page down: Move (the selection) down by one page.
 //   - Ctrl-B, page up: Move (the selection) up by one page.
+//   - /: Type a search query. The first matching node is selected and its
+//     ancestors are expanded as you type. Enter keeps the query, Escape
+//     cancels it.
+//   - n: Move to the next match.
+//   - N: Move to the previous match.
+//   - f: Toggle the filter mode, which hides nodes not matching the query.
+//   - Escape: End the search.
+//
+// If check boxes are shown (see SetCheckable()), Space checks or unchecks the
+// current node and its descendants.
 //
 // Selected nodes can trigger the "selected" callback when the user hits Enter.
 //
@@ -352,6 +473,50 @@ type TreeView struct {
 	// The visible nodes, top-down, as set by process().
 	nodes []*TreeNode
 
+	// The search query, its pattern, and whether or not it is being typed.
+	searchQuery   string
+	searchPattern *regexp.Regexp
+	searching     bool
+
+	// Whether or not search queries are regular expressions.
+	searchRegexp bool
+
+	// The current node and scroll offset when the search query was started.
+	searchNode   *TreeNode
+	searchOffset int
+
+	// The background color of matches and the color of the search query.
+	searchColor     tcell.Color
+	searchTextColor tcell.Color
+
+	// Whether or not nodes are filtered by the search query, and the nodes
+	// shown in filter mode as set by process() (see filterTree).
+	filterMode bool
+	filtered   map[*TreeNode]bool
+
+	// Whether or not nodes may be dragged, and the node the left mouse button
+	// was pressed on.
+	draggable bool
+	dragNode  *TreeNode
+
+	// The node and position the drop indicator is drawn at, and its color.
+	dropNode           *TreeNode
+	dropPosition       DropPosition
+	dropIndicatorColor tcell.Color
+
+	// Optional functions which are called when data is dragged over the tree,
+	// when the user moved a node and when data from another primitive is
+	// dropped on the tree.
+	acceptDrop func(payload *DragPayload, target *TreeNode, position DropPosition) bool
+	moved      func(node, parent *TreeNode, index int)
+	dropped    func(payload *DragPayload, target *TreeNode, position DropPosition)
+
+	// Whether or not check boxes are drawn in front of the nodes.
+	checkable bool
+
+	// An optional function called when the user checks or unchecks a node.
+	checkedChanged func(node *TreeNode, checked bool)
+
 	sync.RWMutex
 }
 
@@ -363,6 +528,9 @@ func NewTreeView() *TreeView {
 		graphics:            true,
 		graphicsColor:       Styles.GraphicsColor,
 		scrollBarColor:      Styles.ScrollBarColor,
+		searchColor:         Styles.MoreContrastBackgroundColor,
+		searchTextColor:     Styles.SecondaryTextColor,
+		dropIndicatorColor:  Styles.SecondaryTextColor,
 	}
 }
 
@@ -579,7 +747,16 @@ func (t *TreeView) process() {
 	if t.graphics {
 		graphicsOffset = 1
 	}
+	t.filtered = nil
+	if t.filterMode && t.searchPattern != nil {
+		t.filtered = t.filterTree()
+	}
 	t.root.walk(func(node, parent *TreeNode) bool {
+		descendants, shown := t.filtered[node]
+		if t.filtered != nil && !shown {
+			return false
+		}
+
 		// Set node attributes.
 		node.parent = parent
 		if parent == nil {
@@ -620,6 +797,9 @@ func (t *TreeView) process() {
 		}
 
 		// Recurse if desired.
+		if t.filtered != nil {
+			return descendants
+		}
 		return node.expanded
 	})
 
@@ -757,6 +937,10 @@ func (t *TreeView) Draw(screen tcell.Screen) {
 
 	// Scroll the tree.
 	x, y, width, height := t.GetInnerRect()
+	defer t.drawSearch(screen, x, y+height-1, width)
+	if isDropTarget(t) {
+		defer t.drawDropIndicator(screen)
+	}
 	switch t.movement {
 	case treeUp:
 		t.offsetY--
@@ -807,7 +991,7 @@ func (t *TreeView) Draw(screen tcell.Screen) {
 				}
 
 				// Draw a branch if this ancestor is not a last child.
-				if ancestor.parent.children[len(ancestor.parent.children)-1] != ancestor {
+				if !t.isLastChild(ancestor) {
 					if posY-1 >= y && ancestor.textX > ancestor.graphicsX {
 						PrintJoinedSemigraphics(screen, x+ancestor.graphicsX, posY-1, Borders.Vertical, t.graphicsColor)
 					}
@@ -836,10 +1020,11 @@ func (t *TreeView) Draw(screen tcell.Screen) {
 
 		// Draw the prefix and the text.
 		if node.textX < width && posY < y+height {
-			// Prefix.
-			var prefixWidth int
-			if len(t.prefixes) > 0 {
-				_, prefixWidth = Print(screen, t.prefixes[(node.level-t.topLevel)%len(t.prefixes)], x+node.textX, posY, width-node.textX, AlignLeft, node.color)
+			// Check box and prefix.
+			prefixWidth := t.drawCheckBox(screen, node, x+node.textX, posY, width-node.textX)
+			if len(t.prefixes) > 0 && node.textX+prefixWidth < width {
+				_, w := Print(screen, t.prefixes[(node.level-t.topLevel)%len(t.prefixes)], x+node.textX+prefixWidth, posY, width-node.textX-prefixWidth, AlignLeft, node.color)
+				prefixWidth += w
 			}
 
 			// Text.
@@ -857,6 +1042,7 @@ func (t *TreeView) Draw(screen tcell.Screen) {
 					style = tcell.StyleDefault.Background(backgroundColor).Foreground(foregroundColor)
 				}
 				PrintStyle(screen, []byte(node.text), x+node.textX+prefixWidth, posY, width-node.textX-prefixWidth, AlignLeft, style)
+				t.highlightMatches(screen, node, x+node.textX+prefixWidth, posY, width-node.textX-prefixWidth)
 			}
 		}
 
@@ -871,6 +1057,8 @@ func (t *TreeView) Draw(screen tcell.Screen) {
 // InputHandler returns the handler for this primitive.
 func (t *TreeView) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return t.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		keys := t.GetKeys()
+
 		selectNode := func() {
 			t.Lock()
 			currentNode := t.currentNode
@@ -894,38 +1082,75 @@ func (t *TreeView) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 		defer t.Unlock()
 
 		// Because the tree is flattened into a list only at drawing time, we also
-		// postpone the (selection) movement to drawing time.
-		if HitShortcut(event, Keys.Cancel, Keys.MovePreviousField, Keys.MoveNextField) {
+		// postpone the (selection) movement to drawing time. Search results are
+		// selected immediately.
+		previous, searched := t.currentNode, false
+		if t.searching {
+			t.handleSearchKey(event)
+			searched = true
+		} else if HitShortcut(event, keys.Cancel) && t.searchQuery != "" {
+			t.setSearchQuery("") // End the search first.
+		} else if HitShortcut(event, keys.Cancel, keys.MovePreviousField, keys.MoveNextField) {
 			if t.done != nil {
 				t.Unlock()
 				t.done(event.Key())
 				t.Lock()
 			}
-		} else if HitShortcut(event, Keys.MoveFirst, Keys.MoveFirst2) {
+		} else if HitShortcut(event, keys.MoveFirst, keys.MoveFirst2) {
 			t.movement = treeHome
-		} else if HitShortcut(event, Keys.MoveLast, Keys.MoveLast2) {
+		} else if HitShortcut(event, keys.MoveLast, keys.MoveLast2) {
 			t.movement = treeEnd
-		} else if HitShortcut(event, Keys.MoveUp, Keys.MoveUp2, Keys.MovePreviousField) {
+		} else if HitShortcut(event, keys.MoveUp, keys.MoveUp2, keys.MovePreviousField) {
 			t.movement = treeUp
-		} else if HitShortcut(event, Keys.MoveDown, Keys.MoveDown2, Keys.MoveNextField) {
+		} else if HitShortcut(event, keys.MoveDown, keys.MoveDown2, keys.MoveNextField) {
 			t.movement = treeDown
-		} else if HitShortcut(event, Keys.MovePreviousPage) {
+		} else if HitShortcut(event, keys.MovePreviousPage) {
 			t.movement = treePageUp
-		} else if HitShortcut(event, Keys.MoveNextPage) {
+		} else if HitShortcut(event, keys.MoveNextPage) {
 			t.movement = treePageDown
-		} else if HitShortcut(event, Keys.Select, Keys.Select2) {
+		} else if t.checkable && t.currentNode != nil && HitShortcut(event, keys.Select2) {
+			t.toggleChecked(t.currentNode)
+		} else if HitShortcut(event, keys.Select, keys.Select2) {
 			t.Unlock()
 			selectNode()
 			t.Lock()
+		} else if HitShortcut(event, keys.Search) {
+			t.searching = true
+			t.setSearchQuery("")
+			t.searchNode, t.searchOffset = t.currentNode, t.offsetY
+		} else if HitShortcut(event, keys.SearchNext) {
+			searched = t.searchSelect(t.currentNode, 1, true)
+		} else if HitShortcut(event, keys.SearchPrevious) {
+			searched = t.searchSelect(t.currentNode, -1, true)
+		} else if HitShortcut(event, keys.Filter) {
+			t.filterMode = !t.filterMode
 		}
 
 		t.process()
+
+		if node := t.currentNode; searched && node != nil && node != previous {
+			if t.changed != nil {
+				t.Unlock()
+				t.changed(node)
+				t.Lock()
+			}
+			if node.focused != nil {
+				t.Unlock()
+				node.focused()
+				t.Lock()
+			}
+		}
 	})
 }
 
 // MouseHandler returns the mouse handler for this primitive.
 func (t *TreeView) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 	return t.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		// Drag nodes and drop data.
+		if consumed, capture = t.handleDrag(action, event); consumed {
+			return
+		}
+
 		x, y := event.Position()
 		if !t.InRect(x, y) {
 			return false, nil
@@ -933,6 +1158,12 @@ func (t *TreeView) MouseHandler() func(action MouseAction, event *tcell.EventMou
 
 		switch action {
 		case MouseLeftClick:
+			if t.clickCheckBox(x, y) {
+				consumed = true
+				setFocus(t)
+				break
+			}
+
 			_, rectY, _, _ := t.GetInnerRect()
 			y -= rectY
 			if y >= 0 && y < len(t.nodes) {
@@ -960,3 +1191,27 @@ func (t *TreeView) MouseHandler() func(action MouseAction, event *tcell.EventMou
 		return
 	})
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme, including the colors of all nodes. Colors which were set
+// explicitly are kept.
+func (t *TreeView) ApplyTheme(previous, theme *Theme) {
+	t.Box.ApplyTheme(previous, theme)
+
+	t.Lock()
+	themeColor(&t.graphicsColor, previous.GraphicsColor, theme.GraphicsColor)
+	themeColor(&t.scrollBarColor, previous.ScrollBarColor, theme.ScrollBarColor)
+	themeColor(&t.searchColor, previous.MoreContrastBackgroundColor, theme.MoreContrastBackgroundColor)
+	themeColor(&t.searchTextColor, previous.SecondaryTextColor, theme.SecondaryTextColor)
+	themeColor(&t.dropIndicatorColor, previous.SecondaryTextColor, theme.SecondaryTextColor)
+	root := t.root
+	t.Unlock()
+
+	if root == nil {
+		return
+	}
+	root.Walk(func(node, parent *TreeNode) bool {
+		themeColor(&node.color, previous.PrimaryTextColor, theme.PrimaryTextColor)
+		return true
+	})
+}
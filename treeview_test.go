@@ -1,7 +1,11 @@
 package crtview
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/gdamore/tcell/v2"
 )
 
 const (
@@ -69,3 +73,271 @@ func TestTreeView(t *testing.T) {
 		t.Errorf("failed to initialize TreeView: incorrect row count: expected 1, got %d", tr.GetRowCount())
 	}
 }
+
+func TestTreeNodeLoad(t *testing.T) {
+	t.Parallel()
+
+	var (
+		loads   int
+		pending func()
+	)
+	node := NewTreeNode("packages")
+	node.SetLoadFunc(func(node *TreeNode) {
+		loads++
+		// Load asynchronously, as with Application.QueueUpdateDraw.
+		pending = func() {
+			node.SetChildren([]*TreeNode{NewTreeNode("a"), NewTreeNode("b")})
+		}
+	})
+
+	if node.IsExpanded() || node.IsLoaded() || len(node.GetChildren()) != 0 {
+		t.Fatalf("failed to defer loading: expected collapsed node without children")
+	}
+
+	node.Expand()
+	children := node.GetChildren()
+	if loads != 1 || len(children) != 1 || children[0].GetText() != "loading…" {
+		t.Fatalf("failed to show placeholder: expected one loading node, got %d children", len(children))
+	}
+
+	pending()
+	node.Collapse()
+	node.Expand()
+	if loads != 1 || len(node.GetChildren()) != 2 {
+		t.Errorf("failed to load children once: expected 1 load and 2 children, got %d and %d", loads, len(node.GetChildren()))
+	}
+
+	node.Reload()
+	if loads != 2 || len(node.GetChildren()) != 1 {
+		t.Errorf("failed to reload children: expected 2 loads and the placeholder, got %d and %d", loads, len(node.GetChildren()))
+	}
+}
+
+func TestTreeNodeExpandAll(t *testing.T) {
+	t.Parallel()
+
+	var loads int
+	lazy := NewTreeNode("lazy")
+	lazy.SetLoadFunc(func(node *TreeNode) {
+		loads++
+		child := NewTreeNode("child")
+		child.AddChild(NewTreeNode("grandchild"))
+		child.Collapse()
+		node.SetChildren([]*TreeNode{child})
+	})
+	root := NewTreeNode("root")
+	root.AddChild(lazy)
+	root.Collapse()
+
+	root.ExpandAll()
+	if !root.IsExpanded() || !lazy.IsExpanded() {
+		t.Fatalf("failed to expand nodes: expected expanded root and lazy node")
+	} else if loads != 1 || !lazy.IsLoaded() {
+		t.Fatalf("failed to load node: expected 1 load, got %d", loads)
+	}
+	children := lazy.GetChildren()
+	if len(children) != 1 || children[0].GetText() != "child" {
+		t.Fatalf("failed to load children: expected child, got %d children", len(children))
+	} else if !children[0].IsExpanded() {
+		t.Errorf("failed to expand loaded children: expected expanded child")
+	}
+
+	root.CollapseAll()
+	if root.IsExpanded() || lazy.IsExpanded() || children[0].IsExpanded() {
+		t.Errorf("failed to collapse nodes: expected collapsed nodes")
+	}
+}
+
+func TestTreeViewSearch(t *testing.T) {
+	t.Parallel()
+
+	root := NewTreeNode("root")
+	src := NewTreeNode("src")
+	main := NewTreeNode("main.go")
+	util := NewTreeNode("util.go")
+	src.SetChildren([]*TreeNode{main, util})
+	src.Collapse()
+	docs := NewTreeNode("docs")
+	readme := NewTreeNode("README.md")
+	docs.AddChild(readme)
+	docs.Collapse()
+	root.SetChildren([]*TreeNode{src, docs})
+
+	tr := NewTreeView()
+	tr.SetRoot(root)
+	tr.SetCurrentNode(root)
+	tr.SetRect(0, 0, 40, 10)
+
+	app, err := newTestApp(tr)
+	if err != nil {
+		t.Fatalf("failed to initialize Application: %s", err)
+	}
+	tr.Draw(app.screen)
+	if tr.GetRowCount() != 3 {
+		t.Fatalf("failed to draw TreeView: incorrect row count: expected 3, got %d", tr.GetRowCount())
+	}
+
+	var changed *TreeNode
+	tr.SetChangedFunc(func(node *TreeNode) {
+		changed = node
+	})
+
+	// Type a query.
+	handler := tr.InputHandler()
+	for _, r := range "/GO" {
+		handler(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone), nil)
+	}
+	if tr.GetSearch() != "GO" {
+		t.Fatalf("failed to type search query: expected GO, got %s", tr.GetSearch())
+	} else if tr.GetCurrentNode() != main || changed != main {
+		t.Fatalf("failed to select match: expected main.go, got %s", tr.GetCurrentNode().GetText())
+	} else if !src.IsExpanded() {
+		t.Errorf("failed to expand ancestor of match")
+	}
+	tr.Draw(app.screen)
+	if line := screenLine(app.screen, 9); line != "/GO" {
+		t.Errorf("failed to draw search query: got %q", line)
+	}
+	handler(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), nil)
+
+	tr.Draw(app.screen)
+	line := screenLine(app.screen, 3)
+	_, _, style, _ := app.screen.GetContent(len([]rune(line[:strings.Index(line, "util.go")]))+5, 3)
+	if _, background, _ := style.Decompose(); background != Styles.MoreContrastBackgroundColor {
+		t.Errorf("failed to highlight match: expected background %v, got %v", Styles.MoreContrastBackgroundColor, background)
+	}
+
+	// Step through matches.
+	handler(tcell.NewEventKey(tcell.KeyRune, 'n', tcell.ModNone), nil)
+	if tr.GetCurrentNode() != util {
+		t.Errorf("failed to move to next match: expected util.go, got %s", tr.GetCurrentNode().GetText())
+	}
+	handler(tcell.NewEventKey(tcell.KeyRune, 'n', tcell.ModNone), nil)
+	if tr.GetCurrentNode() != main {
+		t.Errorf("failed to wrap around: expected main.go, got %s", tr.GetCurrentNode().GetText())
+	}
+	handler(tcell.NewEventKey(tcell.KeyRune, 'N', tcell.ModNone), nil)
+	if tr.GetCurrentNode() != util {
+		t.Errorf("failed to move to previous match: expected util.go, got %s", tr.GetCurrentNode().GetText())
+	}
+
+	// Regular expressions.
+	tr.SetSearchRegexp(true)
+	tr.SetSearch(`^[A-Z]+\.md$`)
+	if tr.GetCurrentNode() != readme || !docs.IsExpanded() {
+		t.Errorf("failed to search regular expression: expected README.md, got %s", tr.GetCurrentNode().GetText())
+	}
+
+	// Filter mode.
+	tr.SetSearch(`\.go$`)
+	handler(tcell.NewEventKey(tcell.KeyRune, 'f', tcell.ModNone), nil)
+	tr.Draw(app.screen)
+	if !tr.IsFilterMode() || tr.GetRowCount() != 4 {
+		t.Errorf("failed to filter nodes: expected 4 rows, got %d", tr.GetRowCount())
+	}
+	for y, text := range []string{"root", "src", "main.go", "util.go", ""} {
+		if line := screenLine(app.screen, y); !strings.Contains(line, text) || (text == "" && strings.TrimSpace(line) != "") {
+			t.Errorf("failed to draw filtered tree: expected %q in line %d, got %q", text, y, line)
+		}
+	}
+
+	handler(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone), nil)
+	tr.Draw(app.screen)
+	if tr.GetSearch() != "" || tr.GetRowCount() != 6 {
+		t.Errorf("failed to end search: expected 6 rows, got %d", tr.GetRowCount())
+	}
+}
+
+func TestTreeViewCheckable(t *testing.T) {
+	t.Parallel()
+
+	root := NewTreeNode("packages")
+	base := NewTreeNode("base")
+	libc, bash := NewTreeNode("libc"), NewTreeNode("bash")
+	base.SetChildren([]*TreeNode{libc, bash})
+	extra := NewTreeNode("extra")
+	root.SetChildren([]*TreeNode{base, extra})
+
+	tr := NewTreeView()
+	tr.SetRoot(root)
+	tr.SetCurrentNode(base)
+	tr.SetCheckable(true)
+	tr.SetRect(0, 0, 40, 10)
+
+	var changed []string
+	tr.SetCheckedChangedFunc(func(node *TreeNode, checked bool) {
+		changed = append(changed, fmt.Sprintf("%s:%v", node.GetText(), checked))
+	})
+	var selected bool
+	tr.SetSelectedFunc(func(node *TreeNode) {
+		selected = true
+	})
+
+	app, err := newTestApp(tr)
+	if err != nil {
+		t.Fatalf("failed to initialize Application: %s", err)
+	}
+	tr.Draw(app.screen)
+
+	// Checking a parent checks its children.
+	handler := tr.InputHandler()
+	handler(tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone), nil)
+	if selected {
+		t.Errorf("failed to toggle node: expected no selection")
+	} else if !base.IsChecked() || !libc.IsChecked() || !bash.IsChecked() {
+		t.Errorf("failed to check children: expected libc and bash to be checked")
+	} else if root.GetCheckState() != TreeNodePartiallyChecked {
+		t.Errorf("failed to derive parent state: expected partially checked, got %d", root.GetCheckState())
+	}
+	if nodes := tr.GetCheckedNodes(); len(nodes) != 3 || nodes[0] != base || nodes[1] != libc || nodes[2] != bash {
+		t.Errorf("failed to get checked nodes: expected base, libc and bash, got %d nodes", len(nodes))
+	}
+
+	// Unchecking a child makes its parent partially checked.
+	handler(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone), nil)
+	handler(tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone), nil)
+	tr.Draw(app.screen)
+	for y, expected := range []string{"[-] packages", "[-] base", "[ ] libc", "[x] bash", "[ ] extra"} {
+		if line := screenLine(app.screen, y); !strings.Contains(line, expected) {
+			t.Errorf("failed to draw check boxes: expected %q in line %d, got %q", expected, y, line)
+		}
+	}
+
+	// Clicking a check box toggles it.
+	line := screenLine(app.screen, 4)
+	x := len([]rune(line[:strings.Index(line, "[ ] extra")]))
+	tr.MouseHandler()(MouseLeftClick, tcell.NewEventMouse(x+1, 4, tcell.Button1, tcell.ModNone), func(p Primitive) {})
+	if !extra.IsChecked() || tr.GetCurrentNode() != extra {
+		t.Errorf("failed to toggle node by clicking its check box")
+	}
+	if expected := "base:true libc:false extra:true"; strings.Join(changed, " ") != expected {
+		t.Errorf("failed to call checked changed handler: expected %s, got %s", expected, strings.Join(changed, " "))
+	}
+
+	handler(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), nil)
+	if !selected {
+		t.Errorf("failed to select node with Enter")
+	}
+}
+
+func TestTreeNodeCheckLoad(t *testing.T) {
+	t.Parallel()
+
+	node := NewTreeNode("packages")
+	node.SetLoadFunc(func(node *TreeNode) {
+		node.SetChildren([]*TreeNode{NewTreeNode("a"), NewTreeNode("b")})
+	})
+	node.SetChecked(true)
+
+	node.Expand()
+	if children := node.GetChildren(); len(children) != 2 {
+		t.Fatalf("failed to load children: expected 2 children, got %d", len(children))
+	} else if !node.IsChecked() || !children[0].IsChecked() || !children[1].IsChecked() {
+		t.Errorf("failed to check loaded children: expected checked node and children, got state %d", node.GetCheckState())
+	}
+
+	node.AddChild(NewTreeNode("c"))
+	if !node.IsChecked() {
+		t.Errorf("failed to check added child: expected checked node, got state %d", node.GetCheckState())
+	}
+}
@@ -0,0 +1,189 @@
+package crtview
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// TreeNodeCheckState is the check state of a tree node shown in a checkable
+// TreeView.
+type TreeNodeCheckState int
+
+// Tree node check states.
+const (
+	// The node and all of its descendants are unchecked.
+	TreeNodeUnchecked TreeNodeCheckState = iota
+
+	// The node and all of its descendants are checked.
+	TreeNodeChecked
+
+	// Some of the node's descendants are checked, others are not.
+	TreeNodePartiallyChecked
+)
+
+// The check boxes drawn for the check states, followed by a space.
+var treeNodeCheckBoxes = map[TreeNodeCheckState][]byte{
+	TreeNodeUnchecked:        EscapeBytes([]byte("[ ] ")),
+	TreeNodeChecked:          EscapeBytes([]byte("[x] ")),
+	TreeNodePartiallyChecked: EscapeBytes([]byte("[-] ")),
+}
+
+// treeNodeCheckBoxWidth is the width of a check box, including the space.
+const treeNodeCheckBoxWidth = 4
+
+// SetChecked checks or unchecks this node and all of its descendants. Child
+// nodes which are added to a checked node later, e.g. by its load function,
+// are checked as well.
+func (n *TreeNode) SetChecked(checked bool) {
+	n.Lock()
+	n.checked = checked
+	n.changes++
+	children := n.children
+	n.Unlock()
+
+	for _, child := range children {
+		child.SetChecked(checked)
+	}
+}
+
+// IsChecked returns whether this node and all of its descendants are checked.
+func (n *TreeNode) IsChecked() bool {
+	return n.GetCheckState() == TreeNodeChecked
+}
+
+// GetCheckState returns the check state of this node. The state of a node with
+// selectable child nodes is derived from the states of these child nodes: it is
+// checked if all of them are checked, unchecked if all of them are unchecked,
+// and partially checked otherwise.
+func (n *TreeNode) GetCheckState() TreeNodeCheckState {
+	n.RLock()
+	checked, children := n.checked, n.children
+	n.RUnlock()
+
+	state := TreeNodeUnchecked
+	if checked {
+		state = TreeNodeChecked
+	}
+	var derived bool
+	for _, child := range children {
+		if !child.isSelectable() {
+			continue // E.g. the placeholder of nodes being loaded.
+		}
+		childState := child.GetCheckState()
+		if !derived {
+			state, derived = childState, true
+		} else if childState != state {
+			return TreeNodePartiallyChecked
+		}
+	}
+	return state
+}
+
+// isSelectable returns whether the node can be focused and selected.
+func (n *TreeNode) isSelectable() bool {
+	n.RLock()
+	defer n.RUnlock()
+
+	return n.selectable
+}
+
+// SetCheckable sets a flag which determines whether check boxes are drawn in
+// front of the nodes. The user toggles the current node with Space or by
+// clicking its check box. Checking or unchecking a node checks or unchecks
+// all of its descendants. Nodes with some checked and some unchecked
+// descendants are shown as partially checked (see TreeNode.GetCheckState).
+//
+// While check boxes are shown, Space no longer selects the current node as
+// Enter does.
+func (t *TreeView) SetCheckable(checkable bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.checkable = checkable
+}
+
+// SetCheckedChangedFunc sets the function which is called when the user checks
+// or unchecks a node. It receives the node and whether it was checked.
+func (t *TreeView) SetCheckedChangedFunc(handler func(node *TreeNode, checked bool)) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.checkedChanged = handler
+}
+
+// GetCheckedNodes returns the checked nodes, including the nodes whose
+// descendants are all checked, in depth-first, pre-order (NLR) order.
+// Collapsed nodes are included.
+func (t *TreeView) GetCheckedNodes() []*TreeNode {
+	t.RLock()
+	root := t.root
+	t.RUnlock()
+
+	var nodes []*TreeNode
+	var add func(node *TreeNode)
+	add = func(node *TreeNode) {
+		if node.GetCheckState() == TreeNodeUnchecked {
+			return // Nothing is checked below.
+		}
+		if node.IsChecked() && node.isSelectable() {
+			nodes = append(nodes, node)
+		}
+		for _, child := range node.GetChildren() {
+			add(child)
+		}
+	}
+	if root != nil {
+		add(root)
+	}
+	return nodes
+}
+
+// toggleChecked checks a node which is not checked and unchecks a node which
+// is checked, and calls the checked changed handler. The tree view must be
+// locked.
+func (t *TreeView) toggleChecked(node *TreeNode) {
+	checked := !node.IsChecked()
+	node.SetChecked(checked)
+
+	if t.checkedChanged != nil {
+		t.Unlock()
+		t.checkedChanged(node, checked)
+		t.Lock()
+	}
+}
+
+// clickCheckBox toggles the node whose check box is at the given position and
+// makes it the current node. It returns whether a check box was clicked.
+func (t *TreeView) clickCheckBox(x, y int) bool {
+	t.Lock()
+	defer t.Unlock()
+
+	index := t.nodeAtY(y)
+	if !t.checkable || index < 0 {
+		return false
+	}
+	node := t.nodes[index]
+	rectX, _, _, _ := t.GetInnerRect()
+	if x -= rectX; !node.selectable || x < node.textX || x >= node.textX+treeNodeCheckBoxWidth-1 {
+		return false
+	}
+
+	if t.currentNode != node {
+		t.currentNode = node
+		if t.changed != nil {
+			t.Unlock()
+			t.changed(node)
+			t.Lock()
+		}
+	}
+	t.toggleChecked(node)
+	return true
+}
+
+// drawCheckBox draws the check box of a node and returns its width.
+func (t *TreeView) drawCheckBox(screen tcell.Screen, node *TreeNode, x, y, width int) int {
+	if !t.checkable {
+		return 0
+	}
+	Print(screen, treeNodeCheckBoxes[node.GetCheckState()], x, y, width, AlignLeft, node.color)
+	return treeNodeCheckBoxWidth
+}
@@ -0,0 +1,297 @@
+package crtview
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// SetDraggable sets a flag which determines whether the user may move nodes by
+// dragging them with the mouse. A node dropped on the text of another node is
+// moved into that node as its last child. A node dropped on the lines left of
+// another node, or on the first two columns of its text, is moved before that
+// node if it is dragged up and after it if it is dragged down. While a node is
+// dragged over the tree, a line indicates where it will be dropped. The root
+// node is not dragged and nodes are never moved into their own subtree, or
+// into nodes whose children were not loaded yet (see TreeNode.SetLoadFunc).
+// The node a node is moved into is expanded.
+//
+// Nodes may also be dragged to other primitives which accept drops (see
+// StartDrag), e.g. a List.
+func (t *TreeView) SetDraggable(draggable bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.draggable = draggable
+}
+
+// SetDropIndicatorColor sets the color of the line which indicates where
+// dragged data is dropped.
+func (t *TreeView) SetDropIndicatorColor(color tcell.Color) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.dropIndicatorColor = color
+}
+
+// SetAcceptDropFunc sets a handler which is called while the user drags data
+// over the tree. It receives the dragged data (a *TreeNode if it was dragged
+// from this tree view), the node it would be dropped on and the position, and
+// returns whether the data may be dropped there. Without a handler, nodes of
+// this tree view are accepted anywhere and data dragged from other primitives
+// is accepted if a handler was set with SetDroppedFunc().
+func (t *TreeView) SetAcceptDropFunc(handler func(payload *DragPayload, target *TreeNode, position DropPosition) bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.acceptDrop = handler
+}
+
+// SetMovedFunc sets a handler which is called after the user moved a node by
+// dragging it. It receives the node, its new parent node and its index among
+// the parent's children.
+func (t *TreeView) SetMovedFunc(handler func(node, parent *TreeNode, index int)) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.moved = handler
+}
+
+// SetDroppedFunc sets a handler which is called when the user drops data
+// dragged from another primitive on the tree. It receives the data, the node
+// it was dropped on and the position.
+func (t *TreeView) SetDroppedFunc(handler func(payload *DragPayload, target *TreeNode, position DropPosition)) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.dropped = handler
+}
+
+// nodeAtY returns the index of the visible node at the given Y position, or
+// -1 if there is no such node.
+func (t *TreeView) nodeAtY(y int) int {
+	_, rectY, _, height := t.GetInnerRect()
+	if y < rectY || y >= rectY+height {
+		return -1
+	}
+	index := y - rectY + t.offsetY
+	if index >= len(t.nodes) {
+		return -1
+	}
+	return index
+}
+
+// dropTarget returns the node data dropped at the given position is dropped
+// on, or nil, and the position relative to that node.
+func (t *TreeView) dropTarget(payload *DragPayload, x, y int) (target *TreeNode, position DropPosition) {
+	index := t.nodeAtY(y)
+	if index < 0 {
+		return nil, DropBefore
+	}
+	target = t.nodes[index]
+	rectX, _, _, _ := t.GetInnerRect()
+	if x-rectX >= target.textX+2 {
+		return target, DropOnto
+	}
+	if payload.Source == t {
+		for _, node := range t.nodes[:index] {
+			if node == t.dragNode {
+				return target, DropAfter
+			}
+		}
+	}
+	return target, DropBefore
+}
+
+// acceptsDrop returns whether the data may be dropped on the target node at
+// the given position. The tree view must not be locked.
+func (t *TreeView) acceptsDrop(payload *DragPayload, target *TreeNode, position DropPosition) bool {
+	t.RLock()
+	accept, internal := t.acceptDrop, payload.Source == t
+	defaultAccept := internal || t.dropped != nil
+	if position != DropOnto && target.parent == nil {
+		t.RUnlock()
+		return false // Nodes are not moved next to the root node.
+	}
+	if internal {
+		if t.dragNode == nil {
+			t.RUnlock()
+			return false
+		}
+		for node := target; node != nil; node = node.parent {
+			if node == t.dragNode {
+				t.RUnlock()
+				return false // Nodes are not moved into their own subtree.
+			}
+		}
+	}
+	t.RUnlock()
+	if internal && position == DropOnto && !target.IsLoaded() {
+		return false // Loading the children would replace the moved node.
+	}
+
+	if accept == nil {
+		return defaultAccept
+	}
+	return accept(payload, target, position)
+}
+
+// handleDrag handles mouse events which drag nodes and drop data on the tree.
+// It returns whether the event was consumed and the primitive which captures
+// the mouse. The tree view must not be locked.
+func (t *TreeView) handleDrag(action MouseAction, event *tcell.EventMouse) (consumed bool, capture Primitive) {
+	x, y := event.Position()
+	payload := GetDrag()
+
+	switch action {
+	case MouseLeftDown:
+		t.Lock()
+		defer t.Unlock()
+
+		t.dragNode = nil
+		if index := t.nodeAtY(y); t.draggable && payload == nil && index >= 0 && t.InRect(x, y) && t.nodes[index] != t.root {
+			// Capture the mouse until dragging starts.
+			t.dragNode = t.nodes[index]
+			return true, t
+		}
+	case MouseMove:
+		t.RLock()
+		pressed := payload == nil && t.dragNode != nil && event.Buttons()&tcell.ButtonPrimary != 0
+		var node *TreeNode
+		if index := t.nodeAtY(y); pressed && (index < 0 || t.nodes[index] != t.dragNode) {
+			node = t.dragNode
+		}
+		t.RUnlock()
+		if node != nil {
+			// Start dragging when the mouse leaves the pressed node.
+			StartDrag(t, node)
+			payload = GetDrag()
+		} else if pressed {
+			return true, t
+		}
+		if payload == nil || !t.InRect(x, y) {
+			return false, nil
+		}
+
+		t.RLock()
+		target, position := t.dropTarget(payload, x, y)
+		t.RUnlock()
+		if target != nil && t.acceptsDrop(payload, target, position) {
+			t.Lock()
+			t.dropNode, t.dropPosition = target, position
+			t.Unlock()
+			setDropTarget(t)
+		}
+		return true, nil
+	case MouseLeftUp:
+		if payload == nil || !t.InRect(x, y) {
+			t.Lock()
+			t.dragNode = nil
+			t.Unlock()
+			return false, nil
+		}
+
+		t.RLock()
+		target, position := t.dropTarget(payload, x, y)
+		dropped := t.dropped
+		t.RUnlock()
+		if target != nil && t.acceptsDrop(payload, target, position) {
+			if payload.Source == t {
+				t.moveNode(target, position)
+			} else if dropped != nil {
+				dropped(payload, target, position)
+			}
+		}
+
+		t.Lock()
+		t.dragNode = nil
+		t.Unlock()
+		return true, nil
+	}
+	return false, nil
+}
+
+// moveNode moves the dragged node relative to the target node, selects it and
+// calls the moved handler. The tree view must not be locked.
+func (t *TreeView) moveNode(target *TreeNode, position DropPosition) {
+	if position == DropOnto {
+		target.SetExpanded(true)
+	}
+
+	t.Lock()
+	node := t.dragNode
+	oldParent, parent := node.parent, target.parent
+	if position == DropOnto {
+		parent = target
+	}
+
+	oldParent.Lock()
+	for index, child := range oldParent.children {
+		if child == node {
+			oldParent.children = append(oldParent.children[:index:index], oldParent.children[index+1:]...)
+			oldParent.changes++
+			break
+		}
+	}
+	oldParent.Unlock()
+
+	parent.Lock()
+	index := len(parent.children)
+	if position != DropOnto {
+		for index = range parent.children {
+			if parent.children[index] == target {
+				break
+			}
+		}
+		if position == DropAfter {
+			index++
+		}
+	}
+	parent.children = append(parent.children[:index:index], append([]*TreeNode{node}, parent.children[index:]...)...)
+	parent.changes++
+	parent.Unlock()
+
+	t.currentNode = node
+	moved := t.moved
+	t.process()
+	t.Unlock()
+
+	if moved != nil {
+		moved(node, parent, index)
+	}
+}
+
+// drawDropIndicator draws a line under the node data is dropped after, or
+// under the node above the node data is dropped before, or highlights the
+// node data is dropped onto.
+func (t *TreeView) drawDropIndicator(screen tcell.Screen) {
+	index := -1
+	for i, node := range t.nodes {
+		if node == t.dropNode {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return
+	}
+
+	x, y, width, height := t.GetInnerRect()
+	row := index
+	switch t.dropPosition {
+	case DropOnto:
+		if row -= t.offsetY; row >= 0 && row < height {
+			node := t.nodes[index]
+			for column := node.textX; column < width; column++ {
+				mainc, combc, style, _ := screen.GetContent(x+column, y+row)
+				screen.SetContent(x+column, y+row, mainc, combc, style.Background(t.dropIndicatorColor))
+			}
+		}
+		return
+	case DropBefore:
+		row--
+	}
+	if row < t.offsetY || row-t.offsetY >= height {
+		return
+	}
+	textX := t.nodes[index].textX
+	drawDropIndicator(screen, x+textX, y+row-t.offsetY, width-textX, t.dropIndicatorColor)
+}
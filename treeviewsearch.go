@@ -0,0 +1,275 @@
+package crtview
+
+import (
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+// SetSearch sets the search query and selects the first node at or after the
+// current node whose text matches it, expanding the node's ancestors. Matching
+// substrings are highlighted. Unless SetSearchRegexp() is enabled, a node
+// matches if its text contains the query, ignoring case and color tags.
+// Provide an empty string to end the search.
+//
+// This function does NOT trigger the "changed" callback.
+func (t *TreeView) SetSearch(query string) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.searching = false
+	t.setSearchQuery(query)
+	if query != "" {
+		t.searchSelect(t.currentNode, 1, false)
+	}
+}
+
+// GetSearch returns the current search query.
+func (t *TreeView) GetSearch() string {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.searchQuery
+}
+
+// SetSearchRegexp sets a flag which determines whether search queries are
+// regular expressions (see package regexp). Regular expressions are case
+// sensitive unless they start with "(?i)". Invalid expressions match no node.
+func (t *TreeView) SetSearchRegexp(useRegexp bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.searchRegexp = useRegexp
+	t.setSearchQuery(t.searchQuery)
+}
+
+// SetSearchHighlightColor sets the background color of the substrings of node
+// texts which match the search query.
+func (t *TreeView) SetSearchHighlightColor(color tcell.Color) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.searchColor = color
+}
+
+// SetSearchTextColor sets the color of the search query while it is typed.
+func (t *TreeView) SetSearchTextColor(color tcell.Color) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.searchTextColor = color
+}
+
+// SetFilterMode sets a flag which determines whether nodes are filtered by
+// the search query. In filter mode, only nodes which match the query and
+// their ancestors are shown, whether or not the ancestors are expanded. All
+// nodes are shown while there is no query.
+func (t *TreeView) SetFilterMode(filter bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.filterMode = filter
+}
+
+// IsFilterMode returns whether nodes are filtered by the search query.
+func (t *TreeView) IsFilterMode() bool {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.filterMode
+}
+
+// SearchNext selects the next node which matches the search query, wrapping
+// around at the end of the tree. It returns false if no node matches.
+//
+// This function does NOT trigger the "changed" callback.
+func (t *TreeView) SearchNext() bool {
+	t.Lock()
+	defer t.Unlock()
+
+	return t.searchSelect(t.currentNode, 1, true)
+}
+
+// SearchPrevious selects the previous node which matches the search query,
+// wrapping around at the beginning of the tree. It returns false if no node
+// matches.
+//
+// This function does NOT trigger the "changed" callback.
+func (t *TreeView) SearchPrevious() bool {
+	t.Lock()
+	defer t.Unlock()
+
+	return t.searchSelect(t.currentNode, -1, true)
+}
+
+// setSearchQuery sets the search query and compiles its pattern.
+func (t *TreeView) setSearchQuery(query string) {
+	t.searchQuery, t.searchPattern = query, nil
+	if query == "" {
+		return
+	}
+	if !t.searchRegexp {
+		query = "(?i)" + regexp.QuoteMeta(query)
+	}
+	t.searchPattern, _ = regexp.Compile(query)
+}
+
+// searchText returns the text of a node which is searched, without color and
+// region tags.
+func searchText(node *TreeNode) string {
+	return string(StripTags([]byte(node.text), true, true))
+}
+
+// searchMatch returns whether the text of a node matches the search query.
+func (t *TreeView) searchMatch(node *TreeNode) bool {
+	return t.searchPattern != nil && t.searchPattern.MatchString(searchText(node))
+}
+
+// searchTree returns the selectable nodes at or below the top level in the
+// order in which they are drawn if all nodes are expanded, and the parent
+// nodes of all nodes.
+func (t *TreeView) searchTree() (nodes []*TreeNode, parents map[*TreeNode]*TreeNode) {
+	parents = make(map[*TreeNode]*TreeNode)
+	var add func(node *TreeNode, level int)
+	add = func(node *TreeNode, level int) {
+		if level >= t.topLevel && node.selectable {
+			nodes = append(nodes, node)
+		}
+		for _, child := range node.children {
+			parents[child] = node
+			add(child, level+1)
+		}
+	}
+	if t.root != nil {
+		add(t.root, 0)
+	}
+	return nodes, parents
+}
+
+// searchSelect selects the first node matching the search query, starting at
+// the given node and moving forward (step 1) or backward (step -1) through
+// the tree. The node itself is skipped if skip is true. The ancestors of the
+// selected node are expanded. It returns whether a match was found.
+func (t *TreeView) searchSelect(from *TreeNode, step int, skip bool) bool {
+	if t.searchPattern == nil {
+		return false
+	}
+	nodes, parents := t.searchTree()
+	if len(nodes) == 0 {
+		return false
+	}
+
+	position, start := 0, 0
+	for index, node := range nodes {
+		if node == from {
+			position = index
+			if skip {
+				start = 1
+			}
+			break
+		}
+	}
+	for i := start; i <= len(nodes); i++ {
+		node := nodes[((position+i*step)%len(nodes)+len(nodes))%len(nodes)]
+		if !t.searchMatch(node) {
+			continue
+		}
+
+		for ancestor := parents[node]; ancestor != nil; ancestor = parents[ancestor] {
+			ancestor.Expand()
+		}
+		t.currentNode = node
+		return true
+	}
+	return false
+}
+
+// filterTree returns the nodes which are shown in filter mode, i.e. the nodes
+// which match the search query and their ancestors, mapped to whether they
+// have matching descendants.
+func (t *TreeView) filterTree() map[*TreeNode]bool {
+	filtered := make(map[*TreeNode]bool)
+	var filter func(node *TreeNode, level int) bool
+	filter = func(node *TreeNode, level int) bool {
+		var descendants bool
+		for _, child := range node.children {
+			if filter(child, level+1) {
+				descendants = true
+			}
+		}
+		if descendants || (level >= t.topLevel && t.searchMatch(node)) {
+			filtered[node] = descendants
+			return true
+		}
+		return false
+	}
+	filter(t.root, 0)
+	return filtered
+}
+
+// isLastChild returns whether a node is the last child of its parent which
+// is shown.
+func (t *TreeView) isLastChild(node *TreeNode) bool {
+	children := node.parent.children
+	for index := len(children) - 1; index >= 0; index-- {
+		if _, shown := t.filtered[children[index]]; t.filtered == nil || shown {
+			return children[index] == node
+		}
+	}
+	return false
+}
+
+// handleSearchKey handles a key while the search query is typed.
+func (t *TreeView) handleSearchKey(event *tcell.EventKey) {
+	switch event.Key() {
+	case tcell.KeyEnter:
+		t.searching = false
+	case tcell.KeyEscape:
+		t.searching = false
+		t.setSearchQuery("")
+		t.currentNode, t.offsetY = t.searchNode, t.searchOffset
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if t.searchQuery == "" {
+			t.searching = false
+			return
+		}
+		_, size := utf8.DecodeLastRuneInString(t.searchQuery)
+		t.setSearchQuery(t.searchQuery[:len(t.searchQuery)-size])
+		t.currentNode = t.searchNode
+		t.searchSelect(t.searchNode, 1, false)
+	case tcell.KeyRune:
+		t.setSearchQuery(t.searchQuery + string(event.Rune()))
+		t.searchSelect(t.searchNode, 1, false)
+	}
+}
+
+// highlightMatches highlights the substrings of the text of a node which
+// match the search query. The text is drawn at the given position.
+func (t *TreeView) highlightMatches(screen tcell.Screen, node *TreeNode, x, y, width int) {
+	if t.searchPattern == nil {
+		return
+	}
+	text := searchText(node)
+	for _, match := range t.searchPattern.FindAllStringIndex(text, -1) {
+		start := runewidth.StringWidth(text[:match[0]])
+		end := start + runewidth.StringWidth(text[match[0]:match[1]])
+		for column := start; column < end && column < width; column++ {
+			mainc, combc, style, _ := screen.GetContent(x+column, y)
+			screen.SetContent(x+column, y, mainc, combc, style.Background(t.searchColor))
+		}
+	}
+}
+
+// drawSearch draws the search query being typed on the given line.
+func (t *TreeView) drawSearch(screen tcell.Screen, x, y, width int) {
+	if !t.searching || width <= 0 {
+		return
+	}
+	style := tcell.StyleDefault.Background(t.backgroundColor).Foreground(t.searchTextColor)
+	for i := 0; i < width; i++ {
+		screen.SetContent(x+i, y, ' ', nil, style)
+	}
+	PrintStyle(screen, EscapeBytes([]byte("/"+t.searchQuery)), x, y, width, AlignLeft, style)
+}
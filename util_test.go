@@ -1,6 +1,8 @@
 package crtview
 
 import (
+	"strings"
+
 	"github.com/gdamore/tcell/v2"
 )
 
@@ -17,3 +19,21 @@ func newTestApp(root Primitive) (*Application, error) {
 
 	return app, nil
 }
+
+// screenLine returns the text of a line of the screen.
+func screenLine(screen tcell.Screen, y int) string {
+	width, _ := screen.Size()
+
+	var b strings.Builder
+	for x := 0; x < width; x++ {
+		r, combining, _, w := screen.GetContent(x, y)
+		b.WriteRune(r)
+		for _, c := range combining {
+			b.WriteRune(c)
+		}
+		if w > 1 {
+			x += w - 1
+		}
+	}
+	return strings.TrimRight(b.String(), " ")
+}
@@ -277,3 +277,16 @@ func (w *Window) MouseHandler() func(action MouseAction, event *tcell.EventMouse
 		return true, capture
 	})
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme, for the window and its primitive. Colors which were set explicitly
+// are kept.
+func (w *Window) ApplyTheme(previous, theme *Theme) {
+	w.Box.ApplyTheme(previous, theme)
+
+	w.RLock()
+	primitive := w.primitive
+	w.RUnlock()
+
+	ApplyTheme(primitive, previous, theme)
+}
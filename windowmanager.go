@@ -10,6 +10,7 @@ import (
 type WindowManager struct {
 	windows    []*Window
 	fullScreen bool
+	menuBar    *MenuBar
 
 	sync.RWMutex
 	*Box
@@ -55,6 +56,25 @@ func (wm *WindowManager) SetFullScreen(state bool) *WindowManager {
 	return wm
 }
 
+// SetMenuBar sets a menu bar which is shown in the top row of the window
+// manager, above the windows. Open menus are drawn over the windows. Pass nil
+// to remove the menu bar.
+func (wm *WindowManager) SetMenuBar(m *MenuBar) *WindowManager {
+	wm.Lock()
+	defer wm.Unlock()
+
+	wm.menuBar = m
+	return wm
+}
+
+// GetMenuBar returns the menu bar of the window manager, or nil if it has none.
+func (wm *WindowManager) GetMenuBar() *MenuBar {
+	wm.RLock()
+	defer wm.RUnlock()
+
+	return wm.menuBar
+}
+
 // IsFullScreen returns true if the window manager is set to be full screen
 func (wm *WindowManager) IsFullScreen() bool {
 	return wm.fullScreen
@@ -105,6 +125,22 @@ func (wm *WindowManager) Draw(screen tcell.Screen) {
 		x, y, width, height = wm.GetInnerRect()
 	}
 
+	// The menu bar takes the top row of the area available to the windows and
+	// is drawn last, above the windows. Centered windows stay centered on the
+	// screen.
+	top := y
+	if wm.menuBar != nil {
+		if wm.IsFullScreen() {
+			wm.menuBar.SetRect(0, y, width+1, 1)
+		} else {
+			wm.menuBar.SetRect(x, y, width, 1)
+		}
+		defer wm.menuBar.Draw(screen)
+
+		y++
+		height--
+	}
+
 	var hasFullScreen bool
 	for _, w := range wm.windows {
 		if !w.fullscreen || !w.IsVisible() {
@@ -126,15 +162,19 @@ func (wm *WindowManager) Draw(screen tcell.Screen) {
 			continue
 		}
 
+		windowY := y + w.y
 		if w.IsCentered() {
 			sw, sh := screen.Size()
 			ww, wh := w.GetSize()
 			w.x, w.y = sw/2-ww/2, sh/2-wh/2
+			if windowY = top + w.y; windowY < y {
+				windowY = y
+			}
 		}
 
 		marginTop, marginRight, marginBottom, marginLeft := w.GetMarginBorder()
 		w.SetBorder(true)
-		w.SetRect(x+w.x+marginLeft, y+w.y+marginTop, w.width-marginRight, w.height-marginBottom)
+		w.SetRect(x+w.x+marginLeft, windowY+marginTop, w.width-marginRight, w.height-marginBottom)
 
 		w.Draw(screen)
 
@@ -147,12 +187,22 @@ func (wm *WindowManager) Draw(screen tcell.Screen) {
 // MouseHandler returns the mouse handler for this primitive.
 func (wm *WindowManager) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 	return wm.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		// Open menus are above the windows.
+		if wm.menuBar != nil {
+			if consumed, capture = wm.menuBar.MouseHandler()(action, event, setFocus); consumed {
+				return consumed, capture
+			}
+		}
+
 		if !wm.InRect(event.Position()) {
 			return false, nil
 		}
 
 		if action == MouseMove {
 			x, y, _, _ := wm.GetInnerRect()
+			if wm.menuBar != nil {
+				y++
+			}
 			mouseX, mouseY := event.Position()
 
 			for _, w := range wm.windows {
@@ -240,3 +290,22 @@ func (wm *WindowManager) MouseHandler() func(action MouseAction, event *tcell.Ev
 		return consumed, nil
 	})
 }
+
+// ApplyTheme replaces the colors of the previous theme with the colors of the
+// new theme, for the window manager and all of its windows. Colors which were
+// set explicitly are kept.
+func (wm *WindowManager) ApplyTheme(previous, theme *Theme) {
+	wm.Box.ApplyTheme(previous, theme)
+
+	wm.RLock()
+	windows := append([]*Window(nil), wm.windows...)
+	menuBar := wm.menuBar
+	wm.RUnlock()
+
+	for _, w := range windows {
+		w.ApplyTheme(previous, theme)
+	}
+	if menuBar != nil {
+		menuBar.ApplyTheme(previous, theme)
+	}
+}